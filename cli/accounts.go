@@ -0,0 +1,560 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/fatih/color"
+	"github.com/tcnksm/go-input"
+	"github.com/urfave/cli"
+
+	"github.com/SummerCash/go-summercash/accounts"
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/crypto"
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/common"
+	walletAccounts "github.com/SummerCash/summercash-wallet-server/accounts"
+)
+
+var (
+	// ErrNoAddress is an error definition describing a command invoked without an account address.
+	ErrNoAddress = errors.New("an account address must be provided")
+
+	// ErrAccountAlreadyExists is an error definition describing an attempt to import an account that already exists.
+	ErrAccountAlreadyExists = errors.New("account already exists")
+
+	// ErrInvalidPrivateKey is an error definition describing a private key file that could not be parsed.
+	ErrInvalidPrivateKey = errors.New("invalid private key file")
+
+	// ErrAccountHasRole is an error definition describing an attempt to delete an account that holds a genesis role.
+	ErrAccountHasRole = errors.New("account holds a genesis role; pass --force to delete it anyway")
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// SetupAccountsCommand sets up the accounts CLI command.
+func (app *CLI) SetupAccountsCommand() {
+	dataDirFlag := cli.StringFlag{
+		Name:        "data-dir, data",                            // Set name
+		Value:       common.DataDir,                              // Set value
+		Usage:       "path of the network to manage accounts in", // Set usage
+		Destination: &common.DataDir,                             // Set destination
+	} // Init data dir flag
+
+	(*app).App.Commands = append((*app).App.Commands, cli.Command{
+		Name:    "accounts",                          // Set name
+		Aliases: []string{"account", "a"},            // Set aliases
+		Usage:   "manage a network's local accounts", // Set usage
+		Subcommands: []cli.Command{
+			{
+				Name:    "list",                                                      // Set name
+				Aliases: []string{"ls"},                                              // Set aliases
+				Usage:   "list local accounts, along with their roles and chain IDs", // Set usage
+				Action:  app.listAccounts,                                            // Set action
+				Flags:   []cli.Flag{dataDirFlag},                                     // Set flags
+			},
+			{
+				Name:   "new",                          // Set name
+				Usage:  "generate a new local account", // Set usage
+				Action: app.createAccount,              // Set action
+				Flags:  []cli.Flag{dataDirFlag},        // Set flags
+			},
+			{
+				Name:      "import",                                             // Set name
+				Usage:     "import a local account from a PEM private key file", // Set usage
+				ArgsUsage: "PRIVATE_KEY_FILE",                                   // Set args usage
+				Action:    app.importAccount,                                    // Set action
+				Flags:     []cli.Flag{dataDirFlag},                              // Set flags
+			},
+			{
+				Name:      "export",                                      // Set name
+				Usage:     "export a local account's private key as PEM", // Set usage
+				ArgsUsage: "ADDRESS",                                     // Set args usage
+				Action:    app.exportAccount,                             // Set action
+				Flags: []cli.Flag{
+					dataDirFlag,
+					cli.StringFlag{
+						Name:  "out, o",                                                                            // Set name
+						Usage: "new file to write the private key to, readable only by its owner (default stdout)", // Set usage
+					},
+				},
+			},
+			{
+				Name:      "delete",                               // Set name
+				Aliases:   []string{"rm"},                         // Set aliases
+				Usage:     "delete a local account's private key", // Set usage
+				ArgsUsage: "ADDRESS",                              // Set args usage
+				Action:    app.deleteAccount,                      // Set action
+				Flags: []cli.Flag{
+					dataDirFlag,
+					cli.BoolFlag{
+						Name:  "yes, y",                     // Set name
+						Usage: "don't ask for confirmation", // Set usage
+					},
+					cli.BoolFlag{
+						Name:  "force",                                              // Set name
+						Usage: "delete the account even if it holds a genesis role", // Set usage
+					},
+				},
+			},
+		},
+	})
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// listAccounts handles the accounts list command.
+func (app *CLI) listAccounts(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	addresses, err := accounts.GetAllAccounts() // Get local accounts
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	roles, err := accountRoles() // Get account roles
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	sort.Strings(addresses) // Sort addresses
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) // Init table writer
+
+	fmt.Fprintln(writer, "ADDRESS\tROLES\tCHAIN ID") // Write header
+
+	for _, addressString := range addresses { // Iterate through addresses
+		chainID := "-" // Init chain ID buffer
+
+		if address, err := summercashCommon.StringToAddress(addressString); err == nil { // Check valid address
+			if chain, err := types.ReadChainFromMemory(address); err == nil { // Check has chain
+				chainID = chain.ID.String() // Set chain ID
+			}
+		}
+
+		accountRoles := strings.Join(roles[addressString], ",") // Get roles
+
+		if accountRoles == "" { // Check no roles
+			accountRoles = "-" // Set no roles
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", addressString, accountRoles, chainID) // Write account
+	}
+
+	return writer.Flush() // Flush table
+}
+
+// createAccount handles the accounts new command.
+func (app *CLI) createAccount(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	account, err := newAccount(localNetworkID(), rand.Reader) // Generate account
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	fmt.Println(account.Address.String()) // Log address
+
+	return nil // No error occurred, return nil
+}
+
+// importAccount handles the accounts import command.
+func (app *CLI) importAccount(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	if c.Args().First() == "" { // Check no private key file
+		return fmt.Errorf("%s: a private key file must be provided", ErrInvalidPrivateKey) // Return error
+	}
+
+	data, err := ioutil.ReadFile(c.Args().First()) // Read private key file
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	privateKey, err := parsePrivateKey(data) // Parse private key
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	account, err := accounts.AccountFromKey(privateKey) // Get account from key
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if _, err := os.Stat(accountPath(account.Address)); err == nil { // Check account already exists
+		return fmt.Errorf("%s: %s", ErrAccountAlreadyExists, account.Address.String()) // Return error
+	}
+
+	err = account.WriteToMemory() // Write account to persistent memory
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if _, err := types.ReadChainFromMemory(account.Address); err != nil { // Check has no chain
+		chain := &types.Chain{ // Init chain
+			Account:      account.Address,
+			Transactions: []*types.Transaction{},
+			NetworkID:    localNetworkID(),
+		}
+
+		(*chain).ID = summercashCommon.NewHash(crypto.Sha3(chain.Bytes())) // Set ID
+
+		err = chain.WriteToMemory() // Write chain to persistent memory
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+	}
+
+	fmt.Println(account.Address.String()) // Log address
+
+	return nil // No error occurred, return nil
+}
+
+// exportAccount handles the accounts export command.
+func (app *CLI) exportAccount(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	address, err := addressArg(c) // Get address
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	account, err := accounts.ReadAccountFromMemory(address) // Read account
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	marshaledPrivateKey, err := x509.MarshalECPrivateKey(account.PrivateKey) // Marshal private key
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	encoded := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: marshaledPrivateKey}) // Encode private key
+
+	if out := c.String("out"); out != "" { // Check has output file
+		file, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) // Create file, refusing to reuse an existing one
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		_, err = file.Write(encoded) // Write private key
+
+		if closeErr := file.Close(); err == nil { // Close file
+			err = closeErr // Set error
+		}
+
+		return err // Return error
+	}
+
+	_, err = os.Stdout.Write(encoded) // Write private key to stdout
+
+	return err // Return error
+}
+
+// deleteAccount handles the accounts delete command.
+func (app *CLI) deleteAccount(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	address, err := addressArg(c) // Get address
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if _, err := os.Stat(accountPath(address)); err != nil { // Check account doesn't exist
+		return err // Return found error
+	}
+
+	roles, err := accountRoles() // Get account roles
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if len(roles[address.String()]) > 0 && !c.Bool("force") { // Check holds role
+		return fmt.Errorf("%s (%s)", ErrAccountHasRole, strings.Join(roles[address.String()], ", ")) // Return error
+	}
+
+	if !c.Bool("yes") { // Check should confirm
+		yellow := color.New(color.FgYellow).PrintfFunc() // Init yellow
+
+		yellow("The private key of %s will be deleted permanently. Do you want to continue? (Default is no)", address.String()) // Print
+
+		shouldContinue, err := app.InputConfig.Ask("", &input.Options{
+			Default:     "no", // Set default
+			Required:    true, // Make required
+			HideOrder:   true, // Hide extra question
+			HideDefault: true, // Hide default
+		})
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if shouldContinue = strings.Replace(shouldContinue, "\r", "", 1); shouldContinue != "yes" && shouldContinue != "y" { // Check not confirmed
+			return nil // Stop execution
+		}
+	}
+
+	return os.Remove(accountPath(address)) // Remove account
+}
+
+// accountRole represents the genesis role and name of an account, as recorded when the network was created.
+type accountRole struct {
+	Address string `json:"address"`        // Address
+	Role    string `json:"role,omitempty"` // Role
+	Name    string `json:"name,omitempty"` // Name
+}
+
+// String formats an account role as role or role:name.
+func (role *accountRole) String() string {
+	if role.Name == "" || role.Name == role.Role { // Check name implied by role
+		return role.Role // Return role
+	}
+
+	if role.Role == "" { // Check no role
+		return "alloc:" + role.Name // Return name
+	}
+
+	return role.Role + ":" + role.Name // Return role and name
+}
+
+// accountRoles gets the genesis roles (e.g. genesis, faucet:dev, or alloc) held by each account in the
+// current data dir, keyed by address.
+func accountRoles() (map[string][]string, error) {
+	roles := make(map[string][]string) // Init roles buffer
+
+	recorded, err := readRoles() // Read recorded roles
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	for _, role := range recorded { // Iterate through recorded roles
+		roles[role.Address] = append(roles[role.Address], role.String()) // Append role
+	}
+
+	if recorded == nil { // Check network created before roles were recorded
+		faucet, err := readWalletAccount("faucet") // Read faucet wallet account
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		if faucet != nil { // Check has faucet
+			roles[faucet.Address.String()] = []string{"faucet"} // Set faucet role
+		}
+	}
+
+	chainConfig, err := config.ReadChainConfigFromMemory() // Read chain config
+
+	if err != nil { // Check no chain config
+		return roles, nil // Return recorded roles
+	}
+
+	for i, address := range chainConfig.AllocAddresses { // Iterate through alloc addresses
+		if len(roles[address.String()]) > 0 { // Check has recorded role
+			continue // Continue
+		}
+
+		if i == 0 { // Check is genesis
+			roles[address.String()] = []string{"genesis"} // Set genesis role
+		} else {
+			roles[address.String()] = []string{"alloc"} // Set alloc role
+		}
+	}
+
+	return roles, nil // Return roles
+}
+
+// recordRole records the genesis role and name of the account at a given address in the current data dir, replacing
+// any role previously recorded for that address.
+func recordRole(address summercashCommon.Address, role string, name string) error {
+	roles, err := readRoles() // Read recorded roles
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	recorded := false // Init recorded buffer
+
+	for _, existing := range roles { // Iterate through recorded roles
+		if existing.Address == address.String() { // Check same account
+			existing.Role, existing.Name, recorded = role, name, true // Update role
+		}
+	}
+
+	if !recorded { // Check no existing role
+		roles = append(roles, &accountRole{Address: address.String(), Role: role, Name: name}) // Append role
+	}
+
+	marshaled, err := json.MarshalIndent(roles, "", "  ") // Marshal roles
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	err = summercashCommon.CreateDirIfDoesNotExist(filepath.Dir(rolesPath())) // Create config dir
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return ioutil.WriteFile(rolesPath(), marshaled, 0644) // Write roles
+}
+
+// readRoles reads the genesis roles recorded in the current data dir, returning nil if none have been recorded.
+func readRoles() ([]*accountRole, error) {
+	data, err := ioutil.ReadFile(rolesPath()) // Read roles
+
+	if os.IsNotExist(err) { // Check no roles
+		return nil, nil // No roles
+	} else if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	var roles []*accountRole // Init roles buffer
+
+	err = json.Unmarshal(data, &roles) // Unmarshal roles
+
+	return roles, err // Return roles
+}
+
+// rolesPath gets the path of the file the genesis roles of the accounts in the current data dir are recorded in.
+func rolesPath() string {
+	return filepath.FromSlash(fmt.Sprintf("%s/config/roles.json", common.DataDir)) // Return path
+}
+
+// readWalletAccount reads the wallet account with a given name from the wallet database in the current data dir,
+// returning nil if the database or account doesn't exist.
+func readWalletAccount(name string) (*walletAccounts.Account, error) {
+	dbPath := filepath.FromSlash(fmt.Sprintf("%s/db/smc_db.db", common.DataDir)) // Get DB path
+
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) { // Check no DB
+		return nil, nil // No wallet account
+	}
+
+	database, err := bolt.Open(dbPath, 0644, &bolt.Options{Timeout: 5 * time.Second, ReadOnly: true}) // Open DB with timeout
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	defer database.Close() // Close DB
+
+	var account *walletAccounts.Account // Init account buffer
+
+	err = database.View(func(tx *bolt.Tx) error {
+		accountsBucket := tx.Bucket([]byte("accounts")) // Get accounts bucket
+
+		if accountsBucket == nil { // Check no accounts bucket
+			return nil // No wallet account
+		}
+
+		accountBytes := accountsBucket.Get(crypto.Sha3([]byte(name))) // Get account
+
+		if accountBytes == nil { // Check no account
+			return nil // No wallet account
+		}
+
+		account, err = walletAccounts.AccountFromBytes(accountBytes) // Deserialize account
+
+		return err // Return error
+	}) // Read account
+
+	return account, err // Return account
+}
+
+// parsePrivateKey parses a PEM-encoded EC private key.
+func parsePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(bytes.TrimSpace(data)) // Decode PEM
+
+	if block == nil { // Check not PEM
+		return nil, fmt.Errorf("%s: not PEM-encoded", ErrInvalidPrivateKey) // Return error
+	}
+
+	if privateKey, err := x509.ParseECPrivateKey(block.Bytes); err == nil { // Check is EC private key
+		return privateKey, nil // Return private key
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes) // Parse PKCS #8 private key
+
+	if err != nil { // Check for errors
+		return nil, fmt.Errorf("%s: %s", ErrInvalidPrivateKey, err) // Return error
+	}
+
+	privateKey, ok := key.(*ecdsa.PrivateKey) // Get EC private key
+
+	if !ok { // Check not EC private key
+		return nil, fmt.Errorf("%s: not an EC private key", ErrInvalidPrivateKey) // Return error
+	}
+
+	return privateKey, nil // Return private key
+}
+
+// addressArg parses the address passed as the first argument to a command.
+func addressArg(c *cli.Context) (summercashCommon.Address, error) {
+	if len(c.Args().First()) < 2 { // Check no address
+		return summercashCommon.Address{}, ErrNoAddress // Return error
+	}
+
+	return summercashCommon.StringToAddress(c.Args().First()) // Parse address
+}
+
+// accountPath gets the path of the keystore file of an account with a given address.
+func accountPath(address summercashCommon.Address) string {
+	return filepath.FromSlash(fmt.Sprintf("%s/keystore/account_%s.json", common.DataDir, address.String())) // Return path
+}
+
+// localNetworkID gets the network ID of the network in the current data dir, or 0 if it has no chain config.
+func localNetworkID() uint {
+	chainConfig, err := config.ReadChainConfigFromMemory() // Read chain config
+
+	if err != nil { // Check for errors
+		return 0 // No network ID
+	}
+
+	return chainConfig.NetworkID // Return network ID
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,203 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SummerCash/go-summercash/accounts"
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/genesis"
+	"github.com/SummerCash/puppet/internal/fixtures"
+)
+
+/* BEGIN INTERNAL METHODS TESTS */
+
+// TestAccountsCommands tests the accounts list, new, export, delete, and import commands.
+func TestAccountsCommands(t *testing.T) {
+	dataDir, cleanup := testDataDir(t, "unused") // Make parent dir
+
+	defer cleanup() // Clean up
+
+	dataDir = filepath.Join(filepath.Dir(dataDir), "accounts") // Get data dir
+
+	if err := testRun(t, "create", "--data-dir", dataDir, "-y", "--network-id", "5", "--inflation", "0.1", "--issuance", "1000"); err != nil { // Create network
+		t.Fatal(err) // Panic
+	}
+
+	out, err := testOutput(t, "accounts", "new", "--data-dir", dataDir) // Generate account
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	address := strings.TrimSpace(out) // Get address
+
+	list, err := testOutput(t, "accounts", "list", "--data-dir", dataDir) // List accounts
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if lines := strings.Split(strings.TrimSpace(list), "\n"); len(lines) != 3 || !strings.Contains(list, address+"  -") || !strings.Contains(list, "  "+genesis.RoleGenesis+"  ") { // Check invalid list
+		t.Fatalf("expected the genesis account and %s to be listed, found:\n%s", address, list) // Panic
+	}
+
+	keyPath := filepath.Join(filepath.Dir(dataDir), "key.pem") // Get key path
+
+	if err = testRun(t, "accounts", "export", address, "--data-dir", dataDir, "--out", keyPath); err != nil { // Export account
+		t.Fatal(err) // Panic
+	}
+
+	testCheckPrivateKey(t, keyPath, address) // Check exported key
+
+	if err = testRun(t, "accounts", "export", address, "--data-dir", dataDir, "--out", keyPath); err == nil || !os.IsExist(err) { // Check existing file overwritten
+		t.Fatalf("expected exporting into an existing file to fail, found %v", err) // Panic
+	}
+
+	if err = testRun(t, "accounts", "delete", address, "--data-dir", dataDir, "-y"); err != nil { // Delete account
+		t.Fatal(err) // Panic
+	}
+
+	if _, err = os.Stat(filepath.Join(dataDir, "keystore", "account_"+address+".json")); !os.IsNotExist(err) { // Check not deleted
+		t.Fatalf("expected the keystore of %s to be deleted, found %v", address, err) // Panic
+	}
+
+	if out, err = testOutput(t, "accounts", "import", keyPath, "--data-dir", dataDir); err != nil || strings.TrimSpace(out) != address { // Import account
+		t.Fatalf("expected %s to be imported, found %q (%v)", address, out, err) // Panic
+	}
+
+	if err = testRun(t, "accounts", "import", keyPath, "--data-dir", dataDir); err == nil || !strings.HasPrefix(err.Error(), ErrAccountAlreadyExists.Error()) { // Check imported twice
+		t.Fatalf("expected %v, found %v", ErrAccountAlreadyExists, err) // Panic
+	}
+
+	var genesisAddress string // Init genesis address buffer
+
+	for _, line := range strings.Split(list, "\n") { // Iterate through listed accounts
+		if fields := strings.Fields(line); len(fields) == 3 && fields[1] == genesis.RoleGenesis { // Check is genesis account
+			genesisAddress = fields[0] // Set genesis address
+		}
+	}
+
+	if err = testRun(t, "accounts", "delete", genesisAddress, "--data-dir", dataDir, "-y"); err == nil || !strings.HasPrefix(err.Error(), ErrAccountHasRole.Error()) { // Check genesis account deleted
+		t.Fatalf("expected %v, found %v", ErrAccountHasRole, err) // Panic
+	}
+}
+
+// TestRecordRole tests that the recordRole() helper method keeps a single record per account.
+func TestRecordRole(t *testing.T) {
+	dataDir, cleanup := testDataDir(t, "roles") // Make network
+
+	defer cleanup() // Clean up
+
+	common.DataDir = dataDir // Set data dir
+
+	treasury := fixtures.Address(fixtures.Sender)     // Get treasury address
+	validator := fixtures.Address(fixtures.Recipient) // Get validator address
+
+	for _, record := range []struct {
+		address summercashCommon.Address // Address
+		role    string                   // Role
+		name    string                   // Name
+	}{
+		{treasury, genesis.RoleTreasury, "reserve"},
+		{validator, genesis.RoleValidator, "node-1"},
+		{treasury, genesis.RoleTreasury, "treasury"},
+	} { // Iterate through records
+		if err := recordRole(record.address, record.role, record.name); err != nil { // Record role
+			t.Fatal(err) // Panic
+		}
+	}
+
+	roles, err := readRoles() // Read roles
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if len(roles) != 2 { // Check duplicate records
+		t.Fatalf("expected 2 recorded roles, found %d", len(roles)) // Panic
+	}
+
+	if roles[0].Address != treasury.String() || roles[0].Name != "treasury" { // Check not updated in place
+		t.Fatalf("expected the treasury record to be renamed in place, found %+v", roles[0]) // Panic
+	}
+
+	if roles[1].Address != validator.String() || roles[1].Role != genesis.RoleValidator { // Check invalid validator record
+		t.Fatalf("expected the validator record to be kept, found %+v", roles[1]) // Panic
+	}
+}
+
+/* END INTERNAL METHODS TESTS */
+
+/* BEGIN INTERNAL METHODS */
+
+// testOutput runs puppet with a given set of arguments, like testRun, returning what it writes to stdout.
+func testOutput(t *testing.T, args ...string) (string, error) {
+	reader, writer, err := os.Pipe() // Init pipe
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	output := make(chan []byte) // Init output channel
+
+	go func() {
+		data, _ := ioutil.ReadAll(reader) // Read output
+
+		output <- data // Send output
+	}() // Read output
+
+	stdout := os.Stdout // Get stdout
+
+	os.Stdout = writer // Redirect stdout
+
+	err = testRun(t, args...) // Run command
+
+	os.Stdout = stdout // Reset stdout
+
+	writer.Close() // Close pipe
+
+	return string(<-output), err // Return output
+}
+
+// testCheckPrivateKey checks that the file at a given path is a private key file readable only by its owner, holding
+// the key of a given address.
+func testCheckPrivateKey(t *testing.T, path string, address string) {
+	info, err := os.Stat(path) // Stat key file
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if info.Mode().Perm() != 0600 { // Check readable by others
+		t.Fatalf("expected the key file to be readable only by its owner, found %s", info.Mode()) // Panic
+	}
+
+	data, err := ioutil.ReadFile(path) // Read key file
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if block, _ := pem.Decode(data); block == nil || block.Type != "EC PRIVATE KEY" { // Check invalid PEM label
+		t.Fatalf("expected a SEC 1 EC PRIVATE KEY PEM block, found:\n%s", data) // Panic
+	}
+
+	privateKey, err := parsePrivateKey(data) // Parse private key
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if account, err := accounts.AccountFromKey(privateKey); err != nil || account.Address.String() != address { // Check key of another account
+		t.Fatalf("expected the key of %s, found %v (%v)", address, account, err) // Panic
+	}
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,91 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+/* BEGIN INTERNAL METHODS */
+
+// readLocalChains reads every chain in the current data dir, ordered by account address. Chains that can't be read
+// are returned as chain errors rather than failing the whole read.
+func readLocalChains() ([]*types.Chain, []*chainError, error) {
+	names, err := localChainNames() // Get all local chains
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	sort.Strings(names) // Sort by address
+
+	var chains []*types.Chain     // Init chains buffer
+	var chainErrors []*chainError // Init chain errors buffer
+
+	for _, name := range names { // Iterate through chains
+		chain, err := readLocalChain(name) // Read chain
+
+		if err != nil { // Check for errors
+			chainErrors = append(chainErrors, &chainError{Chain: name, Err: err}) // Record error
+
+			continue // Continue
+		}
+
+		chains = append(chains, chain) // Append chain
+	}
+
+	return chains, chainErrors, nil // Return chains
+}
+
+// localChainNames gets the account addresses of every chain in the current data dir, recovering from files in the
+// chain directory that types.GetAllLocalizedChains can't parse.
+func localChainNames() (names []string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil { // Check invalid file name
+			names, err = nil, fmt.Errorf("could not list the chains in %s; every file in it must be named chain_ADDRESS.json", chainDir()) // Set error
+		}
+	}()
+
+	return types.GetAllLocalizedChains() // Return chains
+}
+
+// readLocalChain reads the chain of the account with a given address in the current data dir, recovering from
+// chain files the SummerCash decoder can't handle.
+func readLocalChain(name string) (chain *types.Chain, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil { // Check invalid chain file
+			chain, err = nil, fmt.Errorf("invalid chain file: %v", recovered) // Set error
+		}
+	}()
+
+	if len(name) < 2 { // Check too short to parse
+		return nil, fmt.Errorf("invalid chain address %q", name) // Return error
+	}
+
+	address, err := summercashCommon.StringToAddress(name) // Parse address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return types.ReadChainFromMemory(address) // Read chain
+}
+
+// reportChainErrors writes the chains that could not be read to a given writer.
+func reportChainErrors(writer io.Writer, chainErrors []*chainError) {
+	if len(chainErrors) == 0 { // Check no errors
+		return // Nothing to report
+	}
+
+	fmt.Fprintf(writer, "%d chains could not be read:\n", len(chainErrors)) // Log errors
+
+	for _, chainErr := range chainErrors { // Iterate through errors
+		fmt.Fprintf(writer, "  %s: %s\n", chainErr.Chain, chainErr.Err) // Log error
+	}
+}
+
+/* END INTERNAL METHODS */
@@ -4,8 +4,8 @@ package cli
 import (
 	"os"
 
-	"github.com/urfave/cli"
 	"github.com/tcnksm/go-input"
+	"github.com/urfave/cli"
 )
 
 // CLI defines a command-line-interface.
@@ -7,7 +7,9 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/big"
 	"os"
@@ -30,10 +32,34 @@ import (
 	"github.com/SummerCash/go-summercash/crypto"
 	"github.com/SummerCash/go-summercash/types"
 	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/genesis"
+	"github.com/SummerCash/puppet/keystore"
 	walletAccounts "github.com/SummerCash/summercash-wallet-server/accounts"
 	walletCrypto "github.com/SummerCash/summercash-wallet-server/crypto"
 )
 
+// genesisSpec represents a parsed genesis file, along with any additional allocations
+// that must be made after the genesis account has been generated.
+type genesisSpec struct {
+	*genesis.Genesis // Genesis file
+
+	Extra genesis.Alloc // Additional allocations
+
+	Format genesis.Format // Genesis file format
+	Source []byte         // Raw genesis file
+}
+
+var (
+	// ErrCreationAborted is an error definition describing a network creation aborted by the user.
+	ErrCreationAborted = errors.New("network creation aborted")
+
+	// ErrMissingValues is an error definition describing a non-interactive network creation lacking required values.
+	ErrMissingValues = errors.New("missing required values for non-interactive network creation")
+
+	// seededGenesisTimestamp is the timestamp of every genesis transaction in a network created from a seed.
+	seededGenesisTimestamp = time.Unix(0, 0).UTC()
+)
+
 /* BEGIN EXPORTED METHODS */
 
 // SetupCreateCommand sets up the create CLI command.
@@ -65,6 +91,51 @@ func (app *CLI) SetupCreateCommand() {
 				Value: "",                                                                                                                      // Set value
 				Usage: "file to bootstrap network configuration creation from; can contain supply, network id, and inflation rate definitions", // Set usage
 			},
+			cli.StringFlag{
+				Name:  "genesis-format",                                                                           // Set name
+				Usage: "format of the genesis file (json, yaml, or toml); detected from its extension by default", // Set usage
+			},
+			cli.BoolFlag{
+				Name:  "non-interactive, yes, y",                                                           // Set name
+				Usage: "never prompt for input; all values must be provided via flags or the genesis file", // Set usage
+			},
+			cli.UintFlag{
+				Name:  "network-id",                        // Set name
+				Usage: "network ID to register network as", // Set usage
+			},
+			cli.Float64Flag{
+				Name:  "inflation",                     // Set name
+				Usage: "inflation rate of the network", // Set usage
+			},
+			cli.StringFlag{
+				Name:  "issuance",                                        // Set name
+				Usage: "number of coins to issue to the genesis account", // Set usage
+			},
+			cli.BoolFlag{
+				Name:  "faucet",                       // Set name
+				Usage: "enable the SummerCash faucet", // Set usage
+			},
+			cli.StringFlag{
+				Name:  "faucet-amount",                             // Set name
+				Usage: "number of coins to allocate to the faucet", // Set usage
+			},
+			cli.StringFlag{
+				Name:   "faucet-passphrase",                              // Set name
+				Usage:  "passphrase to encrypt the faucet keystore with", // Set usage
+				EnvVar: "PUPPET_FAUCET_PASSPHRASE",                       // Set env var
+			},
+			cli.StringSliceFlag{
+				Name:  "alloc",                                                    // Set name
+				Usage: "additional genesis allocation in the form address=amount", // Set usage
+			},
+			cli.BoolFlag{
+				Name:  "overwrite",                                                                               // Set name
+				Usage: "delete an existing network in the data dir instead of moving it to a timestamped backup", // Set usage
+			},
+			cli.StringFlag{
+				Name:  "seed",                                                                                       // Set name
+				Usage: "derive all generated keys and signatures from a seed, making network creation reproducible", // Set usage
+			},
 		},
 	})
 }
@@ -79,7 +150,21 @@ func (app *CLI) createNetwork(c *cli.Context) error {
 
 	summercashCommon.Silent = true // Silence logsconfigPath
 
-	if c.String("data-dir") == common.GetDefaultDataPath() { // Check data directory not specified
+	nonInteractive := c.Bool("non-interactive") // Check should not prompt for input
+
+	genesisSpec, err := parseGenesisFile(c) // Parse genesis file
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if nonInteractive { // Check cannot prompt for missing values
+		if missing := missingCreateValues(c, genesisSpec); len(missing) > 0 { // Check has missing values
+			return fmt.Errorf("%s: %s", ErrMissingValues, strings.Join(missing, ", ")) // Return missing values
+		}
+	}
+
+	if c.String("data-dir") == common.GetDefaultDataPath() && !nonInteractive { // Check data directory not specified
 		var dataDir, err = app.InputConfig.Ask("Where would you like your new network to be stored?", &input.Options{
 			Default:   common.GetDefaultDataPath(), // Set default
 			Required:  false,                       // Make optional
@@ -97,7 +182,7 @@ func (app *CLI) createNetwork(c *cli.Context) error {
 		common.DataDir = dataDir // Set data dir
 	}
 
-	if _, err := os.Stat(common.DataDir); !os.IsNotExist(err) { // Check network already exists
+	if _, err := os.Stat(common.DataDir); !os.IsNotExist(err) && !nonInteractive { // Check network already exists
 		yellow := color.New(color.FgYellow).PrintfFunc() // Init yellow
 
 		yellow("It looks like a network already exists in %s. Do you want to continue? (Default is no)", common.DataDir) // Print
@@ -114,63 +199,196 @@ func (app *CLI) createNetwork(c *cli.Context) error {
 		}
 
 		if shouldContinue == "\r" || shouldContinue == "" { // Check no value set
-			os.Exit(1) // Abort execution
+			return ErrCreationAborted // Abort execution
 		} else if shouldContinue == "no" {
-			os.Exit(0) // Stop execution
+			return nil // Stop execution
 		}
+	}
+
+	var config *config.ChainConfig // Init config buffer
+
+	backup, err := replaceDataDir(common.DataDir, c.Bool("overwrite"), func() (err error) {
+		config, err = app.buildNetwork(c, genesisSpec) // Build network
+
+		return err // Return error
+	}) // Build network in place of any existing one
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if backup != "" { // Check made backup
+		color.Yellow(fmt.Sprintf("\nYour previous network has been moved to %s.", backup)) // Log backup
+	}
+
+	color.Green(fmt.Sprintf("\nYou're all good to go! %s Your new SummerCash network has been created in %s. Try running go-summercash --network puppet_%d to get started.", emoji.Sprint(":clap:"), common.DataDir, config.NetworkID)) // Log success
+
+	summercashCommon.Silent = false // Enable logs
+
+	return nil // No error occurred, return nil
+}
+
+// buildNetwork writes the config, accounts, and chains of a new network to the current data dir.
+func (app *CLI) buildNetwork(c *cli.Context, genesisSpec *genesisSpec) (*config.ChainConfig, error) {
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
 
-		d, err := os.Open(common.DataDir) // Open data dir
+	if configPath := c.String("config-path"); configPath != "" { // Check has existing configuration file.
+		err := constructNetwork(c, configPath) // Construct network
 
 		if err != nil { // Check for errors
-			return err // Return found error
+			return nil, err // Return found error
 		}
 
-		defer d.Close() // Close dir
+		return readChainConfig(filepath.FromSlash(fmt.Sprintf("%s/config/config.json", configPath))) // Return config
+	}
+
+	config, err := app.makeChainConfig(c, genesisSpec) // Make chain config
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	err = config.WriteToMemory() // Write config to persistent memory
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	err = constructNetwork(c, c.String("data-path")) // Construct network
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
 
-		names, err := d.Readdirnames(-1) // Get directory files
+	if genesisSpec.Source != nil { // Check has genesis file
+		err = ioutil.WriteFile(genesisSourcePath(genesisSpec.Format), genesisSpec.Source, 0644) // Keep copy of genesis file
 
 		if err != nil { // Check for errors
-			return err // Return found error
+			return nil, err // Return found error
 		}
+	}
 
-		for _, name := range names { // Iterate through files
-			err = os.RemoveAll(filepath.Join(common.DataDir, name)) // Remove file
+	return config, nil // No error occurred, return nil
+}
 
-			if err != nil { // Check for errors
-				return err // Return found error
-			}
-		}
+// replaceDataDir builds a new network in a staging directory next to a given data dir, and swaps it into place once it
+// has been built. While build runs, the data dir points at the staging directory. If build fails, the staging
+// directory is removed, and any existing network in the data dir is left untouched. The path of the backup made of the
+// existing network, if any, is returned.
+func replaceDataDir(target string, overwrite bool, build func() error) (string, error) {
+	staging, err := stageDataDir(target) // Make staging dir
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
 	}
 
-	summercashCommon.DataDir = common.DataDir // Set smc data dir
+	common.DataDir = staging // Build network in staging dir
 
-	if configPath := c.String("config-path"); configPath != "" { // Check has existing configuration file.
-		return constructNetwork(c, configPath) // Construct network
+	err = build() // Build network
+
+	common.DataDir = target // Reset data dir
+
+	summercashCommon.DataDir = target // Reset smc data dir
+
+	if err != nil { // Check for errors
+		os.RemoveAll(staging) // Roll back
+
+		return "", err // Return found error
 	}
 
-	config, err := app.parseGenesisFile(c.String("genesis-path")) // Parse genesis file
+	backup, err := commitDataDir(staging, target, overwrite) // Swap network into place
 
 	if err != nil { // Check for errors
-		return err // Return found error
+		os.RemoveAll(staging) // Roll back
+
+		return "", err // Return found error
 	}
 
-	err = config.WriteToMemory() // Write config to persistent memory
+	return backup, nil // Return backup path
+}
+
+// stageDataDir makes an empty staging directory next to a given data dir, such that the
+// staging directory can later be renamed into place atomically.
+func stageDataDir(target string) (string, error) {
+	target = filepath.Clean(target) // Clean path
+
+	err := os.MkdirAll(filepath.Dir(target), 0755) // Make parent dir
 
 	if err != nil { // Check for errors
-		return err // Return found error
+		return "", err // Return found error
 	}
 
-	err = constructNetwork(c, c.String("data-path")) // Construct network
+	return ioutil.TempDir(filepath.Dir(target), fmt.Sprintf(".%s.staging-", filepath.Base(target))) // Make staging dir
+}
+
+// commitDataDir swaps a staged network into place at a given data dir. If a network already exists in the data dir,
+// it is deleted when overwrite is set, and moved to a timestamped backup otherwise. The path of the backup, if any, is returned.
+func commitDataDir(staging string, target string, overwrite bool) (string, error) {
+	target = filepath.Clean(target) // Clean path
+
+	if err := os.Chmod(staging, 0755); err != nil { // Make staged network readable like any other data dir
+		return "", err // Return found error
+	}
+
+	if _, err := os.Stat(target); os.IsNotExist(err) { // Check no existing network
+		return "", os.Rename(staging, target) // Move staged network into place
+	} else if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	previous := fmt.Sprintf("%s.old", staging) // Move next to staging dir, for removal
+
+	if !overwrite { // Check should back up existing network
+		var err error // Init error buffer
+
+		previous, err = backupDataDirPath(target, time.Now()) // Get backup path
+
+		if err != nil { // Check for errors
+			return "", err // Return found error
+		}
+	}
+
+	err := os.Rename(target, previous) // Move existing network out of the way
 
 	if err != nil { // Check for errors
-		return err // Return found error
+		return "", err // Return found error
 	}
 
-	color.Green(fmt.Sprintf("\nYou're all good to go! %s Your new SummerCash network has been created in %s. Try running go-summercash --network puppet_%d to get started.", emoji.Sprint(":clap:"), common.DataDir, config.NetworkID)) // Log success
+	err = os.Rename(staging, target) // Move staged network into place
 
-	summercashCommon.Silent = false // Enable logs
+	if err != nil { // Check for errors
+		if restoreErr := os.Rename(previous, target); restoreErr != nil { // Restore existing network
+			return "", fmt.Errorf("%s; the existing network could not be moved back either (%s), and is now in %s", err, restoreErr, previous) // Return error
+		}
 
-	return nil // No error occurred, return nil
+		return "", err // Return found error
+	}
+
+	if overwrite { // Check should delete existing network
+		return "", os.RemoveAll(previous) // Remove existing network
+	}
+
+	return previous, nil // Return backup path
+}
+
+// backupDataDirPath gets an unused path to back up a given data dir to at a given time, in the form
+// <data-dir>.backup-<time>, followed by a counter if a backup was already made in the same second.
+func backupDataDirPath(target string, at time.Time) (string, error) {
+	base := fmt.Sprintf("%s.backup-%s", filepath.Clean(target), at.UTC().Format("20060102150405")) // Get backup path
+
+	for i := 0; ; i++ { // Find unused path
+		path := base // Init path
+
+		if i > 0 { // Check already backed up in the same second
+			path = fmt.Sprintf("%s-%d", base, i) // Add counter
+		}
+
+		if _, err := os.Stat(path); os.IsNotExist(err) { // Check unused
+			return path, nil // Return path
+		} else if err != nil { // Check for errors
+			return "", err // Return found error
+		}
+	}
 }
 
 // constructNetwork constructs a network, assuming all configs have been set.
@@ -221,7 +439,11 @@ func constructNetwork(c *cli.Context, dataPath string) error {
 		return err // Return found error
 	}
 
-	_, err = chain.MakeGenesis(config, genesisAccount.PrivateKey) // Make genesis
+	if seed := c.String("seed"); seed != "" { // Check has seed
+		_, err = makeSeededGenesis(chain, config, genesisAccount.PrivateKey) // Make reproducible genesis
+	} else {
+		_, err = chain.MakeGenesis(config, genesisAccount.PrivateKey) // Make genesis
+	}
 
 	if err != nil { // Check for errors
 		return err // Return found error
@@ -257,30 +479,65 @@ func readChainConfig(configPath string) (*config.ChainConfig, error) {
 	return buffer, nil // No error occurred, return read config
 }
 
-// parseGenesisFile parses a genesis file at a given genesisPath.
-func (app *CLI) parseGenesisFile(genesisPath string) (*config.ChainConfig, error) {
-	rawJSON := []byte("{}") // Init raw JSON buffer
-	var err error           // Init error buffer
+// parseGenesisFile parses and validates the genesis file at the genesis-path flag, applying any alloc
+// flags on top of its alloc. If the genesis file doesn't define an alloc, the alloc flags are returned
+// separately, as they can only be applied once the genesis account has been generated.
+func parseGenesisFile(c *cli.Context) (*genesisSpec, error) {
+	spec := &genesisSpec{
+		Genesis: &genesis.Genesis{Version: genesis.CurrentVersion}, // Set genesis
+		Format:  genesis.FormatJSON,                                // Set format
+	} // Init genesis buffer
 
-	if genesisPath != "" { // Check no genesis file
-		rawJSON, err = ioutil.ReadFile(genesisPath) // Read genesis file
+	if genesisPath := c.String("genesis-path"); genesisPath != "" { // Check has genesis file
+		var err error // Init error buffer
+
+		spec.Format, err = genesis.FormatFromPath(genesisPath) // Detect format
+
+		if formatName := c.String("genesis-format"); formatName != "" { // Check has format flag
+			spec.Format, err = genesis.ParseFormat(formatName) // Parse format
+		}
 
 		if err != nil { // Check for errors
-			return &config.ChainConfig{}, err // Return found error
+			return nil, err // Return found error
 		}
-	}
 
-	var readJSON map[string]interface{} // Init buffer
+		spec.Source, err = ioutil.ReadFile(genesisPath) // Read genesis file
 
-	err = json.Unmarshal(rawJSON, &readJSON) // Unmarshal to buffer
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		spec.Genesis, err = genesis.DecodeFormat(spec.Source, spec.Format) // Decode genesis
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	}
+
+	extraAlloc, err := genesis.ParseAllocFlags(c.StringSlice("alloc")) // Parse alloc flags
 
 	if err != nil { // Check for errors
-		return &config.ChainConfig{}, err // Return error
+		return nil, err // Return found error
 	}
 
-	alloc := make(map[string]*big.Float) // Init alloc map
+	if spec.Alloc == nil { // Check alloc must be generated
+		spec.Extra = extraAlloc // Set extra alloc
 
-	allocAddresses := []summercashCommon.Address{} // Init alloc address buffer
+		return spec, nil // Return genesis
+	}
+
+	spec.Alloc = spec.Alloc.Merge(extraAlloc) // Apply alloc flags
+
+	return spec, spec.Validate() // Return genesis
+}
+
+// makeChainConfig makes a chain config from a given genesis spec.
+// Values provided via flags take precedence over those in the genesis file. Values missing from both are
+// requested from the user.
+func (app *CLI) makeChainConfig(c *cli.Context, spec *genesisSpec) (*config.ChainConfig, error) {
+	var alloc map[string]*big.Float               // Init alloc map
+	var allocAddresses []summercashCommon.Address // Init alloc address buffer
+	var err error                                 // Init error buffer
 
 	networkID := uint(0) // Init network ID buffer
 
@@ -288,8 +545,10 @@ func (app *CLI) parseGenesisFile(genesisPath string) (*config.ChainConfig, error
 
 	chainID := summercashCommon.Hash{} // Init hash buffer
 
-	if readJSON["networkID"] != nil { // Check has network ID
-		networkID = uint(readJSON["networkID"].(float64)) // Set network ID
+	if c.IsSet("network-id") { // Check has network ID flag
+		networkID = c.Uint("network-id") // Set network ID
+	} else if spec.NetworkID != nil { // Check has network ID
+		networkID = *spec.NetworkID // Set network ID
 	} else {
 		networkIDString, err := app.InputConfig.Ask("What is this network's network ID?", &input.Options{
 			Default:   "1",   // Set default
@@ -297,6 +556,10 @@ func (app *CLI) parseGenesisFile(genesisPath string) (*config.ChainConfig, error
 			HideOrder: true,  // Hide extra question
 		})
 
+		if err != nil { // Check for errors
+			return &config.ChainConfig{}, err // Return error
+		}
+
 		if networkIDString == "\r" { // Check no ID
 			networkIDString = "1" // Set to default ID
 		}
@@ -312,22 +575,50 @@ func (app *CLI) parseGenesisFile(genesisPath string) (*config.ChainConfig, error
 		networkID = uint(networkIDInt) // Convert to uint
 	}
 
-	if readJSON["alloc"] != nil { // Check has alloc
-		alloc, allocAddresses, err = parseAlloc(readJSON) // Parse alloc
+	if spec.Alloc != nil { // Check has alloc
+		err = app.generateAlloc(c, networkID, spec.Alloc) // Generate alloc accounts
+
+		if err != nil { // Check for errors
+			return &config.ChainConfig{}, err // Return error
+		}
+
+		alloc, allocAddresses, err = spec.Alloc.Balances() // Parse alloc
+
+		if err != nil { // Check for errors
+			return &config.ChainConfig{}, err // Return error
+		}
+
+		err = recordVesting(spec.Alloc) // Record vesting schedules
 
 		if err != nil { // Check for errors
 			return &config.ChainConfig{}, err // Return error
 		}
 	} else { // User has not specified alloc in genesis
-		alloc, allocAddresses, err = app.requestAlloc(networkID) // Request alloc
+		alloc, allocAddresses, err = app.requestAlloc(c, networkID) // Request alloc
 
 		if err != nil { // Check for errors
 			return &config.ChainConfig{}, err // Return error
 		}
+
+		extraAlloc, extraAllocAddresses, err := spec.Extra.Balances() // Parse alloc flags
+
+		if err != nil { // Check for errors
+			return &config.ChainConfig{}, err // Return error
+		}
+
+		for _, address := range extraAllocAddresses { // Iterate through flag alloc addresses
+			if _, exists := alloc[address.String()]; !exists { // Check not already allocated
+				allocAddresses = append(allocAddresses, address) // Append address
+			}
+
+			alloc[address.String()] = extraAlloc[address.String()] // Set balance
+		}
 	}
 
-	if readJSON["inflation"] != nil { // Check has inflation rate
-		inflation = readJSON["inflation"].(float64) // Set inflation
+	if c.IsSet("inflation") { // Check has inflation flag
+		inflation = c.Float64("inflation") // Set inflation
+	} else if spec.Inflation != nil { // Check has inflation rate
+		inflation = *spec.Inflation // Set inflation
 	} else {
 		inflationString, err := app.InputConfig.Ask("What will this network's inflation rate be?", &input.Options{
 			Default:   "0.0", // Set default
@@ -335,6 +626,10 @@ func (app *CLI) parseGenesisFile(genesisPath string) (*config.ChainConfig, error
 			HideOrder: true,  // Hide extra question
 		})
 
+		if err != nil { // Check for errors
+			return &config.ChainConfig{}, err // Return found error
+		}
+
 		if inflationString == "\r" { // Check no value set
 			inflationString = "0.0" // Set inflation
 		}
@@ -352,6 +647,10 @@ func (app *CLI) parseGenesisFile(genesisPath string) (*config.ChainConfig, error
 
 	chainVersion := config.Version // Set chain version
 
+	if spec.ChainVersion != nil { // Check has chain version
+		chainVersion = *spec.ChainVersion // Set chain version
+	}
+
 	return &config.ChainConfig{
 		Alloc:          alloc,          // Set alloc
 		AllocAddresses: allocAddresses, // Set alloc addresses
@@ -362,25 +661,88 @@ func (app *CLI) parseGenesisFile(genesisPath string) (*config.ChainConfig, error
 	}, nil // Return chain config
 }
 
-func (app *CLI) requestAlloc(networkID uint) (map[string]*big.Float, []summercashCommon.Address, error) {
+// missingCreateValues gets a list of the values required to create a network that
+// have been provided neither via flags nor via the genesis file.
+func missingCreateValues(c *cli.Context, spec *genesisSpec) []string {
+	var missing []string // Init missing values buffer
+
+	if !c.IsSet("network-id") && spec.NetworkID == nil { // Check no network ID
+		missing = append(missing, "network ID (--network-id)") // Append missing network ID
+	}
+
+	if !c.IsSet("inflation") && spec.Inflation == nil { // Check no inflation rate
+		missing = append(missing, "inflation rate (--inflation)") // Append missing inflation rate
+	}
+
+	if spec.Alloc == nil { // Check alloc must be generated
+		if c.String("issuance") == "" { // Check no issuance
+			missing = append(missing, "total issuance (--issuance)") // Append missing issuance
+		}
+
+		if c.Bool("faucet") && c.String("faucet-amount") == "" { // Check faucet enabled without amount
+			missing = append(missing, "faucet allocation (--faucet-amount)") // Append missing faucet amount
+		}
+
+		if c.Bool("faucet") && c.String("faucet-passphrase") == "" { // Check faucet enabled without passphrase
+			missing = append(missing, "faucet keystore passphrase (--faucet-passphrase or $PUPPET_FAUCET_PASSPHRASE)") // Append missing faucet passphrase
+		}
+	} else if hasFaucet(spec.Alloc) && c.String("faucet-passphrase") == "" { // Check genesis file has faucets without passphrase
+		missing = append(missing, "faucet keystore passphrase (--faucet-passphrase or $PUPPET_FAUCET_PASSPHRASE)") // Append missing faucet passphrase
+	}
+
+	return missing // Return missing values
+}
+
+// requestAlloc requests the total issuance, faucet allocation, and any additional genesis addresses
+// from the user, generating the genesis and faucet accounts along the way. Answers provided via flags
+// are used instead of prompting.
+func (app *CLI) requestAlloc(c *cli.Context, networkID uint) (map[string]*big.Float, []summercashCommon.Address, error) {
 	alloc := make(map[string]*big.Float)           // Init alloc map
 	allocAddresses := []summercashCommon.Address{} // Init alloc address buffer
 
-	totalIssuanceString, err := app.InputConfig.Ask("How many coins would you like to issue?", &input.Options{
-		Default:   "21000000", // Set default
-		Required:  true,       // Make required
-		HideOrder: true,       // Hide extra question
-	})
+	nonInteractive := c.Bool("non-interactive") // Check should not prompt for input
+
+	totalIssuanceString := c.String("issuance") // Get issuance flag
+
+	if totalIssuanceString == "" { // Check issuance not specified
+		var err error // Init error buffer
+
+		totalIssuanceString, err = app.InputConfig.Ask("How many coins would you like to issue?", &input.Options{
+			Default:   "21000000", // Set default
+			Required:  true,       // Make required
+			HideOrder: true,       // Hide extra question
+		})
+
+		if err != nil { // Check for errors
+			return nil, []summercashCommon.Address{}, err // Return found error
+		}
+
+		if totalIssuanceString == "\r" { // Check no value specified
+			totalIssuanceString = "21000000" // Set default
+		}
+
+		totalIssuanceString = strings.Replace(totalIssuanceString, "\r", "", 1) // Remove \r
+	}
+
+	totalIssuanceBigVal, _, err := big.ParseFloat(totalIssuanceString, 10, 18, big.ToNearestEven) // Parse total issuance
+
+	if err != nil { // Check for errors
+		return nil, []summercashCommon.Address{}, err // Return found error
+	}
+
+	genesisKeys, err := keyReader(c, keyPurpose(genesis.RoleGenesis, "")) // Get genesis key source
 
-	if totalIssuanceString == "\r" { // Check no value specified
-		totalIssuanceString = "21000000" // Set default
+	if err != nil { // Check for errors
+		return nil, []summercashCommon.Address{}, err // Return found error
 	}
 
-	totalIssuanceString = strings.Replace(totalIssuanceString, "\r", "", 1) // Remove \r
+	genesisAccount, err := newAccount(networkID, genesisKeys) // Initialize genesis account
 
-	totalIssuanceBigVal, _, _ := big.ParseFloat(totalIssuanceString, 10, 18, big.ToNearestEven) // Parse total issuance
+	if err != nil { // Check for errors
+		return nil, []summercashCommon.Address{}, err // Return found error
+	}
 
-	genesisAccount, err := newAccount(networkID) // Initialize genesis account
+	err = recordRole(genesisAccount.Address, genesis.RoleGenesis, "") // Record genesis role
 
 	if err != nil { // Check for errors
 		return nil, []summercashCommon.Address{}, err // Return found error
@@ -389,48 +751,82 @@ func (app *CLI) requestAlloc(networkID uint) (map[string]*big.Float, []summercas
 	alloc[genesisAccount.Address.String()] = totalIssuanceBigVal    // Set value
 	allocAddresses = append(allocAddresses, genesisAccount.Address) // Append genesis account address
 
-	shouldEnableFaucetString, err := app.InputConfig.Ask("Would you like to enable the SummerCash faucet?", &input.Options{
-		Default:   "true", // Set default
-		Required:  true,   // Make required
-		HideOrder: true,   // Hide extra question
-	})
+	shouldEnableFaucet := c.Bool("faucet") // Get faucet flag
 
-	if shouldEnableFaucetString == "\r" { // Check no value specified
-		shouldEnableFaucetString = "true" // Set should not
-	}
+	if !c.IsSet("faucet") && !nonInteractive { // Check faucet not specified
+		shouldEnableFaucetString, err := app.InputConfig.Ask("Would you like to enable the SummerCash faucet?", &input.Options{
+			Default:   "true", // Set default
+			Required:  true,   // Make required
+			HideOrder: true,   // Hide extra question
+		})
 
-	shouldEnableFaucetString = strings.Replace(shouldEnableFaucetString, "\r", "", 1) // Remove \r
+		if err != nil { // Check for errors
+			return nil, []summercashCommon.Address{}, err // Return found error
+		}
+
+		if shouldEnableFaucetString == "\r" { // Check no value specified
+			shouldEnableFaucetString = "true" // Set should not
+		}
 
-	shouldEnableFaucet, err := strconv.ParseBool(shouldEnableFaucetString) // Parse should enable faucet
+		shouldEnableFaucetString = strings.Replace(shouldEnableFaucetString, "\r", "", 1) // Remove \r
 
-	if err != nil { // Check for errors
-		return nil, []summercashCommon.Address{}, err // Return found error
+		shouldEnableFaucet, err = strconv.ParseBool(shouldEnableFaucetString) // Parse should enable faucet
+
+		if err != nil { // Check for errors
+			return nil, []summercashCommon.Address{}, err // Return found error
+		}
 	}
 
 	if shouldEnableFaucet { // Check should enable faucet
-		faucet, err := makeFaucetAccount(networkID) // Initialize faucet account
+		faucetKeys, err := keyReader(c, keyPurpose(genesis.RoleFaucet, "faucet")) // Get faucet key source
 
 		if err != nil { // Check for errors
 			return nil, []summercashCommon.Address{}, err // Return found error
 		}
 
-		amountShouldGiftFaucetString, err := app.InputConfig.Ask("How many coins would you like to allocate to the faucet?", &input.Options{
-			Default:   "100", // Set default
-			Required:  true,  // Make required
-			HideOrder: true,  // Hide extra question
-		})
+		passphrase, err := app.requestFaucetPassphrase(c, "Please choose a passphrase to encrypt the faucet keystore with:") // Get faucet keystore passphrase
+
+		if err != nil { // Check for errors
+			return nil, []summercashCommon.Address{}, err // Return found error
+		}
+
+		faucet, err := makeFaucetAccount("faucet", networkID, faucetKeys, passphrase) // Initialize faucet account
 
-		if amountShouldGiftFaucetString == "\r" || amountShouldGiftFaucetString == "" { // Check no value set
-			amountShouldGiftFaucetString = "100" // Set to default
+		if err != nil { // Check for errors
+			return nil, []summercashCommon.Address{}, err // Return found error
 		}
 
-		amountShouldGiftFaucet, _, _ := big.ParseFloat(amountShouldGiftFaucetString, 10, 18, big.ToNearestEven) // Parse float
+		amountShouldGiftFaucetString := c.String("faucet-amount") // Get faucet amount flag
+
+		if amountShouldGiftFaucetString == "" { // Check faucet amount not specified
+			amountShouldGiftFaucetString, err = app.InputConfig.Ask("How many coins would you like to allocate to the faucet?", &input.Options{
+				Default:   "100", // Set default
+				Required:  true,  // Make required
+				HideOrder: true,  // Hide extra question
+			})
+
+			if err != nil { // Check for errors
+				return nil, []summercashCommon.Address{}, err // Return found error
+			}
+
+			if amountShouldGiftFaucetString == "\r" || amountShouldGiftFaucetString == "" { // Check no value set
+				amountShouldGiftFaucetString = "100" // Set to default
+			}
+
+			amountShouldGiftFaucetString = strings.Replace(amountShouldGiftFaucetString, "\r", "", 1) // Remove \r
+		}
+
+		amountShouldGiftFaucet, _, err := big.ParseFloat(amountShouldGiftFaucetString, 10, 18, big.ToNearestEven) // Parse float
+
+		if err != nil { // Check for errors
+			return nil, []summercashCommon.Address{}, err // Return found error
+		}
 
 		alloc[faucet.Address.String()] = amountShouldGiftFaucet // Set amount to gift faucet
 		allocAddresses = append(allocAddresses, faucet.Address) // Append faucet address
 	}
 
-	for x := 0; true; x++ { // Do until break
+	for x := 0; !nonInteractive; x++ { // Do until break
 		message := "Would you like to add a genesis address (optional, press enter to skip)?" // Set default message
 
 		if x > 0 { // Check multiple addresses
@@ -443,6 +839,10 @@ func (app *CLI) requestAlloc(networkID uint) (map[string]*big.Float, []summercas
 			HideOrder: true,  // Hide extra question
 		})
 
+		if err != nil { // Check for errors
+			return nil, []summercashCommon.Address{}, err // Return found error
+		}
+
 		if additionalAddress == "" || additionalAddress == "\r" { // Check no additional address
 			break // Break
 		}
@@ -465,7 +865,13 @@ func (app *CLI) requestAlloc(networkID uint) (map[string]*big.Float, []summercas
 			return nil, []summercashCommon.Address{}, err // Return found error
 		}
 
-		additionalBalanceBigVal, _, _ := big.ParseFloat(additionalBalance, 10, 18, big.ToNearestEven) // Parse balance string val
+		additionalBalance = strings.Replace(additionalBalance, "\r", "", 1) // Remove \r
+
+		additionalBalanceBigVal, _, err := big.ParseFloat(additionalBalance, 10, 18, big.ToNearestEven) // Parse balance string val
+
+		if err != nil { // Check for errors
+			return nil, []summercashCommon.Address{}, err // Return found error
+		}
 
 		alloc[address.String()] = additionalBalanceBigVal // Set val
 		allocAddresses = append(allocAddresses, address)  // Append alloc address
@@ -474,31 +880,82 @@ func (app *CLI) requestAlloc(networkID uint) (map[string]*big.Float, []summercas
 	return alloc, allocAddresses, nil // No error occurred, return nil
 }
 
-// parseAlloc parses an alloc.
-func parseAlloc(json map[string]interface{}) (map[string]*big.Float, []summercashCommon.Address, error) {
-	alloc := make(map[string]*big.Float) // Init alloc map
+// generateAlloc generates the accounts of the alloc entries keyed by name, setting their addresses, registers every
+// faucet with the wallet server, and records the role and name of each entry.
+func (app *CLI) generateAlloc(c *cli.Context, networkID uint, alloc genesis.Alloc) error {
+	passphrase := "" // Init faucet keystore passphrase buffer
 
-	allocAddresses := []summercashCommon.Address{} // Init alloc address buffer
+	if hasFaucet(alloc) { // Check has faucets
+		var err error // Init error buffer
+
+		passphrase, err = app.requestFaucetPassphrase(c, "Please choose a passphrase to encrypt the faucet keystores with:") // Get faucet keystore passphrase
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+	}
+
+	for i, entry := range alloc { // Iterate through entries
+		role := alloc.EntryRole(i) // Get role
+
+		keys, err := keyReader(c, keyPurpose(role, entry.Name)) // Get key source
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if entry.Address == "" { // Check must generate account
+			account, err := newAccount(networkID, keys) // Initialize account
+
+			if err != nil { // Check for errors
+				return err // Return found error
+			}
 
-	for key, value := range json["alloc"].(map[string]interface{}) { // Iterate through genesis addresses
-		floatVal, _, _ := big.ParseFloat(value.(map[string]interface{})["balance"].(string), 10, 350, big.ToNearestEven) // Parse float
+			entry.Address = account.Address.String() // Set address
+		}
 
-		address, err := summercashCommon.StringToAddress(key) // Get address value
+		address, err := summercashCommon.StringToAddress(entry.Address) // Parse address
 
 		if err != nil { // Check for errors
-			return nil, []summercashCommon.Address{}, err // Return error
+			return err // Return found error
 		}
 
-		allocAddresses = append(allocAddresses, address) // Append address
+		if role == genesis.RoleFaucet { // Check is faucet
+			_, err = registerFaucet(entry.Name, address, keys, passphrase) // Register faucet
+		} else if role != "" || entry.Name != "" { // Check has role
+			err = recordRole(address, role, entry.Name) // Record role
+		}
 
-		alloc[key] = floatVal // Set int val
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
 	}
 
-	return alloc, allocAddresses, nil // No error occurred, return nil
+	return nil // No error occurred, return nil
 }
 
-// newAccount initializes a new account, along with a chain.
-func newAccount(networkID uint) (*accounts.Account, error) {
+// hasFaucet checks whether a given alloc has any faucet entries.
+func hasFaucet(alloc genesis.Alloc) bool {
+	for _, entry := range alloc { // Iterate through entries
+		if entry.Role == genesis.RoleFaucet { // Check is faucet
+			return true // Has faucet
+		}
+	}
+
+	return false // No faucets
+}
+
+// keyPurpose gets the purpose the keys of an account with a given role and name are derived for when creating a network from a seed.
+func keyPurpose(role string, name string) string {
+	if name == "" || name == role { // Check role implies name
+		return role // Return role
+	}
+
+	return role + "/" + name // Return role and name
+}
+
+// newAccount initializes a new account, along with a chain, generating its key from a given source of randomness.
+func newAccount(networkID uint, random io.Reader) (*accounts.Account, error) {
 	account := &accounts.Account{
 		Address: summercashCommon.Address{'\r'}, // Set mock address
 	} // Init account buffer
@@ -507,7 +964,7 @@ func newAccount(networkID uint) (*accounts.Account, error) {
 	var err error                    // Init error buffer
 
 	for bytes.Contains(account.Address.Bytes(), []byte{'\r'}) { // Generate accounts until valid
-		privateKey, err = ecdsa.GenerateKey(elliptic.P521(), rand.Reader) // Generate private key
+		privateKey, err = common.GenerateKey(elliptic.P521(), random) // Generate private key
 
 		if err != nil { // Check for errors
 			return &accounts.Account{}, err // Return error
@@ -537,44 +994,57 @@ func newAccount(networkID uint) (*accounts.Account, error) {
 	return account, chain.WriteToMemory() // Write to memory
 }
 
-// makeFaucetAccount initializes a new account, along with a SummerCash wallet account.
-func makeFaucetAccount(networkID uint) (*walletAccounts.Account, error) {
-	account, err := newAccount(networkID) // Initialize account
+// makeFaucetAccount initializes a new account, along with a SummerCash wallet account with a given name, generating its keys from a given source of randomness.
+// The wallet account's password is stored in a keystore encrypted with a given passphrase.
+func makeFaucetAccount(name string, networkID uint, random io.Reader, passphrase string) (*walletAccounts.Account, error) {
+	account, err := newAccount(networkID, random) // Initialize account
 
 	if err != nil { // Check for errors
 		return &walletAccounts.Account{}, err // Return found error
 	}
 
-	privateKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader) // Generate private key
+	return registerFaucet(name, account.Address, random, passphrase) // Register faucet
+}
+
+// registerFaucet initializes a SummerCash wallet account with a given name for the faucet at a given address, generating its
+// password from a given source of randomness. The password is stored in a keystore encrypted with a given passphrase.
+func registerFaucet(name string, address summercashCommon.Address, random io.Reader, passphrase string) (*walletAccounts.Account, error) {
+	privateKey, err := common.GenerateKey(elliptic.P521(), random) // Generate private key
 
 	if err != nil { // Check for errors
 		return &walletAccounts.Account{}, err // Return found error
 	}
 
-	err = summercashCommon.CreateDirIfDoesNotExist(fmt.Sprintf("%s/faucet/keystore", common.DataDir)) // Create faucet keystore dir
+	path, err := faucetKeystorePath(name) // Get faucet keystore path
 
 	if err != nil { // Check for errors
 		return &walletAccounts.Account{}, err // Return found error
 	}
 
-	keystoreFile, err := os.OpenFile(filepath.FromSlash(fmt.Sprintf("%s/faucet/keystore/privateKey.key", common.DataDir)), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666) // Open keystore dir
+	err = summercashCommon.CreateDirIfDoesNotExist(fmt.Sprintf("%s/faucet/keystore", common.DataDir)) // Create faucet keystore dir
 
 	if err != nil { // Check for errors
 		return &walletAccounts.Account{}, err // Return found error
 	}
 
-	defer keystoreFile.Close() // Close keystore file
+	password := []byte(privateKey.X.String() + privateKey.Y.String()) // Get wallet password
+
+	envelope, err := keystore.Encrypt(password, passphrase) // Encrypt wallet password
+
+	if err != nil { // Check for errors
+		return &walletAccounts.Account{}, err // Return found error
+	}
 
-	_, err = keystoreFile.WriteString(privateKey.X.String() + ":" + privateKey.Y.String()) // Write pwd
+	err = envelope.WriteToFile(path) // Write keystore
 
 	if err != nil { // Check for errors
 		return &walletAccounts.Account{}, err // Return found error
 	}
 
 	walletAccount := &walletAccounts.Account{
-		Name:         "faucet",                                                                 // Set username
-		PasswordHash: walletCrypto.Salt([]byte(privateKey.X.String() + privateKey.Y.String())), // Set password hash
-		Address:      account.Address,                                                          // Set address
+		Name:         name,                        // Set username
+		PasswordHash: walletCrypto.Salt(password), // Set password hash
+		Address:      address,                     // Set address
 	} // Initialize wallet account
 
 	err = summercashCommon.CreateDirIfDoesNotExist(filepath.FromSlash(fmt.Sprintf("%s/db", common.DataDir))) // Create db dir
@@ -602,11 +1072,11 @@ func makeFaucetAccount(networkID uint) (*walletAccounts.Account, error) {
 	err = db.DB.Update(func(tx *bolt.Tx) error {
 		accountsBucket := tx.Bucket([]byte("accounts")) // Get accounts bucket
 
-		if alreadyExists := accountsBucket.Get(crypto.Sha3([]byte("faucet"))); alreadyExists != nil { // Check already exists
+		if alreadyExists := accountsBucket.Get(crypto.Sha3([]byte(name))); alreadyExists != nil { // Check already exists
 			return walletAccounts.ErrAccountAlreadyExists // Return error
 		}
 
-		return accountsBucket.Put(crypto.Sha3([]byte("faucet")), walletAccount.Bytes()) // Put account
+		return accountsBucket.Put(crypto.Sha3([]byte(name)), walletAccount.Bytes()) // Put account
 	}) // Add new account to DB
 
 	if err != nil { // Check for errors
@@ -619,7 +1089,131 @@ func makeFaucetAccount(networkID uint) (*walletAccounts.Account, error) {
 		return &walletAccounts.Account{}, err // Return found error
 	}
 
-	return walletAccount, nil // No error occurred, return nil.
+	return walletAccount, recordRole(address, genesis.RoleFaucet, name) // Record faucet role
+}
+
+// keyReader gets the source of randomness that keys for a given purpose should be generated from.
+// If a seed has been provided, a deterministic reader derived from the seed is returned.
+func keyReader(c *cli.Context, purpose string) (io.Reader, error) {
+	if seed := c.String("seed"); seed != "" { // Check has seed
+		return common.NewSeededReader([]byte(seed), purpose) // Return seeded reader
+	}
+
+	return rand.Reader, nil // Return system randomness
+}
+
+// makeSeededGenesis makes the genesis of a given chain in the same way chain.MakeGenesis does,
+// but with fixed timestamps and deterministic signatures, such that the resulting chains are reproducible.
+func makeSeededGenesis(chain *types.Chain, genesis *config.ChainConfig, genesisPrivateKey *ecdsa.PrivateKey) (summercashCommon.Hash, error) {
+	if !bytes.Equal(chain.Genesis.Bytes(), new(summercashCommon.Hash).Bytes()) || len(chain.Transactions) > 0 { // Check genesis already exists
+		return summercashCommon.Hash{}, types.ErrGenesisAlreadyExists // Return error
+	}
+
+	genesisTx := newSeededTransaction(0, nil, nil, &genesis.AllocAddresses[0], genesis.Alloc[genesis.AllocAddresses[0].String()], []byte("genesis")) // Init transaction
+
+	(*chain).Transactions = append(chain.Transactions, genesisTx) // Append genesis tx
+	(*chain).Genesis = *genesisTx.Hash                            // Set genesis
+
+	err := chain.WriteToMemory() // Write chain to memory
+
+	if err != nil { // Check for errors
+		return summercashCommon.Hash{}, err // Return error
+	}
+
+	lastTx := genesisTx // Set initial
+
+	for x := 1; x < len(genesis.AllocAddresses); x++ { // Iterate through allocations
+		lastTx = newSeededTransaction(uint64(x), lastTx, &genesis.AllocAddresses[0], &genesis.AllocAddresses[x], genesis.Alloc[genesis.AllocAddresses[x].String()], []byte("genesisChild")) // Init transaction
+
+		err = signSeededTransaction(lastTx, genesisPrivateKey) // Sign transaction
+
+		if err != nil { // Check for errors
+			return summercashCommon.Hash{}, err // Return error
+		}
+
+		err = chain.AddTransaction(lastTx) // Add tx
+
+		if err != nil { // Check for errors
+			return summercashCommon.Hash{}, err // Return error
+		}
+
+		recipientChain, err := types.ReadChainFromMemory(genesis.AllocAddresses[x]) // Read recipient chain
+
+		if err != nil { // Check for errors
+			recipientChain, err = types.NewChain(genesis.AllocAddresses[x]) // Init recipient chain
+
+			if err != nil { // Check for errors
+				return summercashCommon.Hash{}, err // Return error
+			}
+
+			err = recipientChain.WriteToMemory() // Write to persistent memory
+
+			if err != nil { // Check for errors
+				return summercashCommon.Hash{}, err // Return error
+			}
+		}
+
+		err = recipientChain.AddTransaction(lastTx) // Add tx
+
+		if err != nil { // Check for errors
+			return summercashCommon.Hash{}, err // Return error
+		}
+	}
+
+	return *genesisTx.Hash, nil // Return genesis
+}
+
+// newSeededTransaction initializes a transaction in the same way types.NewTransaction does, but with a fixed timestamp.
+func newSeededTransaction(nonce uint64, parentTx *types.Transaction, sender *summercashCommon.Address, destination *summercashCommon.Address, amount *big.Float, payload []byte) *types.Transaction {
+	parentHash := &summercashCommon.Hash{} // Init hash buffer
+
+	if parentTx != nil { // Check has parent
+		parentHash = parentTx.Hash // Set parent hash
+	}
+
+	transaction := &types.Transaction{
+		AccountNonce: nonce,                  // Set nonce
+		Sender:       sender,                 // Set sender
+		Recipient:    destination,            // Set recipient
+		Amount:       amount,                 // Set amount
+		Payload:      payload,                // Set tx payload
+		ParentTx:     parentHash,             // Set parent
+		Timestamp:    seededGenesisTimestamp, // Set timestamp
+	} // Init tx
+
+	hash := summercashCommon.NewHash(crypto.Sha3(transaction.Bytes())) // Hash transaction
+
+	for bytes.Contains(hash.Bytes(), []byte{'\r'}) { // Do until does not contain escape character
+		transaction.HashNonce++ // Increment hash nonce
+
+		hash = summercashCommon.NewHash(crypto.Sha3(transaction.Bytes())) // Set hash
+	}
+
+	transaction.Hash = &hash // Set hash
+
+	return transaction // Return initialized transaction
+}
+
+// signSeededTransaction signs a given transaction in the same way types.SignTransaction does, but with a deterministic signature.
+func signSeededTransaction(transaction *types.Transaction, privateKey *ecdsa.PrivateKey) error {
+	pkCopy := *privateKey // Copy pk value, as the signature's public key is cleared when the transaction is written
+
+	hash := crypto.Sha3(transaction.Bytes()) // Hash transaction
+
+	r, s, err := common.SignDeterministic(&pkCopy, hash) // Sign tx
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	(*transaction).Signature = &types.Signature{
+		PublicKey: &pkCopy.PublicKey, // Set public key
+		V:         hash,              // Set val
+		R:         r,                 // Set R
+		S:         s,                 // Set S
+	} // Set signature
+
+	return nil // No error occurred, return nil
 }
 
 /* END INTERNAL METHODS */
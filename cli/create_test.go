@@ -0,0 +1,284 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/puppet/common"
+)
+
+/* BEGIN INTERNAL METHODS TESTS */
+
+// TestReplaceDataDirFailedBuild tests that a failed build leaves an existing network untouched.
+func TestReplaceDataDirFailedBuild(t *testing.T) {
+	target, cleanup := testDataDir(t, "previous") // Make existing network
+
+	defer cleanup() // Clean up
+
+	errBuild := errors.New("build failed") // Init build error
+
+	backup, err := replaceDataDir(target, false, func() error {
+		if common.DataDir == target { // Check building in place
+			t.Fatal("expected build to run in a staging dir") // Panic
+		}
+
+		return testWriteNetwork(t, common.DataDir, "next", errBuild) // Write partial network
+	}) // Replace network
+
+	if err != errBuild || backup != "" { // Check did not fail
+		t.Fatalf("expected build error and no backup, found %v and %q", err, backup) // Panic
+	}
+
+	if common.DataDir != target { // Check data dir not reset
+		t.Fatalf("expected data dir to be reset to %s, found %s", target, common.DataDir) // Panic
+	}
+
+	testCheckNetwork(t, target, "previous") // Check existing network untouched
+	testCheckSiblings(t, target, 1)         // Check staging dir removed
+}
+
+// TestReplaceDataDirSwap tests that a built network is swapped into place, and the existing network is backed up.
+func TestReplaceDataDirSwap(t *testing.T) {
+	target, cleanup := testDataDir(t, "previous") // Make existing network
+
+	defer cleanup() // Clean up
+
+	backup, err := replaceDataDir(target, false, func() error {
+		return testWriteNetwork(t, common.DataDir, "next", nil) // Write network
+	}) // Replace network
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	testCheckNetwork(t, target, "next")     // Check network swapped into place
+	testCheckNetwork(t, backup, "previous") // Check existing network backed up
+	testCheckSiblings(t, target, 2)         // Check staging dir removed
+
+	info, err := os.Stat(target) // Stat data dir
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if info.Mode().Perm() != 0755 { // Check staging dir mode kept
+		t.Fatalf("expected the new data dir to be readable by everyone, found %s", info.Mode()) // Panic
+	}
+}
+
+// TestReplaceDataDirOverwrite tests that an existing network is deleted when overwritten.
+func TestReplaceDataDirOverwrite(t *testing.T) {
+	target, cleanup := testDataDir(t, "previous") // Make existing network
+
+	defer cleanup() // Clean up
+
+	backup, err := replaceDataDir(target, true, func() error {
+		return testWriteNetwork(t, common.DataDir, "next", nil) // Write network
+	}) // Replace network
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if backup != "" { // Check made backup
+		t.Fatalf("expected no backup, found %s", backup) // Panic
+	}
+
+	testCheckNetwork(t, target, "next") // Check network swapped into place
+	testCheckSiblings(t, target, 1)     // Check existing network removed
+}
+
+// TestReplaceDataDirNew tests that a network is created in a data dir without an existing network.
+func TestReplaceDataDirNew(t *testing.T) {
+	existing, cleanup := testDataDir(t, "unrelated") // Make unrelated network
+
+	defer cleanup() // Clean up
+
+	target := filepath.Join(existing, "nested", "network") // Get new data dir
+
+	backup, err := replaceDataDir(target, false, func() error {
+		return testWriteNetwork(t, common.DataDir, "next", nil) // Write network
+	}) // Create network
+
+	if err != nil || backup != "" { // Check for errors
+		t.Fatalf("expected no error and no backup, found %v and %q", err, backup) // Panic
+	}
+
+	testCheckNetwork(t, target, "next") // Check network moved into place
+}
+
+// TestBackupDataDirPath tests the functionality of the backupDataDirPath() helper method.
+func TestBackupDataDirPath(t *testing.T) {
+	target, cleanup := testDataDir(t, "previous") // Make existing network
+
+	defer cleanup() // Clean up
+
+	at := time.Date(2019, time.June, 1, 12, 30, 45, 0, time.FixedZone("UTC+2", 2*60*60)) // Get backup time
+
+	expected := target + ".backup-20190601103045" // Get expected path, in UTC
+
+	for i, suffix := range []string{"", "-1", "-2"} { // Back up repeatedly in the same second
+		path, err := backupDataDirPath(target+string(filepath.Separator), at) // Get backup path
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		if path != expected+suffix { // Check invalid path
+			t.Fatalf("backup %d: expected %s, found %s", i, expected+suffix, path) // Panic
+		}
+
+		if err := os.Mkdir(path, 0755); err != nil { // Take path
+			t.Fatal(err) // Panic
+		}
+	}
+}
+
+// TestCreateNonInteractive tests that a non-interactive create lists every missing value instead of prompting for it,
+// and creates a network without prompting once every value is given.
+func TestCreateNonInteractive(t *testing.T) {
+	dataDir, cleanup := testDataDir(t, "unused") // Make parent dir
+
+	defer cleanup() // Clean up
+
+	passphrase, hasPassphrase := os.LookupEnv("PUPPET_FAUCET_PASSPHRASE") // Get faucet passphrase env var
+
+	os.Unsetenv("PUPPET_FAUCET_PASSPHRASE") // Require the passphrase flag
+
+	if hasPassphrase { // Check had passphrase
+		defer os.Setenv("PUPPET_FAUCET_PASSPHRASE", passphrase) // Reset faucet passphrase env var
+	}
+
+	dataDir = filepath.Join(filepath.Dir(dataDir), "created") // Get data dir
+
+	err := testRun(t, "create", "--data-dir", dataDir, "-y", "--faucet") // Create network without values
+
+	expected := ErrMissingValues.Error() + ": network ID (--network-id), inflation rate (--inflation), total issuance (--issuance), faucet allocation (--faucet-amount), faucet keystore passphrase (--faucet-passphrase or $PUPPET_FAUCET_PASSPHRASE)" // Get expected error
+
+	if err == nil || err.Error() != expected { // Check missing values not listed
+		t.Fatalf("expected %q, found %v", expected, err) // Panic
+	}
+
+	if _, err = os.Stat(dataDir); !os.IsNotExist(err) { // Check network created
+		t.Fatalf("expected no network to be created, found %v", err) // Panic
+	}
+
+	if err = testRun(t, "create", "--data-dir", dataDir, "-y", "--network-id", "5", "--inflation", "0.1", "--issuance", "1000", "--faucet", "--faucet-amount", "100", "--faucet-passphrase", "passphrase"); err != nil { // Create network with every value
+		t.Fatal(err) // Panic
+	}
+
+	if _, err = os.Stat(filepath.Join(dataDir, "config", "config.json")); err != nil { // Check network not created
+		t.Fatalf("expected a network to be created, found %v", err) // Panic
+	}
+}
+
+/* END INTERNAL METHODS TESTS */
+
+/* BEGIN INTERNAL METHODS */
+
+// testPromptReader is an input reader failing the test it belongs to when read from.
+type testPromptReader struct {
+	t *testing.T // Test
+}
+
+// Read fails the test the reader belongs to, as a command prompted for input.
+func (reader *testPromptReader) Read([]byte) (int, error) {
+	reader.t.Error("expected the command not to prompt for input") // Fail
+
+	return 0, io.EOF // Return no input
+}
+
+// testRun runs puppet with a given set of arguments, failing the test if it prompts for input. The current data
+// dirs are reset afterwards.
+func testRun(t *testing.T, args ...string) error {
+	dataDir, smcDataDir := common.DataDir, summercashCommon.DataDir // Get current data dirs
+
+	defer func() { common.DataDir, summercashCommon.DataDir = dataDir, smcDataDir }() // Reset data dirs
+
+	app := NewCLI() // Init CLI
+
+	app.InputConfig.Reader, app.InputConfig.Writer = &testPromptReader{t: t}, ioutil.Discard // Never prompt
+
+	app.SetupCreateCommand()   // Setup create command
+	app.SetupGenesisCommand()  // Setup genesis command
+	app.SetupAccountsCommand() // Setup accounts command
+
+	return app.App.Run(append([]string{"puppet"}, args...)) // Run command
+}
+
+// testDataDir makes a data dir containing a network with a given marker in a new temporary directory. The returned
+// cleanup function removes the temporary directory and resets the current data dir.
+func testDataDir(t *testing.T, marker string) (string, func()) {
+	parent, err := ioutil.TempDir("", "puppet-cli-test-") // Make parent dir
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	dataDir := common.DataDir // Get current data dir
+
+	cleanup := func() {
+		common.DataDir = dataDir // Reset data dir
+		os.RemoveAll(parent)     // Remove parent dir
+	} // Init cleanup
+
+	target := filepath.Join(parent, "network") // Get data dir
+
+	testWriteNetwork(t, target, marker, nil) // Write network
+
+	return target, cleanup // Return data dir
+}
+
+// testWriteNetwork writes a network containing a given marker to a given data dir, returning a given error.
+func testWriteNetwork(t *testing.T, dataDir string, marker string, err error) error {
+	if mkErr := os.MkdirAll(filepath.Join(dataDir, "config"), 0755); mkErr != nil { // Make config dir
+		t.Fatal(mkErr) // Panic
+	}
+
+	if writeErr := ioutil.WriteFile(filepath.Join(dataDir, "config", "marker"), []byte(marker), 0644); writeErr != nil { // Write marker
+		t.Fatal(writeErr) // Panic
+	}
+
+	return err // Return error
+}
+
+// testCheckNetwork checks that the network in a given data dir contains a given marker.
+func testCheckNetwork(t *testing.T, dataDir string, marker string) {
+	found, err := ioutil.ReadFile(filepath.Join(dataDir, "config", "marker")) // Read marker
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if string(found) != marker { // Check invalid marker
+		t.Fatalf("expected network %q in %s, found %q", marker, dataDir, found) // Panic
+	}
+}
+
+// testCheckSiblings checks the number of entries in the parent of a given data dir, including the data dir itself.
+func testCheckSiblings(t *testing.T, dataDir string, expected int) {
+	entries, err := ioutil.ReadDir(filepath.Dir(dataDir)) // Read parent dir
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if len(entries) != expected { // Check invalid number of entries
+		names := make([]string, len(entries)) // Init names buffer
+
+		for i, entry := range entries { // Iterate through entries
+			names[i] = entry.Name() // Set name
+		}
+
+		t.Fatalf("expected %d entries next to %s, found %v", expected, dataDir, names) // Panic
+	}
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,107 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/export"
+)
+
+// ErrNoExportPath is an error definition describing an export missing the path of the file to write to.
+var ErrNoExportPath = errors.New("a path to export to must be provided")
+
+/* BEGIN EXPORTED METHODS */
+
+// SetupExportCommand sets up the export CLI command.
+func (app *CLI) SetupExportCommand() {
+	(*app).App.Commands = append((*app).App.Commands, cli.Command{
+		Name:  "export",                                             // Set name
+		Usage: "export a network to other data stores for analysis", // Set usage
+		Subcommands: []cli.Command{
+			{
+				Name:      "sqlite",                                                                            // Set name
+				Usage:     "write a network's chains, transactions, accounts, and config to a SQLite database", // Set usage
+				ArgsUsage: "OUT.db",                                                                            // Set args usage
+				Action:    app.exportSQLite,                                                                    // Set action
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:        "data-dir, data",                // Set name
+						Value:       common.DataDir,                  // Set value
+						Usage:       "path of the network to export", // Set usage
+						Destination: &common.DataDir,                 // Set destination
+					},
+					cli.BoolFlag{
+						Name:  "incremental",                                                     // Set name
+						Usage: "only append transactions missing from the last export to OUT.db", // Set usage
+					},
+				},
+			},
+		},
+	})
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// exportSQLite handles the export sqlite command.
+func (app *CLI) exportSQLite(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	if c.Args().First() == "" { // Check no path
+		return ErrNoExportPath // Return error
+	}
+
+	chainConfig, err := config.ReadChainConfigFromMemory() // Read chain config
+
+	if err != nil { // Check for errors
+		return fmt.Errorf("could not read the chain config of %s: %s", common.DataDir, err) // Return error
+	}
+
+	chains, chainErrors, err := readLocalChains() // Read chains
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	reportChainErrors(os.Stderr, chainErrors) // Report unreadable chains
+
+	roles, err := readRoles() // Read roles
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	network := &export.Network{Config: chainConfig, Chains: chains, Roles: make(map[string]*export.Role)} // Init network
+
+	for _, role := range roles { // Iterate through roles
+		network.Roles[role.Address] = &export.Role{Role: role.Role, Name: role.Name} // Set role
+	}
+
+	exported, err := export.SQLite(c.Args().First(), network, c.Bool("incremental")) // Export network
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	mode := "exported" // Init mode
+
+	if exported.Incremental { // Check appended
+		mode = "appended" // Set mode
+	}
+
+	fmt.Printf("%s %d transactions to %s (%d chains, %d accounts)\n", mode, exported.Transactions, c.Args().First(), exported.Chains, exported.Accounts) // Log export
+
+	return nil // No error occurred, return nil
+}
+
+/* END INTERNAL METHODS */
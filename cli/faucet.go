@@ -0,0 +1,154 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tcnksm/go-input"
+	"github.com/urfave/cli"
+
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/genesis"
+	"github.com/SummerCash/puppet/keystore"
+)
+
+var (
+	// ErrInvalidFaucetName is an error definition describing a faucet name that can't be used as a keystore file name.
+	ErrInvalidFaucetName = errors.New("faucet names may only contain letters, digits, '-', and '_'")
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// SetupFaucetCommand sets up the faucet CLI command.
+func (app *CLI) SetupFaucetCommand() {
+	(*app).App.Commands = append((*app).App.Commands, cli.Command{
+		Name:  "faucet",                      // Set name
+		Usage: "manage the network's faucet", // Set usage
+		Subcommands: []cli.Command{
+			{
+				Name:   "unlock",                                                             // Set name
+				Usage:  "decrypt the faucet keystore, printing the faucet's wallet password", // Set usage
+				Action: app.unlockFaucet,                                                     // Set action
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:        "data-dir, data",                // Set name
+						Value:       common.DataDir,                  // Set value
+						Usage:       "path of the network to unlock", // Set usage
+						Destination: &common.DataDir,                 // Set destination
+					},
+					cli.StringFlag{
+						Name:  "name",                         // Set name
+						Value: "faucet",                       // Set value
+						Usage: "name of the faucet to unlock", // Set usage
+					},
+					cli.StringFlag{
+						Name:   "faucet-passphrase, passphrase",                    // Set name
+						Usage:  "passphrase the faucet keystore is encrypted with", // Set usage
+						EnvVar: "PUPPET_FAUCET_PASSPHRASE",                         // Set env var
+					},
+					cli.StringFlag{
+						Name:  "out, o",                                                                                         // Set name
+						Usage: "new file to write the faucet's wallet password to, readable only by its owner (default stdout)", // Set usage
+					},
+				},
+			},
+		},
+	})
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// unlockFaucet handles the faucet unlock command.
+func (app *CLI) unlockFaucet(c *cli.Context) error {
+	path, err := faucetKeystorePath(c.String("name")) // Get faucet keystore path
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	envelope, err := keystore.ReadFromFile(path) // Read faucet keystore
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	passphrase, err := app.requestFaucetPassphrase(c, "Please enter the faucet keystore passphrase:") // Get passphrase
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	password, err := envelope.Decrypt(passphrase) // Decrypt wallet password
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if out := c.String("out"); out != "" { // Check has output file
+		file, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) // Create file, refusing to reuse an existing one
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		_, err = file.Write(password) // Write password
+
+		if closeErr := file.Close(); err == nil { // Close file
+			err = closeErr // Set error
+		}
+
+		return err // Return error
+	}
+
+	_, err = fmt.Fprintln(os.Stdout, string(password)) // Write password to stdout
+
+	return err // Return error
+}
+
+// requestFaucetPassphrase gets the faucet keystore passphrase from the faucet-passphrase flag,
+// prompting for it with a given message if it hasn't been provided.
+func (app *CLI) requestFaucetPassphrase(c *cli.Context, message string) (string, error) {
+	if passphrase := c.String("faucet-passphrase"); passphrase != "" { // Check has passphrase flag
+		return passphrase, nil // Return passphrase
+	}
+
+	if c.Bool("non-interactive") { // Check cannot prompt
+		return "", keystore.ErrEmptyPassphrase // Return error
+	}
+
+	passphrase, err := app.InputConfig.Ask(message, &input.Options{
+		Required:  true, // Make required
+		Mask:      true, // Hide passphrase
+		HideOrder: true, // Hide extra question
+	})
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	passphrase = strings.Replace(passphrase, "\r", "", 1) // Remove \r
+
+	if passphrase == "" { // Check no passphrase
+		return "", keystore.ErrEmptyPassphrase // Return error
+	}
+
+	return passphrase, nil // Return passphrase
+}
+
+// faucetKeystorePath gets the path of the keystore of the faucet with a given name in the current data dir, rejecting
+// names that could point outside of the keystore dir.
+func faucetKeystorePath(name string) (string, error) {
+	if !genesis.IsValidName(name) { // Check invalid name
+		return "", fmt.Errorf("%s: %q", ErrInvalidFaucetName, name) // Return error
+	}
+
+	return filepath.FromSlash(fmt.Sprintf("%s/faucet/keystore/%s.json", common.DataDir, name)), nil // Return path
+}
+
+/* END INTERNAL METHODS */
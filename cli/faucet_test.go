@@ -0,0 +1,34 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SummerCash/puppet/common"
+)
+
+/* BEGIN INTERNAL METHODS TESTS */
+
+// TestFaucetKeystorePath tests that the faucetKeystorePath() helper method rejects names that could point outside of
+// the keystore dir.
+func TestFaucetKeystorePath(t *testing.T) {
+	path, err := faucetKeystorePath("faucet_2") // Get keystore path
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if expected := filepath.Join(common.DataDir, "faucet", "keystore", "faucet_2.json"); path != expected { // Check invalid path
+		t.Fatalf("expected %s, found %s", expected, path) // Panic
+	}
+
+	for _, name := range []string{"", "../faucet", "..", "faucet/../../config", "/etc/passwd", "faucet.json"} { // Iterate through invalid names
+		if _, err := faucetKeystorePath(name); err == nil || !strings.HasPrefix(err.Error(), ErrInvalidFaucetName.Error()) { // Check name accepted
+			t.Fatalf("expected invalid faucet name error for %q, found %v", name, err) // Panic
+		}
+	}
+}
+
+/* END INTERNAL METHODS TESTS */
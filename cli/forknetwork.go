@@ -0,0 +1,236 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/genesis"
+	"github.com/SummerCash/puppet/stats"
+	"github.com/SummerCash/puppet/vesting"
+)
+
+var (
+	// ErrNoSourceNetwork is an error definition describing a network fork without a network to fork.
+	ErrNoSourceNetwork = errors.New("the data dir of the network to fork must be provided with --from")
+
+	// ErrNoNetworkID is an error definition describing a network fork without a network ID for the new network.
+	ErrNoNetworkID = errors.New("the network ID of the new network must be provided with --network-id")
+
+	// ErrSameNetworkID is an error definition describing a network fork reusing the network ID of the forked network.
+	ErrSameNetworkID = errors.New("the new network must have a different network ID than the network it's forked from")
+
+	// ErrSameDataDir is an error definition describing a network fork into the data dir of the forked network.
+	ErrSameDataDir = errors.New("the new network must be created in a different data dir (--data-dir) than the network it's forked from")
+
+	// ErrUnreadableChains is an error definition describing a network fork of a network with unreadable chains.
+	ErrUnreadableChains = errors.New("every chain of the forked network must be readable to reproduce its balances")
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// SetupForkNetworkCommand sets up the fork-network CLI command.
+func (app *CLI) SetupForkNetworkCommand() {
+	(*app).App.Commands = append((*app).App.Commands, cli.Command{
+		Name:   "fork-network",                                                                  // Set name
+		Usage:  "create a new SummerCash network starting from the balances of an existing one", // Set usage
+		Action: app.forkNetwork,                                                                 // Set action
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "from",                                 // Set name
+				Usage: "path of the existing network to fork", // Set usage
+			},
+			cli.StringFlag{
+				Name:        "data-dir, data",                           // Set name
+				Value:       common.DataDir,                             // Set value
+				Usage:       "path to store the new network's files in", // Set usage
+				Destination: &common.DataDir,                            // Set destination
+			},
+			cli.UintFlag{
+				Name:  "network-id",                    // Set name
+				Usage: "network ID of the new network", // Set usage
+			},
+			cli.StringSliceFlag{
+				Name:  "exclude",                                              // Set name
+				Usage: "address whose balance is left out of the new network", // Set usage
+			},
+			cli.StringSliceFlag{
+				Name:  "remap",                                                                  // Set name
+				Usage: "move the balance of an address to another address, in the form old=new", // Set usage
+			},
+			cli.BoolFlag{
+				Name:  "replace-faucet",                                                              // Set name
+				Usage: "move faucet balances to newly generated faucet accounts with the same names", // Set usage
+			},
+			cli.StringFlag{
+				Name:   "faucet-passphrase",                                    // Set name
+				Usage:  "passphrase to encrypt replaced faucet keystores with", // Set usage
+				EnvVar: "PUPPET_FAUCET_PASSPHRASE",                             // Set env var
+			},
+			cli.BoolFlag{
+				Name:  "non-interactive, yes, y",                                       // Set name
+				Usage: "never prompt for input; all values must be provided via flags", // Set usage
+			},
+			cli.BoolFlag{
+				Name:  "overwrite",                                                                               // Set name
+				Usage: "delete an existing network in the data dir instead of moving it to a timestamped backup", // Set usage
+			},
+			cli.StringFlag{
+				Name:  "seed",                                                                               // Set name
+				Usage: "derive all generated keys and signatures from a seed, making the fork reproducible", // Set usage
+			},
+		},
+	})
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// forkNetwork handles the fork-network command.
+func (app *CLI) forkNetwork(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	if c.String("from") == "" { // Check no source network
+		return ErrNoSourceNetwork // Return error
+	}
+
+	if !c.IsSet("network-id") { // Check no network ID
+		return ErrNoNetworkID // Return error
+	}
+
+	source, _ := filepath.Abs(c.String("from")) // Get source data dir
+	target, _ := filepath.Abs(common.DataDir)   // Get target data dir
+
+	if source == target { // Check same data dir
+		return ErrSameDataDir // Return error
+	}
+
+	remap, err := genesis.ParseRemapFlags(c.StringSlice("remap")) // Parse remap flags
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	target = common.DataDir // Get data dir to create network in
+
+	common.DataDir = c.String("from") // Read source network
+
+	chainConfig, balances, err := readBalances() // Read balances
+
+	common.DataDir = target // Reset data dir
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if chainConfig.NetworkID == c.Uint("network-id") { // Check same network ID
+		return fmt.Errorf("%s (%d)", ErrSameNetworkID, chainConfig.NetworkID) // Return error
+	}
+
+	spinoff, err := genesis.FromBalances(chainConfig, balances, &genesis.Spinoff{
+		NetworkID:      c.Uint("network-id"),     // Set network ID
+		Exclude:        c.StringSlice("exclude"), // Set excluded addresses
+		Remap:          remap,                    // Set remapped addresses
+		ReplaceFaucets: c.Bool("replace-faucet"), // Set replace faucets
+		At:             time.Now(),               // Carry over vesting locks from now
+	}) // Make genesis
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	encoded, err := spinoff.Encode(genesis.FormatJSON) // Encode genesis, before its accounts are generated
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	backup, err := replaceDataDir(target, c.Bool("overwrite"), func() error {
+		_, err := app.buildNetwork(c, &genesisSpec{Genesis: spinoff, Format: genesis.FormatJSON, Source: encoded}) // Build network
+
+		return err // Return error
+	}) // Build network in place of any existing one
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if backup != "" { // Check made backup
+		fmt.Printf("moved the previous network in %s to %s\n", target, backup) // Log backup
+	}
+
+	fmt.Printf("forked network %d into network %d in %s (%d accounts, supply %s)\n", chainConfig.NetworkID, c.Uint("network-id"), target, len(spinoff.Alloc)-1, spinoff.Alloc[0].Balance) // Log fork
+
+	return nil // No error occurred, return nil
+}
+
+// readBalances reads the chain config of the current data dir, along with the final balance, recorded role, recorded
+// name, and vesting lock of every account with a chain in it.
+func readBalances() (*config.ChainConfig, []*genesis.Balance, error) {
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	chainConfig, err := config.ReadChainConfigFromMemory() // Read chain config
+
+	if err != nil { // Check for errors
+		return nil, nil, fmt.Errorf("could not read the chain config of %s: %s", common.DataDir, err) // Return error
+	}
+
+	chains, chainErrors, err := readLocalChains() // Read chains
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	if len(chainErrors) > 0 { // Check has unreadable chains
+		reportChainErrors(os.Stderr, chainErrors) // Report unreadable chains
+
+		return nil, nil, fmt.Errorf("%s (%d unreadable in %s)", ErrUnreadableChains, len(chainErrors), common.DataDir) // Return error
+	}
+
+	roles, err := readRoles() // Read roles
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	locks, err := vesting.ReadLocks(vestingPath()) // Read vesting locks
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	recorded := make(map[string]*accountRole) // Init recorded roles buffer
+
+	for _, role := range roles { // Iterate through roles
+		recorded[role.Address] = role // Set role
+	}
+
+	balances := make([]*genesis.Balance, len(chains)) // Init balances buffer
+
+	for i, chain := range chains { // Iterate through chains
+		balance, _, _ := stats.Balance(chain) // Compute balance
+
+		balances[i] = &genesis.Balance{Address: chain.Account.String(), Balance: balance} // Set balance
+
+		if role, ok := recorded[chain.Account.String()]; ok { // Check has recorded role
+			balances[i].Role, balances[i].Name = role.Role, role.Name // Set role
+		}
+
+		if lock, err := vesting.FindLock(locks, chain.Account.String()); err == nil { // Check has vesting lock
+			balances[i].Lock = lock // Set lock
+		}
+	}
+
+	return chainConfig, balances, nil // Return balances
+}
+
+/* END INTERNAL METHODS */
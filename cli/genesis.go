@@ -0,0 +1,231 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/genesis"
+	"github.com/SummerCash/puppet/verify"
+)
+
+var (
+	// ErrNoGenesisSource is an error definition describing a data directory without a stored genesis file.
+	ErrNoGenesisSource = errors.New("no genesis file has been stored in the data directory")
+
+	// ErrGenesisMismatch is an error definition describing a genesis chain that doesn't match its chain config.
+	ErrGenesisMismatch = errors.New("genesis chain does not match chain config")
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// SetupGenesisCommand sets up the genesis CLI command.
+func (app *CLI) SetupGenesisCommand() {
+	(*app).App.Commands = append((*app).App.Commands, cli.Command{
+		Name:    "genesis",                      // Set name
+		Aliases: []string{"g"},                  // Set aliases
+		Usage:   "manage network genesis files", // Set usage
+		Subcommands: []cli.Command{
+			{
+				Name:   "export",                                                   // Set name
+				Usage:  "export a genesis file that recreates an existing network", // Set usage
+				Action: app.exportGenesis,                                          // Set action
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:        "data-dir, data",                // Set name
+						Value:       common.DataDir,                  // Set value
+						Usage:       "path of the network to export", // Set usage
+						Destination: &common.DataDir,                 // Set destination
+					},
+					cli.StringFlag{
+						Name:  "format",                                                                                     // Set name
+						Usage: "format to export the genesis file in (json, yaml, or toml); detected from --out by default", // Set usage
+					},
+					cli.StringFlag{
+						Name:  "out, o",                                             // Set name
+						Usage: "file to write the genesis file to (default stdout)", // Set usage
+					},
+				},
+			},
+		},
+	})
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// exportGenesis handles the genesis export command.
+func (app *CLI) exportGenesis(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	chainConfig, err := config.ReadChainConfigFromMemory() // Read config from persistent memory
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	spec, err := genesis.FromChainConfig(chainConfig) // Make genesis from config
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	err = applyRoles(spec.Alloc, chainConfig.AllocAddresses[0].String()) // Set alloc entry roles
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	err = applyVesting(spec.Alloc) // Set alloc entry vesting schedules
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	genesisChain, err := types.ReadGenesisChainFromMemory(chainConfig) // Read genesis chain
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	err = verifyGenesisChain(genesisChain, chainConfig) // Check genesis chain matches config
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	sourceFormat := genesis.FormatJSON // Init source format buffer
+
+	if source, format, err := readGenesisSource(); err == nil { // Check has stored genesis file
+		spec.Comments = source.Comments // Keep comments
+		sourceFormat = format           // Default to source format
+
+		for _, entry := range spec.Alloc { // Iterate through entries
+			if entry.Name != "" && entry.Address != "" { // Check may have been keyed by name
+				spec.Comments.MoveAllocEntry(entry.Name, entry.Address) // Keep entry comments
+			}
+		}
+	}
+
+	format, err := exportFormat(c, sourceFormat) // Get export format
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	encoded, err := spec.Encode(format) // Encode genesis
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if out := c.String("out"); out != "" { // Check has output file
+		return ioutil.WriteFile(out, encoded, 0644) // Write genesis file
+	}
+
+	_, err = os.Stdout.Write(encoded) // Write genesis file to stdout
+
+	return err // Return error
+}
+
+// verifyGenesisChain checks that the genesis and genesis child transactions in a genesis chain
+// match the alloc of a given chain config.
+func verifyGenesisChain(genesisChain *types.Chain, chainConfig *config.ChainConfig) error {
+	if problems := verify.Genesis(genesisChain, chainConfig); len(problems) > 0 { // Check mismatched
+		return fmt.Errorf("%s: %s", ErrGenesisMismatch, problems[0]) // Return mismatch
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// applyRoles sets the role and name of each entry in a given alloc to those recorded when the network was created, given
+// the address of the network's genesis account, whose entry is generated. Faucet entries are generated as well, as
+// the wallet server can only send funds from a faucet whose private key is in the keystore.
+func applyRoles(alloc genesis.Alloc, genesisAddress string) error {
+	roles, err := readRoles() // Read recorded roles
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	for i, entry := range alloc { // Iterate through entries
+		address := entry.Address // Get address
+
+		if i == 0 { // Check is genesis entry
+			address = genesisAddress // Set genesis address
+		}
+
+		for _, role := range roles { // Iterate through roles
+			if !strings.EqualFold(role.Address, address) { // Check different account
+				continue // Continue
+			}
+
+			if i > 0 { // Check role not implied
+				entry.Role = role.Role // Set role
+			}
+
+			if role.Name != "" { // Check has name
+				entry.Name = role.Name // Set name
+			}
+		}
+
+		if i > 0 && entry.Role == genesis.RoleFaucet && entry.Name != "" { // Check is faucet
+			entry.Address = "" // Generate faucet
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// exportFormat gets the format a genesis file should be exported in, falling back to the
+// extension of the output file, and then to a given default format.
+func exportFormat(c *cli.Context, defaultFormat genesis.Format) (genesis.Format, error) {
+	if formatName := c.String("format"); formatName != "" { // Check has format flag
+		return genesis.ParseFormat(formatName) // Parse format
+	}
+
+	if out := c.String("out"); out != "" && filepath.Ext(out) != "" { // Check has output file extension
+		return genesis.FormatFromPath(out) // Detect format
+	}
+
+	return defaultFormat, nil // Return default format
+}
+
+// readGenesisSource reads and decodes the genesis file stored in the data directory when the network was created.
+func readGenesisSource() (*genesis.Genesis, genesis.Format, error) {
+	for _, format := range []genesis.Format{genesis.FormatJSON, genesis.FormatYAML, genesis.FormatTOML} { // Iterate through formats
+		source, err := ioutil.ReadFile(genesisSourcePath(format)) // Read genesis file
+
+		if os.IsNotExist(err) { // Check not stored in format
+			continue // Continue
+		} else if err != nil { // Check for errors
+			return nil, "", err // Return found error
+		}
+
+		spec, err := genesis.DecodeFormat(source, format) // Decode genesis file
+
+		return spec, format, err // Return genesis
+	}
+
+	return nil, "", fmt.Errorf("%s (%s)", ErrNoGenesisSource, common.DataDir) // Return no genesis file
+}
+
+// genesisSourcePath gets the path that the genesis file a network was created from is stored at.
+func genesisSourcePath(format genesis.Format) string {
+	return filepath.FromSlash(fmt.Sprintf("%s/config/genesis.%s", common.DataDir, format)) // Return path
+}
+
+/* END INTERNAL METHODS */
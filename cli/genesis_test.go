@@ -0,0 +1,74 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/SummerCash/puppet/genesis"
+	"github.com/SummerCash/puppet/internal/fixtures"
+)
+
+/* BEGIN INTERNAL METHODS TESTS */
+
+// TestExportGenesisRoundTrip tests that a genesis file exported from a network recreates it.
+func TestExportGenesisRoundTrip(t *testing.T) {
+	dataDir, cleanup := testDataDir(t, "unused") // Make parent dir
+
+	defer cleanup() // Clean up
+
+	parent := filepath.Dir(dataDir) // Get parent dir
+
+	first, second := filepath.Join(parent, "first"), filepath.Join(parent, "second") // Get data dirs
+
+	if err := testRun(t, "create", "--data-dir", first, "-y", "--network-id", "5", "--inflation", "0.1", "--issuance", "1000", "--faucet", "--faucet-amount", "100", "--faucet-passphrase", "passphrase", "--alloc", fixtures.Recipient+"=25"); err != nil { // Create network
+		t.Fatal(err) // Panic
+	}
+
+	exported := testExportGenesis(t, first, filepath.Join(parent, "first.json")) // Export network
+
+	if entry := exported.Alloc[0]; entry.Address != "" || entry.Role != genesis.RoleGenesis || entry.Balance != "1000" { // Check genesis entry keyed by address
+		t.Fatalf("expected a generated genesis entry issuing 1000, found %+v", entry) // Panic
+	}
+
+	if err := testRun(t, "create", "--data-dir", second, "-y", "--genesis", filepath.Join(parent, "first.json"), "--faucet-passphrase", "passphrase"); err != nil { // Recreate network
+		t.Fatal(err) // Panic
+	}
+
+	testExportGenesis(t, second, filepath.Join(parent, "second.json")) // Export recreated network
+
+	original, _ := ioutil.ReadFile(filepath.Join(parent, "first.json"))   // Read exported genesis
+	recreated, _ := ioutil.ReadFile(filepath.Join(parent, "second.json")) // Read recreated genesis
+
+	if string(original) != string(recreated) { // Check network not recreated
+		t.Fatalf("expected the recreated network to export the same genesis file, found:\n%s\nand:\n%s", original, recreated) // Panic
+	}
+}
+
+/* END INTERNAL METHODS TESTS */
+
+/* BEGIN INTERNAL METHODS */
+
+// testExportGenesis exports the genesis file of the network in a given data dir to a given path, and decodes it.
+func testExportGenesis(t *testing.T, dataDir string, out string) *genesis.Genesis {
+	if err := testRun(t, "genesis", "export", "--data-dir", dataDir, "--out", out); err != nil { // Export genesis
+		t.Fatal(err) // Panic
+	}
+
+	data, err := ioutil.ReadFile(out) // Read genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	exported, err := genesis.Decode(data) // Decode genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	return exported // Return genesis
+}
+
+/* END INTERNAL METHODS */
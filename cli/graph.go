@@ -0,0 +1,152 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/graph"
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// SetupGraphCommand sets up the graph CLI command.
+func (app *CLI) SetupGraphCommand() {
+	(*app).App.Commands = append((*app).App.Commands, cli.Command{
+		Name:   "graph",                                                               // Set name
+		Usage:  "export the flow of funds between addresses as DOT, GraphML, or JSON", // Set usage
+		Action: app.exportGraph,                                                       // Set action
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:        "data-dir, data",               // Set name
+				Value:       common.DataDir,                 // Set value
+				Usage:       "path of the network to graph", // Set usage
+				Destination: &common.DataDir,                // Set destination
+			},
+			cli.StringFlag{
+				Name:  "format",                                                                     // Set name
+				Usage: "format to export the graph in (dot, graphml, or json); detected from --out", // Set usage
+			},
+			cli.StringFlag{
+				Name:  "out, o",                                      // Set name
+				Usage: "file to write the graph to (default stdout)", // Set usage
+			},
+			cli.StringFlag{
+				Name:  "since",                                                                 // Set name
+				Usage: "only include transactions at or after a time (RFC 3339 or YYYY-MM-DD)", // Set usage
+			},
+			cli.StringFlag{
+				Name:  "until",                                                            // Set name
+				Usage: "only include transactions before a time (RFC 3339 or YYYY-MM-DD)", // Set usage
+			},
+			cli.StringSliceFlag{
+				Name:  "from",                                                                      // Set name
+				Usage: "only include addresses within --hops transfers of an address (repeatable)", // Set usage
+			},
+			cli.IntFlag{
+				Name:  "hops",                                                            // Set name
+				Value: 1,                                                                 // Set value
+				Usage: "number of transfers, in either direction, to follow from --from", // Set usage
+			},
+		},
+	})
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// exportGraph handles the graph command.
+func (app *CLI) exportGraph(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	format := graph.FormatDOT // Init format buffer
+	var err error             // Init error buffer
+
+	if formatName := c.String("format"); formatName != "" { // Check has format flag
+		format, err = graph.ParseFormat(formatName) // Parse format
+	} else if out := c.String("out"); filepath.Ext(out) != "" { // Check has output file extension
+		format, err = graph.FormatFromPath(out) // Detect format
+	}
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	options := graph.Options{Hops: c.Int("hops")} // Init options
+
+	if options.Since, err = parseTimeFlag(c.String("since")); err != nil { // Parse since
+		return fmt.Errorf("invalid --since: %s", err) // Return error
+	}
+
+	if options.Until, err = parseTimeFlag(c.String("until")); err != nil { // Parse until
+		return fmt.Errorf("invalid --until: %s", err) // Return error
+	}
+
+	for _, from := range c.StringSlice("from") { // Iterate through starting addresses
+		if len(from) < 2 { // Check too short to parse
+			return fmt.Errorf("invalid --from address %q", from) // Return error
+		}
+
+		address, err := summercashCommon.StringToAddress(from) // Parse address
+
+		if err != nil { // Check for errors
+			return fmt.Errorf("invalid --from address %q: %s", from, err) // Return error
+		}
+
+		options.From = append(options.From, address.String()) // Append address
+	}
+
+	chains, chainErrors, err := readLocalChains() // Read chains
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	reportChainErrors(os.Stderr, chainErrors) // Report unreadable chains
+
+	transactionGraph, err := graph.Build(chains, options) // Build graph
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	encoded, err := transactionGraph.Encode(format) // Encode graph
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if out := c.String("out"); out != "" { // Check has output file
+		return ioutil.WriteFile(out, encoded, 0644) // Write graph
+	}
+
+	_, err = os.Stdout.Write(encoded) // Write graph to stdout
+
+	return err // Return error
+}
+
+// parseTimeFlag parses the value of a time flag, given as either an RFC 3339 time or a UTC date. An empty value
+// parses to the zero time.
+func parseTimeFlag(value string) (time.Time, error) {
+	if value == "" { // Check no value
+		return time.Time{}, nil // Return zero time
+	}
+
+	if date, err := time.Parse("2006-01-02", value); err == nil { // Check is date
+		return date, nil // Return date
+	}
+
+	return time.Parse(time.RFC3339, value) // Return time
+}
+
+/* END INTERNAL METHODS */
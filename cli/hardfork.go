@@ -2,14 +2,26 @@
 package cli
 
 import (
-	"strconv"
-	"strings"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
 
 	"github.com/urfave/cli"
 
 	summercashCommon "github.com/SummerCash/go-summercash/common"
 	"github.com/SummerCash/go-summercash/config"
 	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/fork"
+)
+
+var (
+	// ErrNoForkSpec is an error definition describing a scheduled fork missing its fork spec.
+	ErrNoForkSpec = errors.New("a fork spec must be provided with --spec")
+
+	// ErrConflictingVersionFlags is an error definition describing a fork given both a version to bump and a target version.
+	ErrConflictingVersionFlags = errors.New("--bump and --to-version can't be used together")
 )
 
 /* BEGIN EXPORTED METHODS */
@@ -23,10 +35,54 @@ func (app *CLI) SetupHardforkCommand() {
 		Action:  app.forkBlockmesh,               // Set action
 		Flags: []cli.Flag{
 			cli.StringFlag{
-				Name:        "data-dir, data",       // Set name
-				Value:       common.DataDir,         // Set value
-				Usage:       "path start search in", // Set usage
-				Destination: &common.DataDir,        // Set destination
+				Name:        "data-dir, data",              // Set name
+				Value:       common.DataDir,                // Set value
+				Usage:       "path of the network to fork", // Set usage
+				Destination: &common.DataDir,               // Set destination
+			},
+			cli.StringFlag{
+				Name:  "bump",                                                        // Set name
+				Usage: "part of the latest version to bump (major, minor, or patch)", // Set usage
+			},
+			cli.StringFlag{
+				Name:  "to-version",                                             // Set name
+				Usage: "semantic version to fork to (e.g. 1.0.0 or 1.0.0-rc.1)", // Set usage
+			},
+			cli.BoolFlag{
+				Name:  "allow-downgrade",                                                                 // Set name
+				Usage: "allow forking to an older version, or replacing an invalid stored chain version", // Set usage
+			},
+		},
+		Subcommands: []cli.Command{
+			{
+				Name:   "plan",                                                       // Set name
+				Usage:  "print what scheduling the fork in a fork spec would change", // Set usage
+				Action: app.planFork,                                                 // Set action
+				Flags:  forkSpecFlags(),                                              // Set flags
+			},
+			{
+				Name:   "apply",                                                                    // Set name
+				Usage:  "schedule the fork in a fork spec, to take effect at its activation point", // Set usage
+				Action: app.applyFork,                                                              // Set action
+				Flags:  forkSpecFlags(),                                                            // Set flags
+			},
+			{
+				Name:      "rollback",                                                       // Set name
+				Usage:     "roll a fork back, restoring the data dir snapshotted before it", // Set usage
+				ArgsUsage: "[VERSION]",                                                      // Set args usage
+				Action:    app.rollbackFork,                                                 // Set action
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:        "data-dir, data",                   // Set name
+						Value:       common.DataDir,                     // Set value
+						Usage:       "path of the network to roll back", // Set usage
+						Destination: &common.DataDir,                    // Set destination
+					},
+					cli.BoolFlag{
+						Name:  "force",                                                                                    // Set name
+						Usage: "roll back even if transactions have been added since the fork, exporting them for replay", // Set usage
+					},
+				},
 			},
 		},
 	})
@@ -38,7 +94,7 @@ func (app *CLI) SetupHardforkCommand() {
 
 // forkBlockmesh handles the fork command.
 func (app *CLI) forkBlockmesh(c *cli.Context) error {
-	summercashCommon.Silent = true // Silence logsconfigPath
+	summercashCommon.Silent = true // Silence logs
 
 	summercashCommon.DataDir = common.DataDir // Set smc data dir
 
@@ -48,29 +104,158 @@ func (app *CLI) forkBlockmesh(c *cli.Context) error {
 		return err // Return found error
 	}
 
-	previousVersion := config.ChainVersion // Get old version
-
-	err = config.UpdateChainVersion() // Update
+	version := c.String("to-version") // Get target version
 
-	if err != nil { // Check for errors
-		return err // Return found error
+	if version != "" && c.String("bump") != "" { // Check conflicting flags
+		return ErrConflictingVersionFlags // Return error
 	}
 
-	if previousVersion == config.ChainVersion { // Check has not upgraded
-		splitVersion := strings.Split(config.ChainVersion, ".") // Split version
+	if version == "" { // Check no target version
+		latest, err := fork.LatestVersion(common.DataDir) // Get latest version, including scheduled forks
 
-		parsed, err := strconv.Atoi(splitVersion[2]) // Parse version
+		if err != nil && fork.IsInvalidStoredVersion(err) { // Check invalid stored version
+			return fmt.Errorf("%s; pass --to-version with --allow-downgrade to replace it", err) // Return error
+		} else if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if c.String("bump") != "" { // Check has bump
+			version, err = fork.BumpVersion(latest, c.String("bump")) // Bump version
+		} else {
+			version, err = fork.NextVersion(latest) // Get default version
+		}
 
 		if err != nil { // Check for errors
 			return err // Return found error
 		}
+	}
 
-		parsed++ // Increment
+	entry, err := fork.Fork(common.DataDir, version, c.Bool("allow-downgrade")) // Fork network
 
-		config.ChainVersion = strings.Join(splitVersion[:2], ".") + "." + strconv.Itoa(parsed) // Set incremented
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	fmt.Printf("forked network %d from %s to %s (snapshot %s)\n", config.NetworkID, entry.From, entry.To, entry.Snapshot) // Log fork
+
+	for _, migration := range entry.Migrations { // Iterate through migrations
+		fmt.Printf("migrated chains: %s\n", migration) // Log migration
 	}
 
 	return nil // No error occurred, return nil
 }
 
+// planFork handles the hardfork plan command.
+func (app *CLI) planFork(c *cli.Context) error {
+	spec, err := readForkSpec(c) // Read fork spec
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	plan, err := fork.PlanSchedule(common.DataDir, spec) // Plan fork
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) // Init table writer
+
+	fmt.Fprintf(writer, "FORK\t%s -> %s\n", plan.From, plan.To)                         // Write versions
+	fmt.Fprintf(writer, "ACTIVATION\t%s\n", plan.Activation)                            // Write activation point
+	fmt.Fprintf(writer, "ACTIVE ON\t%d of %d local chains\n", plan.Active, plan.Chains) // Write active chains
+	fmt.Fprintln(writer, "\nCHANGES AT ACTIVATION")                                     // Write changes header
+
+	for _, change := range plan.Changes { // Iterate through changes
+		fmt.Fprintf(writer, "%s\t%s -> %s\n", change.Field, change.From, change.To) // Write change
+	}
+
+	fmt.Fprintln(writer, "\nMIGRATIONS RUN ON APPLY") // Write migrations header
+
+	if len(plan.Migrations) == 0 { // Check no migrations
+		fmt.Fprintln(writer, "none") // Write no migrations
+	}
+
+	for _, migration := range plan.Migrations { // Iterate through migrations
+		fmt.Fprintf(writer, "%s\t%s\n", migration.Version, migration.Description) // Write migration
+	}
+
+	return writer.Flush() // Flush table
+}
+
+// applyFork handles the hardfork apply command.
+func (app *CLI) applyFork(c *cli.Context) error {
+	spec, err := readForkSpec(c) // Read fork spec
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	entry, err := fork.Schedule(common.DataDir, spec) // Schedule fork
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	fmt.Printf("scheduled fork from %s to %s %s (snapshot %s)\n", entry.From, entry.To, entry.Activation, entry.Snapshot) // Log fork
+
+	for _, migration := range entry.Migrations { // Iterate through migrations
+		fmt.Printf("migrated chains: %s\n", migration) // Log migration
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// rollbackFork handles the hardfork rollback command.
+func (app *CLI) rollbackFork(c *cli.Context) error {
+	rollback, err := fork.RollbackFork(common.DataDir, c.Args().First(), c.Bool("force")) // Roll back fork
+
+	if err != nil && fork.IsTransactionsSinceFork(err) { // Check transactions added since fork
+		return fmt.Errorf("%s; pass --force to roll back anyway, exporting them for replay", err) // Return error
+	} else if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	fmt.Printf("rolled back from %s to %s (pre-rollback snapshot %s)\n", rollback.From, rollback.To, rollback.Snapshot) // Log rollback
+
+	for _, entry := range rollback.RolledBack { // Iterate through forks rolled back
+		fmt.Printf("rolled back fork from %s to %s at %s\n", entry.From, entry.To, entry.Time.Format(time.RFC3339)) // Log fork
+	}
+
+	if rollback.Replay != "" { // Check transactions exported
+		fmt.Printf("exported %d transactions added since the fork for replay to %s\n", rollback.Transactions, rollback.Replay) // Log export
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// readForkSpec reads the fork spec passed to a hardfork subcommand.
+func readForkSpec(c *cli.Context) (*fork.Spec, error) {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	if c.String("spec") == "" { // Check no spec
+		return nil, ErrNoForkSpec // Return error
+	}
+
+	return fork.ReadSpec(c.String("spec")) // Return spec
+}
+
+// forkSpecFlags gets the flags of the hardfork subcommands.
+func forkSpecFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:        "data-dir, data",              // Set name
+			Value:       common.DataDir,                // Set value
+			Usage:       "path of the network to fork", // Set usage
+			Destination: &common.DataDir,               // Set destination
+		},
+		cli.StringFlag{
+			Name:  "spec",                                                                         // Set name
+			Usage: "fork spec declaring the target version, activation point, and config changes", // Set usage
+		},
+	} // Return flags
+}
+
 /* END INTERNAL METHODS */
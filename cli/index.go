@@ -0,0 +1,128 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/index"
+	"github.com/SummerCash/puppet/query"
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// SetupIndexCommand sets up the index CLI command.
+func (app *CLI) SetupIndexCommand() {
+	dataDirFlag := cli.StringFlag{
+		Name:        "data-dir, data",               // Set name
+		Value:       common.DataDir,                 // Set value
+		Usage:       "path of the network to index", // Set usage
+		Destination: &common.DataDir,                // Set destination
+	} // Init data dir flag
+
+	(*app).App.Commands = append((*app).App.Commands, cli.Command{
+		Name:  "index",                                           // Set name
+		Usage: "maintain the search index of a network's chains", // Set usage
+		Subcommands: []cli.Command{
+			{
+				Name:   "build",                                                 // Set name
+				Usage:  "build the search index from scratch",                   // Set usage
+				Action: func(c *cli.Context) error { return updateIndex(true) }, // Set action
+				Flags:  []cli.Flag{dataDirFlag},                                 // Set flags
+			},
+			{
+				Name:   "update",                                                               // Set name
+				Usage:  "index the transactions added since the search index was last updated", // Set usage
+				Action: func(c *cli.Context) error { return updateIndex(false) },               // Set action
+				Flags:  []cli.Flag{dataDirFlag},                                                // Set flags
+			},
+		},
+	})
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// updateIndex updates the search index of the current data dir, rebuilding it from scratch if full is set.
+func updateIndex(full bool) error {
+	searchIndex, err := index.Open(indexPath(), false) // Open index
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	defer searchIndex.Close() // Close index
+
+	stats, err := searchIndex.Update(chainDir(), full) // Update index
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if stats.Rebuilt { // Check rebuilt
+		color.Green("Built the search index of %d chains (%d transactions) at %s.", stats.Chains, stats.Transactions, indexPath()) // Log rebuild
+	} else {
+		color.Green("Indexed %d new transactions in %d of %d chains.", stats.Transactions, stats.Updated, stats.Chains) // Log update
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// searchWithIndex searches the search index of the current data dir for the transactions in the chains of a given set of accounts
+// matching a query. If the index hasn't been built, or is out of date, ok is false and the chains must be scanned instead.
+func searchWithIndex(addresses []summercashCommon.Address, searchQuery query.Query, progress io.Writer) (hits map[summercashCommon.Address][]*index.Hit, ok bool, err error) {
+	searchIndex, err := index.Open(indexPath(), true) // Open index
+
+	if err == index.ErrNoIndex { // Check not built
+		return nil, false, nil // Scan chains
+	} else if err != nil { // Check for errors
+		return nil, false, err // Return found error
+	}
+
+	defer searchIndex.Close() // Close index
+
+	fresh, err := searchIndex.Fresh(chainDir()) // Check fresh
+
+	if err != nil { // Check for errors
+		return nil, false, err // Return found error
+	}
+
+	if !fresh { // Check out of date
+		fmt.Fprintln(progress, color.YellowString("The search index is out of date; scanning chains instead. Run `puppet index update` to refresh it.")) // Log stale
+
+		return nil, false, nil // Scan chains
+	}
+
+	found, err := searchIndex.Search(searchQuery, addresses) // Search index
+
+	if err != nil { // Check for errors
+		return nil, false, err // Return found error
+	}
+
+	hits = make(map[summercashCommon.Address][]*index.Hit) // Init hits
+
+	for _, hit := range found { // Iterate through hits
+		hits[hit.Chain] = append(hits[hit.Chain], hit) // Group by chain
+	}
+
+	return hits, true, nil // Return hits
+}
+
+// indexPath gets the path of the search index of the current data dir.
+func indexPath() string {
+	return filepath.FromSlash(fmt.Sprintf("%s/index/search.db", common.DataDir)) // Return path
+}
+
+// chainDir gets the path of the chain directory of the current data dir.
+func chainDir() string {
+	return filepath.FromSlash(fmt.Sprintf("%s/db/chain", common.DataDir)) // Return path
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,493 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/urfave/cli"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/query"
+	"github.com/SummerCash/puppet/stats"
+	"github.com/SummerCash/puppet/vesting"
+)
+
+// chainInspection represents the inspected state of a chain.
+type chainInspection struct {
+	Account   string `json:"account"`   // Chain account address
+	ID        string `json:"id"`        // Chain ID
+	NetworkID uint   `json:"networkID"` // Network ID
+	Genesis   string `json:"genesis"`   // Genesis transaction hash
+	Contract  bool   `json:"contract"`  // Whether the chain holds a contract
+
+	TransactionCount int    `json:"transactionCount"` // Number of transactions
+	Balance          string `json:"balance"`          // Account balance
+	Received         string `json:"received"`         // Total received, including the genesis allocation
+	Sent             string `json:"sent"`             // Total sent
+
+	Vesting *vestingInspection `json:"vesting,omitempty"` // Vesting status of the genesis allocation, if it vests
+
+	Transactions []*transactionInspection `json:"transactions"` // Transactions
+}
+
+// transactionInspection represents the inspected state of a transaction.
+type transactionInspection struct {
+	Hash      string    `json:"hash"`      // Transaction hash
+	Nonce     uint64    `json:"nonce"`     // Account nonce
+	Sender    string    `json:"sender"`    // Sender address
+	Recipient string    `json:"recipient"` // Recipient address
+	Amount    string    `json:"amount"`    // Amount
+	Timestamp time.Time `json:"timestamp"` // Timestamp
+	Parent    string    `json:"parent"`    // Parent transaction hash
+
+	Genesis          bool   `json:"genesis"`            // Whether the transaction is a genesis transaction
+	ContractCreation bool   `json:"contractCreation"`   // Whether the transaction deploys a contract
+	Contract         string `json:"contract,omitempty"` // Deployed contract address
+	Signed           bool   `json:"signed"`             // Whether the transaction carries a signature
+
+	PayloadHex  string          `json:"payloadHex"`            // Hex-encoded payload
+	PayloadUTF8 string          `json:"payloadUTF8"`           // Payload, if valid UTF-8
+	PayloadJSON json.RawMessage `json:"payloadJSON,omitempty"` // Payload, if valid JSON
+
+	Chains []string `json:"chains,omitempty"` // Chains holding the transaction
+}
+
+// accountInspection represents the inspected state of an account.
+type accountInspection struct {
+	Address string   `json:"address"` // Account address
+	Local   bool     `json:"local"`   // Whether the account's private key is in the data dir
+	Roles   []string `json:"roles"`   // Genesis roles
+
+	ChainID          string `json:"chainID"`          // Chain ID, if the account has a chain
+	TransactionCount int    `json:"transactionCount"` // Number of transactions in the account's chain
+	Sent             int    `json:"sent"`             // Number of transactions sent
+	Received         int    `json:"received"`         // Number of transactions received
+	NextNonce        uint64 `json:"nextNonce"`        // Nonce of the account's next transaction
+	Balance          string `json:"balance"`          // Account balance
+
+	Vesting *vestingInspection `json:"vesting,omitempty"` // Vesting status of the genesis allocation, if it vests
+}
+
+// vestingInspection represents the vesting status of a genesis allocation.
+type vestingInspection struct {
+	Schedule string `json:"schedule"` // Schedule description
+	Total    string `json:"total"`    // Allocated balance
+	Unlocked string `json:"unlocked"` // Unlocked balance
+	Locked   string `json:"locked"`   // Locked balance
+
+	NextUnlock    *time.Time `json:"nextUnlock,omitempty"`    // Time of the next unlock
+	FullyUnlocked *time.Time `json:"fullyUnlocked,omitempty"` // Time the allocation is fully unlocked
+}
+
+var (
+	// ErrNoHash is an error definition describing a command invoked without a transaction hash.
+	ErrNoHash = errors.New("a transaction hash must be provided")
+
+	// ErrTransactionNotFound is an error definition describing a transaction that isn't in any local chain.
+	ErrTransactionNotFound = errors.New("no local chain holds a transaction with the given hash")
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// SetupInspectCommand sets up the inspect CLI command.
+func (app *CLI) SetupInspectCommand() {
+	flags := []cli.Flag{
+		cli.StringFlag{
+			Name:        "data-dir, data",                 // Set name
+			Value:       common.DataDir,                   // Set value
+			Usage:       "path of the network to inspect", // Set usage
+			Destination: &common.DataDir,                  // Set destination
+		},
+		cli.BoolFlag{
+			Name:  "json",                         // Set name
+			Usage: "print the inspection as JSON", // Set usage
+		},
+	} // Init flags
+
+	(*app).App.Commands = append((*app).App.Commands, cli.Command{
+		Name:    "inspect",                                      // Set name
+		Aliases: []string{"i"},                                  // Set aliases
+		Usage:   "show a single chain, transaction, or account", // Set usage
+		Subcommands: []cli.Command{
+			{
+				Name:      "chain",                                               // Set name
+				Usage:     "show a chain's metadata, balances, and transactions", // Set usage
+				ArgsUsage: "ADDRESS",                                             // Set args usage
+				Action:    app.inspectChain,                                      // Set action
+				Flags:     flags,                                                 // Set flags
+			},
+			{
+				Name:      "tx",                                           // Set name
+				Aliases:   []string{"transaction"},                        // Set aliases
+				Usage:     "show a transaction, with its payload decoded", // Set usage
+				ArgsUsage: "HASH",                                         // Set args usage
+				Action:    app.inspectTransaction,                         // Set action
+				Flags:     flags,                                          // Set flags
+			},
+			{
+				Name:      "account",                                              // Set name
+				Usage:     "show an account's roles, balance, and vesting status", // Set usage
+				ArgsUsage: "ADDRESS",                                              // Set args usage
+				Action:    app.inspectAccount,                                     // Set action
+				Flags:     flags,                                                  // Set flags
+			},
+		},
+	})
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// inspectChain handles the inspect chain command.
+func (app *CLI) inspectChain(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	address, err := addressArg(c) // Get address
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	chain, err := types.ReadChainFromMemory(address) // Read chain
+
+	if err != nil { // Check for errors
+		return fmt.Errorf("could not read the chain of %s: %s", address.String(), err) // Return error
+	}
+
+	balance, received, sent := stats.Balance(chain) // Get balances
+
+	inspection := &chainInspection{
+		Account:          chain.Account.String(),        // Set account
+		ID:               chain.ID.String(),             // Set ID
+		NetworkID:        chain.NetworkID,               // Set network ID
+		Genesis:          chain.Genesis.String(),        // Set genesis
+		Contract:         len(chain.ContractSource) > 0, // Set contract
+		TransactionCount: len(chain.Transactions),       // Set transaction count
+		Balance:          formatAmount(balance),         // Set balance
+		Received:         formatAmount(received),        // Set received
+		Sent:             formatAmount(sent),            // Set sent
+		Transactions:     []*transactionInspection{},    // Init transactions
+	} // Init inspection
+
+	if inspection.Vesting, err = newVestingInspection(address); err != nil { // Inspect vesting
+		return err // Return found error
+	}
+
+	for _, transaction := range chain.Transactions { // Iterate through transactions
+		if transaction != nil { // Check valid transaction
+			inspection.Transactions = append(inspection.Transactions, newTransactionInspection(transaction)) // Append transaction
+		}
+	}
+
+	if c.Bool("json") { // Check JSON
+		return printJSON(inspection) // Print inspection
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) // Init table writer
+
+	fmt.Fprintf(writer, "ACCOUNT\t%s\n", inspection.Account)               // Write account
+	fmt.Fprintf(writer, "CHAIN ID\t%s\n", inspection.ID)                   // Write ID
+	fmt.Fprintf(writer, "NETWORK ID\t%d\n", inspection.NetworkID)          // Write network ID
+	fmt.Fprintf(writer, "GENESIS\t%s\n", inspection.Genesis)               // Write genesis
+	fmt.Fprintf(writer, "CONTRACT\t%t\n", inspection.Contract)             // Write contract
+	fmt.Fprintf(writer, "TRANSACTIONS\t%d\n", inspection.TransactionCount) // Write transaction count
+	fmt.Fprintf(writer, "BALANCE\t%s\n", inspection.Balance)               // Write balance
+	fmt.Fprintf(writer, "RECEIVED\t%s\n", inspection.Received)             // Write received
+	fmt.Fprintf(writer, "SENT\t%s\n", inspection.Sent)                     // Write sent
+
+	writeVesting(writer, inspection.Vesting) // Write vesting
+
+	if len(inspection.Transactions) > 0 { // Check has transactions
+		fmt.Fprintln(writer, "\nNONCE\tHASH\tSENDER\tRECIPIENT\tAMOUNT\tTIME") // Write header
+
+		for _, transaction := range inspection.Transactions { // Iterate through transactions
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\n", transaction.Nonce, transaction.Hash, orDash(transaction.Sender), orDash(transaction.Recipient), orDash(transaction.Amount), transaction.Timestamp.Format(time.RFC3339)) // Write transaction
+		}
+	}
+
+	return writer.Flush() // Flush table
+}
+
+// inspectTransaction handles the inspect tx command.
+func (app *CLI) inspectTransaction(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	if c.Args().First() == "" { // Check no hash
+		return ErrNoHash // Return error
+	}
+
+	searchQuery, err := query.Parse(fmt.Sprintf("hash:%s", c.Args().First())) // Parse hash query
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	summary, err := searchBlockmesh(context.Background(), nil, searchQuery, &searchOptions{Parallelism: runtime.NumCPU(), UseIndex: true, Progress: ioutil.Discard}) // Find transaction
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if len(summary.Results) == 0 { // Check not found
+		return fmt.Errorf("%s: %s", ErrTransactionNotFound, c.Args().First()) // Return error
+	}
+
+	inspection := newTransactionInspection(summary.Results[0].Transaction) // Inspect transaction
+
+	for _, result := range summary.Results { // Iterate through results
+		inspection.Chains = append(inspection.Chains, result.ChainAddress.String()) // Append chain
+	}
+
+	if c.Bool("json") { // Check JSON
+		return printJSON(inspection) // Print inspection
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) // Init table writer
+
+	fmt.Fprintf(writer, "HASH\t%s\n", inspection.Hash)                           // Write hash
+	fmt.Fprintf(writer, "NONCE\t%d\n", inspection.Nonce)                         // Write nonce
+	fmt.Fprintf(writer, "SENDER\t%s\n", orDash(inspection.Sender))               // Write sender
+	fmt.Fprintf(writer, "RECIPIENT\t%s\n", orDash(inspection.Recipient))         // Write recipient
+	fmt.Fprintf(writer, "AMOUNT\t%s\n", orDash(inspection.Amount))               // Write amount
+	fmt.Fprintf(writer, "TIME\t%s\n", inspection.Timestamp.Format(time.RFC3339)) // Write timestamp
+	fmt.Fprintf(writer, "PARENT\t%s\n", orDash(inspection.Parent))               // Write parent
+	fmt.Fprintf(writer, "GENESIS\t%t\n", inspection.Genesis)                     // Write genesis
+	fmt.Fprintf(writer, "SIGNED\t%t\n", inspection.Signed)                       // Write signed
+
+	if inspection.ContractCreation || inspection.Contract != "" { // Check contract transaction
+		fmt.Fprintf(writer, "CONTRACT\t%s (creation: %t)\n", orDash(inspection.Contract), inspection.ContractCreation) // Write contract
+	}
+
+	fmt.Fprintf(writer, "CHAINS\t%s\n", strings.Join(inspection.Chains, ", ")) // Write chains
+
+	if err = writer.Flush(); err != nil { // Flush table
+		return err // Return found error
+	}
+
+	switch {
+	case inspection.PayloadHex == "":
+		fmt.Println("\nPAYLOAD (empty)") // Write empty payload
+	case len(inspection.PayloadJSON) > 0:
+		indented := &bytes.Buffer{} // Init indented buffer
+
+		json.Indent(indented, inspection.PayloadJSON, "", "  ") // Indent payload
+
+		fmt.Printf("\nPAYLOAD (JSON)\n%s\n", indented.String()) // Write JSON payload
+	case inspection.PayloadUTF8 != "":
+		fmt.Printf("\nPAYLOAD (UTF-8)\n%s\n", printable(inspection.PayloadUTF8)) // Write text payload
+	default:
+		fmt.Printf("\nPAYLOAD (hex)\n%s\n", inspection.PayloadHex) // Write hex payload
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// inspectAccount handles the inspect account command.
+func (app *CLI) inspectAccount(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	address, err := addressArg(c) // Get address
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	roles, err := accountRoles() // Get account roles
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	inspection := &accountInspection{
+		Address: address.String(),                               // Set address
+		Roles:   append([]string{}, roles[address.String()]...), // Set roles
+		Balance: "0",                                            // Init balance
+	} // Init inspection
+
+	if _, err := os.Stat(accountPath(address)); err == nil { // Check has keystore file
+		inspection.Local = true // Set local
+	}
+
+	if chain, err := types.ReadChainFromMemory(address); err == nil { // Check has chain
+		balance, _, _ := stats.Balance(chain) // Get balance
+
+		inspection.ChainID = chain.ID.String()                // Set chain ID
+		inspection.TransactionCount = len(chain.Transactions) // Set transaction count
+		inspection.Balance = formatAmount(balance)            // Set balance
+
+		for _, transaction := range chain.Transactions { // Iterate through transactions
+			switch {
+			case transaction == nil:
+				continue // Continue
+			case transaction.Sender != nil && *transaction.Sender == address:
+				inspection.Sent++ // Increment sent
+
+				if transaction.AccountNonce >= inspection.NextNonce { // Check latest nonce
+					inspection.NextNonce = transaction.AccountNonce + 1 // Set next nonce
+				}
+			case transaction.Recipient != nil && *transaction.Recipient == address:
+				inspection.Received++ // Increment received
+			}
+		}
+	} else if !inspection.Local && len(inspection.Roles) == 0 { // Check unknown account
+		return fmt.Errorf("no local account, genesis role, or chain for %s", address.String()) // Return error
+	}
+
+	if inspection.Vesting, err = newVestingInspection(address); err != nil { // Inspect vesting
+		return err // Return found error
+	}
+
+	if c.Bool("json") { // Check JSON
+		return printJSON(inspection) // Print inspection
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) // Init table writer
+
+	fmt.Fprintf(writer, "ADDRESS\t%s\n", inspection.Address)                                                                            // Write address
+	fmt.Fprintf(writer, "LOCAL KEY\t%t\n", inspection.Local)                                                                            // Write local
+	fmt.Fprintf(writer, "ROLES\t%s\n", orDash(strings.Join(inspection.Roles, ",")))                                                     // Write roles
+	fmt.Fprintf(writer, "CHAIN ID\t%s\n", orDash(inspection.ChainID))                                                                   // Write chain ID
+	fmt.Fprintf(writer, "TRANSACTIONS\t%d (%d sent, %d received)\n", inspection.TransactionCount, inspection.Sent, inspection.Received) // Write transaction count
+	fmt.Fprintf(writer, "NEXT NONCE\t%d\n", inspection.NextNonce)                                                                       // Write next nonce
+	fmt.Fprintf(writer, "BALANCE\t%s\n", inspection.Balance)                                                                            // Write balance
+
+	writeVesting(writer, inspection.Vesting) // Write vesting
+
+	return writer.Flush() // Flush table
+}
+
+// newTransactionInspection converts a transaction to its inspected representation.
+func newTransactionInspection(transaction *types.Transaction) *transactionInspection {
+	inspection := &transactionInspection{
+		Nonce:            transaction.AccountNonce,                // Set nonce
+		Timestamp:        transaction.Timestamp,                   // Set timestamp
+		Genesis:          transaction.Genesis,                     // Set genesis
+		ContractCreation: transaction.ContractCreation,            // Set contract creation
+		Signed:           transaction.Signature != nil,            // Set signed
+		PayloadHex:       hex.EncodeToString(transaction.Payload), // Set hex payload
+	} // Init inspection
+
+	if transaction.Hash != nil { // Check has hash
+		inspection.Hash = transaction.Hash.String() // Set hash
+	}
+
+	if transaction.Sender != nil { // Check has sender
+		inspection.Sender = transaction.Sender.String() // Set sender
+	}
+
+	if transaction.Recipient != nil { // Check has recipient
+		inspection.Recipient = transaction.Recipient.String() // Set recipient
+	}
+
+	if transaction.Amount != nil { // Check has amount
+		inspection.Amount = transaction.Amount.Text('f', -1) // Set amount
+	}
+
+	if transaction.ParentTx != nil { // Check has parent
+		inspection.Parent = transaction.ParentTx.String() // Set parent
+	}
+
+	if transaction.DeployedContractAddress != nil { // Check has contract
+		inspection.Contract = transaction.DeployedContractAddress.String() // Set contract
+	}
+
+	if utf8.Valid(transaction.Payload) { // Check text payload
+		inspection.PayloadUTF8 = string(transaction.Payload) // Set UTF-8 payload
+	}
+
+	if trimmed := bytes.TrimSpace(transaction.Payload); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) { // Check JSON payload
+		inspection.PayloadJSON = json.RawMessage(trimmed) // Set JSON payload
+	}
+
+	return inspection // Return inspection
+}
+
+// newVestingInspection gets the vesting status of the genesis allocation of a given address, or nil if it doesn't vest.
+func newVestingInspection(address summercashCommon.Address) (*vestingInspection, error) {
+	locks, err := vesting.ReadLocks(vestingPath()) // Read locks
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	lock, err := vesting.FindLock(locks, address.String()) // Find lock
+
+	if err != nil { // Check no lock
+		return nil, nil // Doesn't vest
+	}
+
+	balance, ok := new(big.Float).SetPrec(350).SetString(lock.Balance) // Parse balance
+
+	if !ok { // Check invalid balance
+		return nil, fmt.Errorf("invalid vesting balance for %s", address.String()) // Return error
+	}
+
+	status := lock.Schedule.Status(balance, time.Now().UTC()) // Get status
+
+	return &vestingInspection{
+		Schedule:      describeSchedule(lock.Schedule), // Set schedule
+		Total:         formatAmount(status.Total),      // Set total
+		Unlocked:      formatAmount(status.Unlocked),   // Set unlocked
+		Locked:        formatAmount(status.Locked),     // Set locked
+		NextUnlock:    status.NextUnlock,               // Set next unlock
+		FullyUnlocked: status.FullyUnlocked,            // Set fully unlocked
+	}, nil // Return inspection
+}
+
+// writeVesting writes a vesting status to a table, if there is one.
+func writeVesting(writer *tabwriter.Writer, inspection *vestingInspection) {
+	if inspection == nil { // Check doesn't vest
+		return // Nothing to write
+	}
+
+	fmt.Fprintf(writer, "VESTING\t%s\n", inspection.Schedule)                            // Write schedule
+	fmt.Fprintf(writer, "  UNLOCKED\t%s of %s\n", inspection.Unlocked, inspection.Total) // Write unlocked
+	fmt.Fprintf(writer, "  LOCKED\t%s\n", inspection.Locked)                             // Write locked
+
+	if inspection.NextUnlock != nil { // Check has next unlock
+		fmt.Fprintf(writer, "  NEXT UNLOCK\t%s\n", inspection.NextUnlock.Format(time.RFC3339)) // Write next unlock
+	}
+}
+
+// printJSON prints a value as indented JSON.
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout) // Init encoder
+
+	encoder.SetEscapeHTML(false) // Keep payloads readable
+	encoder.SetIndent("", "  ")  // Indent output
+
+	return encoder.Encode(value) // Print value
+}
+
+// orDash returns a given string, or a dash if it is empty.
+func orDash(s string) string {
+	if s == "" { // Check empty
+		return "-" // Return dash
+	}
+
+	return s // Return string
+}
+
+/* END INTERNAL METHODS */
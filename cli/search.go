@@ -2,24 +2,20 @@
 package cli
 
 import (
-	"bytes"
 	"fmt"
 	"os"
-	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
-	"github.com/gernest/wow"
-	"github.com/gernest/wow/spin"
-	"github.com/kyokomi/emoji"
 	"github.com/tcnksm/go-input"
 	i "github.com/tockins/interact"
 	"github.com/urfave/cli"
 
 	summercashCommon "github.com/SummerCash/go-summercash/common"
-	"github.com/SummerCash/go-summercash/types"
 	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/query"
 )
 
 /* BEGIN EXPORTED METHODS */
@@ -39,13 +35,26 @@ func (app *CLI) SetupSearchCommand() {
 				Destination: &common.DataDir,        // Set destination
 			},
 			cli.StringFlag{
-				Name:  "search-term, term",                   // Set name
-				Usage: "term to search for in the blockmesh", // Set usage
+				Name:  "search-term, term",                                                                       // Set name
+				Usage: "query to search the blockmesh with (e.g. 'sender:0x... amount>100 payload~\"invoice\"')", // Set usage
 			},
 			cli.StringSliceFlag{
 				Name:  "search-chains, chains",    // Set name
 				Usage: "blockchains to search in", // Set usage
 			},
+			cli.IntFlag{
+				Name:  "parallelism, p",                   // Set name
+				Value: runtime.NumCPU(),                   // Set value
+				Usage: "number of chains to scan at once", // Set usage
+			},
+			cli.BoolFlag{
+				Name:  "no-index",                                                 // Set name
+				Usage: "scan every chain, even if the search index is up to date", // Set usage
+			},
+			cli.StringFlag{
+				Name:  "output, o",                                                                          // Set name
+				Usage: "print every result immediately in a machine-readable format (json, ndjson, or csv)", // Set usage
+			},
 		},
 	})
 }
@@ -82,6 +91,16 @@ func (app *CLI) searchBlockmesh(c *cli.Context) error {
 		}
 	}
 
+	output := searchOutputFormat(c.String("output")) // Get output format
+
+	if output != "" && !output.valid() { // Check unknown output format
+		return fmt.Errorf("%s: %s", ErrUnknownOutputFormat, output) // Return error
+	}
+
+	if searchTerm == "" && output != "" { // Check cannot prompt
+		return ErrNoSearchQuery // Return error
+	}
+
 	if searchTerm == "" { // Check search term not specified
 		searchTerm, err = app.InputConfig.Ask("What term would you like to search for?", &input.Options{
 			Required:  true, // Make optional
@@ -93,7 +112,7 @@ func (app *CLI) searchBlockmesh(c *cli.Context) error {
 		}
 	}
 
-	if len(searchChains) == 0 { // Check no search chains specified
+	if len(searchChains) == 0 && output == "" { // Check no search chains specified
 		searchChains, err = app.requestSearchChains(c) // Request search chains
 
 		if err != nil { // Check for errors
@@ -101,18 +120,78 @@ func (app *CLI) searchBlockmesh(c *cli.Context) error {
 		}
 	}
 
-	results, files, err := searchBlockmesh(searchChains, searchTerm) // Search
+	searchQuery, err := query.Parse(searchTerm) // Parse search query
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
+	options := &searchOptions{
+		Parallelism: c.Int("parallelism"), // Set parallelism
+		UseIndex:    !c.Bool("no-index"),  // Set use index
+		Progress:    os.Stdout,            // Set progress writer
+	} // Init search options
+
+	var resultWriter *searchResultWriter // Init result writer buffer
+
+	if output != "" { // Check machine-readable output
+		options.Progress = os.Stderr // Keep stdout for results
+
+		if resultWriter, err = newSearchResultWriter(os.Stdout, output); err != nil { // Init result writer
+			return err // Return found error
+		}
+
+		options.Found = resultWriter.Write // Stream results
+	}
+
+	ctx, stop := interruptContext() // Cancel search on interrupt
+
+	summary, err := searchBlockmesh(ctx, searchChains, searchQuery, options) // Search
+
+	stop() // Restore interrupt behavior
+
+	if resultWriter != nil { // Check machine-readable output
+		if closeErr := resultWriter.Close(); err == nil { // Finish output, even if the search failed
+			err = closeErr // Set error
+		}
+	}
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	summary.report(options.Progress) // Report cancellation and chain errors
+
+	if output != "" { // Check machine-readable output
+		if summary.Cancelled { // Check cancelled
+			return ErrSearchCancelled // Return error
+		}
+
+		if len(summary.Errors) > 0 { // Check incomplete
+			return fmt.Errorf("%d of %d chains could not be searched", len(summary.Errors), summary.Chains) // Return error
+		}
+
+		return nil // No error occurred, return nil
+	}
+
+	results := summary.Results // Get results
+
+	files := make([]string, len(results)) // Init files buffer
+
+	for i, result := range results { // Iterate through results
+		files[i] = result.File // Set file
+	}
+
 	if len(results) == 0 { // Check no results
 		color.Red(fmt.Sprintf("No results were found in any of %d files matching your query for %s.", len(files), searchTerm)) // Log error
 
 		return nil // No error occurred, return nil
 	}
 
+	if interactiveTerminal() { // Check can browse results
+		return browseSearchResults(searchTerm, results) // Browse results
+	}
+
 	green := color.New(color.FgGreen).PrintfFunc() // Init green
 
 	green("All done! Found %d results in %d files matching your query for %s. Which result would you like to show?", len(results), len(files), searchTerm) // Print
@@ -149,13 +228,19 @@ func (app *CLI) searchBlockmesh(c *cli.Context) error {
 				return err // Return found error
 			}
 
-			fmt.Println(results[intVal]) // Log result
-			fmt.Println(files[intVal])   // Log filename
+			if intVal < 0 || intVal >= len(results) { // Check out of range
+				color.Red("There is no result %d; choose a result between 0 and %d.", intVal, len(results)-1) // Log error
+
+				continue // Continue
+			}
+
+			fmt.Println(results[intVal].String()) // Log result
+			fmt.Println(files[intVal])            // Log filename
 		} else {
 			for i := 0; i < len(files); i++ { // Iterate through files
 				if files[i] == resultSelector { // Check is file
-					fmt.Println(results[i]) // Log result
-					fmt.Println(files[i])   // Log filename
+					fmt.Println(results[i].String()) // Log result
+					fmt.Println(files[i])            // Log filename
 				}
 			}
 		}
@@ -220,63 +305,4 @@ func (app *CLI) requestSearchChains(c *cli.Context) ([]string, error) {
 	return searchChains, nil // Return search chains
 }
 
-// searchBlockmesh searches the blockmesh for a particular string.
-// If the search term is found, the particular resources in which it is contained are returned as JSON string values,
-// followed by their file names.
-func searchBlockmesh(searchChains []string, searchTerm string) ([]string, []string, error) {
-	w := wow.New(os.Stdout, spin.Get(spin.Dots), emoji.Sprintf(":mag: Searching the blockmesh...")) // Init logger
-
-	w.Start() // Start spinner
-
-	defer w.Stop() // Stop spinner
-
-	localSearchChains := searchChains // Init local search chains buffer
-
-	var results []string     // Init search results buffer
-	var resultFiles []string // Init result files buffer
-	var err error            // Init error buffer
-
-	if len(searchChains) == 0 { // Check no search chains
-		localSearchChains, err = types.GetAllLocalizedChains() // Get all local chains
-
-		if err != nil { // Check for errors
-			return []string{}, []string{}, err // Return found error
-		}
-	}
-
-	for _, chainName := range localSearchChains { // Iterate through search chains
-		address, err := summercashCommon.StringToAddress(chainName) // Parse string addr
-
-		if err != nil { // Check for errors
-			return []string{}, []string{}, err // Return found error
-		}
-
-		chain, err := types.ReadChainFromMemory(address) // Read chain
-
-		if err != nil { // Check for errors
-			return []string{}, []string{}, err // Return found error
-		}
-
-		if chainName == searchTerm { // Check direct match
-			results = append(results, chain.String())                                                                                         // Append chain string
-			resultFiles = append(resultFiles, filepath.FromSlash(fmt.Sprintf("%s/db/chain/chain_%s.json", common.DataDir, address.String()))) // Append result file
-
-			continue // Continue
-		}
-
-		for _, transaction := range chain.Transactions { // Iterate through transactions
-			if transaction != nil && transaction.Hash != nil { // Check is transaction
-				if transaction.Hash.String() == searchTerm || (transaction.Sender != nil && transaction.Sender.String() == searchTerm) || (transaction.Recipient != nil && transaction.Recipient.String() == searchTerm) || bytes.Contains(transaction.Payload, []byte(searchTerm)) || (transaction.Amount != nil && strings.Contains(transaction.Amount.String(), searchTerm)) { // Check match
-					results = append(results, transaction.String())                                                                                   // Append transaction
-					resultFiles = append(resultFiles, filepath.FromSlash(fmt.Sprintf("%s/db/chain/chain_%s.json", common.DataDir, address.String()))) // Append result file
-
-					continue // Continue
-				}
-			}
-		}
-	}
-
-	return results, resultFiles, nil // Return results
-}
-
 /* BEGIN INTERNAL METHODS */
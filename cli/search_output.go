@@ -0,0 +1,218 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+// searchResult represents a transaction, or a whole chain, matching a search query.
+type searchResult struct {
+	ChainAddress summercashCommon.Address // Address of the account whose chain holds the result
+	File         string                   // Chain file
+
+	Transaction *types.Transaction // Matching transaction
+	Chain       *types.Chain       // Matching chain, if the query matched the chain itself
+
+	Reasons []string // Query terms the result matched
+}
+
+// searchRecord represents a search result in machine-readable output.
+type searchRecord struct {
+	Chain       string `json:"chain"`       // Chain account address
+	Hash        string `json:"hash"`        // Transaction hash
+	Sender      string `json:"sender"`      // Transaction sender
+	Recipient   string `json:"recipient"`   // Transaction recipient
+	Amount      string `json:"amount"`      // Transaction amount
+	PayloadHex  string `json:"payloadHex"`  // Hex-encoded payload
+	PayloadUTF8 string `json:"payloadUTF8"` // Payload, if valid UTF-8
+	Reason      string `json:"reason"`      // Query terms the result matched, separated by semicolons
+}
+
+// searchResultWriter writes search results in a machine-readable format as they are found.
+type searchResultWriter struct {
+	writer io.Writer          // Output
+	format searchOutputFormat // Output format
+
+	encoder   *json.Encoder // JSON encoder
+	buffer    bytes.Buffer  // Encoded JSON array record
+	csvWriter *csv.Writer   // CSV writer
+
+	written int // Number of results written
+}
+
+// searchOutputFormat represents a machine-readable search output format.
+type searchOutputFormat string
+
+const (
+	// searchOutputJSON writes results as a JSON array.
+	searchOutputJSON searchOutputFormat = "json"
+
+	// searchOutputNDJSON writes results as newline-delimited JSON objects.
+	searchOutputNDJSON searchOutputFormat = "ndjson"
+
+	// searchOutputCSV writes results as CSV with a header row.
+	searchOutputCSV searchOutputFormat = "csv"
+)
+
+var (
+	// ErrUnknownOutputFormat is an error definition describing an unsupported search output format.
+	ErrUnknownOutputFormat = errors.New("unknown output format; expected json, ndjson, or csv")
+
+	// ErrNoSearchQuery is an error definition describing a non-interactive search without a query.
+	ErrNoSearchQuery = errors.New("a search query must be provided")
+
+	// searchRecordHeader is the CSV header row of search output.
+	searchRecordHeader = []string{"chain", "hash", "sender", "recipient", "amount", "payloadHex", "payloadUTF8", "reason"}
+)
+
+/* BEGIN INTERNAL METHODS */
+
+// String formats a search result as JSON.
+func (result *searchResult) String() string {
+	if result.Chain != nil { // Check chain match
+		return result.Chain.String() // Return chain
+	}
+
+	return result.Transaction.String() // Return transaction
+}
+
+// record converts a search result to its machine-readable representation.
+func (result *searchResult) record() *searchRecord {
+	record := &searchRecord{
+		Chain:  result.ChainAddress.String(),       // Set chain
+		Reason: strings.Join(result.Reasons, "; "), // Set reason
+	} // Init record
+
+	if transaction := result.Transaction; transaction != nil { // Check transaction match
+		record.Hash = transaction.Hash.String() // Set hash
+
+		if transaction.Sender != nil { // Check has sender
+			record.Sender = transaction.Sender.String() // Set sender
+		}
+
+		if transaction.Recipient != nil { // Check has recipient
+			record.Recipient = transaction.Recipient.String() // Set recipient
+		}
+
+		if transaction.Amount != nil { // Check has amount
+			record.Amount = transaction.Amount.Text('f', -1) // Set amount
+		}
+
+		record.PayloadHex = hex.EncodeToString(transaction.Payload) // Set hex payload
+
+		if utf8.Valid(transaction.Payload) { // Check valid UTF-8
+			record.PayloadUTF8 = string(transaction.Payload) // Set UTF-8 payload
+		}
+	}
+
+	return record // Return record
+}
+
+// fields gets the CSV fields of a search record, in header order.
+func (record *searchRecord) fields() []string {
+	return []string{record.Chain, record.Hash, record.Sender, record.Recipient, record.Amount, record.PayloadHex, record.PayloadUTF8, record.Reason} // Return fields
+}
+
+// valid checks whether a search output format is supported.
+func (format searchOutputFormat) valid() bool {
+	return format == searchOutputJSON || format == searchOutputNDJSON || format == searchOutputCSV // Check supported
+}
+
+// newSearchResultWriter initializes a writer of search results in a given machine-readable format. Each result
+// is written as soon as it is found, so that results can be consumed while a search is still in progress.
+func newSearchResultWriter(writer io.Writer, format searchOutputFormat) (*searchResultWriter, error) {
+	resultWriter := &searchResultWriter{writer: writer, format: format} // Init writer
+
+	switch format {
+	case searchOutputJSON:
+		resultWriter.encoder = json.NewEncoder(&resultWriter.buffer) // Init encoder
+
+		resultWriter.encoder.SetEscapeHTML(false)  // Keep comparison operators readable
+		resultWriter.encoder.SetIndent("  ", "  ") // Indent records within array
+	case searchOutputNDJSON:
+		resultWriter.encoder = json.NewEncoder(writer) // Init encoder
+
+		resultWriter.encoder.SetEscapeHTML(false) // Keep comparison operators readable
+	case searchOutputCSV:
+		resultWriter.csvWriter = csv.NewWriter(writer) // Init CSV writer
+
+		if err := resultWriter.csvWriter.Write(searchRecordHeader); err != nil { // Write header
+			return nil, err // Return found error
+		}
+
+		resultWriter.csvWriter.Flush() // Flush header
+	default:
+		return nil, ErrUnknownOutputFormat // Return unknown format
+	}
+
+	return resultWriter, nil // Return writer
+}
+
+// Write writes a search result.
+func (resultWriter *searchResultWriter) Write(result *searchResult) error {
+	record := result.record() // Get record
+
+	defer func() { resultWriter.written++ }() // Count record
+
+	switch resultWriter.format {
+	case searchOutputJSON:
+		separator := ",\n  " // Init separator
+
+		if resultWriter.written == 0 { // Check first record
+			separator = "[\n  " // Open array
+		}
+
+		if _, err := io.WriteString(resultWriter.writer, separator); err != nil { // Write separator
+			return err // Return found error
+		}
+
+		resultWriter.buffer.Reset() // Reset encoded record buffer
+
+		if err := resultWriter.encoder.Encode(record); err != nil { // Encode record
+			return err // Return found error
+		}
+
+		_, err := resultWriter.writer.Write(bytes.TrimSuffix(resultWriter.buffer.Bytes(), []byte("\n"))) // Write record
+
+		return err // Return error
+	case searchOutputNDJSON:
+		return resultWriter.encoder.Encode(record) // Write record
+	}
+
+	if err := resultWriter.csvWriter.Write(record.fields()); err != nil { // Write record
+		return err // Return found error
+	}
+
+	resultWriter.csvWriter.Flush() // Flush record
+
+	return resultWriter.csvWriter.Error() // Return error
+}
+
+// Close finishes the output of a search result writer.
+func (resultWriter *searchResultWriter) Close() error {
+	if resultWriter.format != searchOutputJSON { // Check not array output
+		return nil // Nothing to finish
+	}
+
+	closing := "\n]\n" // Init closing
+
+	if resultWriter.written == 0 { // Check no records
+		closing = "[]\n" // Empty array
+	}
+
+	_, err := io.WriteString(resultWriter.writer, closing) // Close array
+
+	return err // Return error
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,114 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/SummerCash/puppet/internal/fixtures"
+)
+
+/* BEGIN INTERNAL METHODS TESTS */
+
+// TestSearchResultWriter tests that every search output format is written as expected, including when no results are
+// found.
+func TestSearchResultWriter(t *testing.T) {
+	results := testSearchResults() // Init results
+
+	placeholders := strings.NewReplacer(
+		"{sender}", fixtures.Sender,
+		"{recipient}", fixtures.Recipient,
+		"{transfer}", results[0].Transaction.Hash.String(),
+		"{reward}", results[1].Transaction.Hash.String(),
+	) // Init placeholders, filled in with the addresses and hashes of the results
+
+	for _, test := range []struct {
+		format   searchOutputFormat // Output format
+		results  []*searchResult    // Results written
+		expected string             // Expected output
+	}{
+		{searchOutputJSON, nil, "[]\n"},
+		{searchOutputJSON, results, `[
+  {
+    "chain": "{sender}",
+    "hash": "{transfer}",
+    "sender": "{sender}",
+    "recipient": "{recipient}",
+    "amount": "12.5",
+    "payloadHex": "696e766f6963652022613c6222",
+    "payloadUTF8": "invoice \"a<b\"",
+    "reason": "payload~invoice"
+  },
+  {
+    "chain": "{recipient}",
+    "hash": "{reward}",
+    "sender": "",
+    "recipient": "{recipient}",
+    "amount": "100",
+    "payloadHex": "ff00",
+    "payloadUTF8": "",
+    "reason": "amount>=100; NOT sender:{sender}"
+  }
+]
+`},
+		{searchOutputNDJSON, nil, ""},
+		{searchOutputNDJSON, results, `{"chain":"{sender}","hash":"{transfer}","sender":"{sender}","recipient":"{recipient}","amount":"12.5","payloadHex":"696e766f6963652022613c6222","payloadUTF8":"invoice \"a<b\"","reason":"payload~invoice"}
+{"chain":"{recipient}","hash":"{reward}","sender":"","recipient":"{recipient}","amount":"100","payloadHex":"ff00","payloadUTF8":"","reason":"amount>=100; NOT sender:{sender}"}
+`},
+		{searchOutputCSV, nil, "chain,hash,sender,recipient,amount,payloadHex,payloadUTF8,reason\n"},
+		{searchOutputCSV, results, `chain,hash,sender,recipient,amount,payloadHex,payloadUTF8,reason
+{sender},{transfer},{sender},{recipient},12.5,696e766f6963652022613c6222,"invoice ""a<b""",payload~invoice
+{recipient},{reward},,{recipient},100,ff00,,amount>=100; NOT sender:{sender}
+`},
+	} { // Iterate through tests
+		var buffer bytes.Buffer // Init output buffer
+
+		resultWriter, err := newSearchResultWriter(&buffer, test.format) // Init writer
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		for _, result := range test.results { // Iterate through results
+			if err = resultWriter.Write(result); err != nil { // Write result
+				t.Fatal(err) // Panic
+			}
+		}
+
+		if err = resultWriter.Close(); err != nil { // Finish output
+			t.Fatal(err) // Panic
+		}
+
+		if expected := placeholders.Replace(test.expected); buffer.String() != expected { // Check invalid output
+			t.Fatalf("%s output of %d results: expected\n%s\nfound\n%s", test.format, len(test.results), expected, buffer.String()) // Panic
+		}
+	}
+
+	if _, err := newSearchResultWriter(&bytes.Buffer{}, "xml"); err != ErrUnknownOutputFormat { // Check unknown format accepted
+		t.Fatalf("expected unknown output format error, found %v", err) // Panic
+	}
+}
+
+/* END INTERNAL METHODS TESTS */
+
+/* BEGIN INTERNAL METHODS */
+
+// testSearchResults initializes a transfer matching a search for its payload, and a minting transaction with a binary
+// payload matching a search for its amount.
+func testSearchResults() []*searchResult {
+	sender := fixtures.Address(fixtures.Sender)       // Parse sender
+	recipient := fixtures.Address(fixtures.Recipient) // Parse recipient
+
+	transfer := fixtures.Transaction("transfer", &sender, &recipient, 12.5, fixtures.Start) // Init transfer
+	reward := fixtures.Transaction("reward", nil, &recipient, 100, fixtures.Start)          // Init minting transaction
+
+	transfer.Payload, reward.Payload = []byte(`invoice "a<b"`), []byte{0xff, 0x00} // Set payloads
+
+	return []*searchResult{
+		{ChainAddress: sender, Transaction: transfer, Reasons: []string{"payload~invoice"}},
+		{ChainAddress: recipient, Transaction: reward, Reasons: []string{"amount>=100", "NOT sender:" + fixtures.Sender}},
+	} // Return results
+}
+
+/* END INTERNAL METHODS */
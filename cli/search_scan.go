@@ -0,0 +1,328 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gernest/wow"
+	"github.com/gernest/wow/spin"
+	"github.com/kyokomi/emoji"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/index"
+	"github.com/SummerCash/puppet/query"
+)
+
+// searchOptions represents the settings of a blockmesh search.
+type searchOptions struct {
+	Parallelism int  // Number of chains scanned at once
+	UseIndex    bool // Whether the search index may be used in place of scanning
+
+	Progress io.Writer                 // Writer progress is reported to
+	Found    func(*searchResult) error // Called with each result as it is found, if set
+}
+
+// searchSummary represents the outcome of a blockmesh search.
+type searchSummary struct {
+	Results []*searchResult // Results, ordered by chain and then by position in the chain
+
+	Chains  int           // Number of chains to search
+	Scanned int           // Number of chains searched
+	Errors  []*chainError // Chains that could not be searched
+
+	Cancelled bool // Whether the search was cancelled before every chain was searched
+}
+
+// chainError represents a chain that could not be searched.
+type chainError struct {
+	Chain string // Chain account address
+	Err   error  // Error
+}
+
+// scanEvent represents a result found, or a chain finished (if result is nil), by a scan worker.
+type scanEvent struct {
+	chain  int           // Position of the chain in the search chains
+	result *searchResult // Found result
+	err    error         // Chain error, if the chain has been searched
+}
+
+var (
+	// ErrInvalidParallelism is an error definition describing a parallelism below one.
+	ErrInvalidParallelism = errors.New("parallelism must be at least 1")
+
+	// ErrSearchCancelled is an error definition describing a search cancelled before every chain was searched.
+	ErrSearchCancelled = errors.New("search cancelled")
+)
+
+/* BEGIN INTERNAL METHODS */
+
+// searchBlockmesh searches the blockmesh for the transactions matching a particular query. A query consisting only
+// of a chain's address also matches the chain itself. Unless the search index is used, chains are scanned by a pool
+// of workers, and chains that can't be read are recorded in the summary rather than aborting the search. Cancelling
+// the context stops the search, keeping the results found so far.
+func searchBlockmesh(ctx context.Context, searchChains []string, searchQuery query.Query, options *searchOptions) (*searchSummary, error) {
+	if options.Parallelism < 1 { // Check invalid parallelism
+		return nil, ErrInvalidParallelism // Return error
+	}
+
+	localSearchChains := searchChains // Init local search chains buffer
+
+	var err error // Init error buffer
+
+	if len(searchChains) == 0 { // Check no search chains
+		localSearchChains, err = localChainNames() // Get all local chains
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	}
+
+	summary := &searchSummary{Chains: len(localSearchChains)} // Init summary
+
+	addresses := make([]summercashCommon.Address, len(localSearchChains)) // Init addresses buffer
+	valid := make([]bool, len(localSearchChains))                         // Init valid addresses buffer
+
+	var validAddresses []summercashCommon.Address // Init valid addresses buffer
+
+	for x, chainName := range localSearchChains { // Iterate through search chains
+		if len(chainName) < 2 { // Check too short to parse
+			summary.Errors = append(summary.Errors, &chainError{Chain: chainName, Err: errors.New("invalid chain address")}) // Record error
+
+			continue // Continue
+		}
+
+		if addresses[x], err = summercashCommon.StringToAddress(chainName); err != nil { // Parse string addr
+			summary.Errors = append(summary.Errors, &chainError{Chain: chainName, Err: err}) // Record error
+
+			continue // Continue
+		}
+
+		valid[x] = true                                       // Set valid
+		validAddresses = append(validAddresses, addresses[x]) // Append address
+	}
+
+	var hits map[summercashCommon.Address][]*index.Hit // Init index hits buffer
+
+	indexed := false // Init indexed buffer
+
+	if options.UseIndex && len(validAddresses) > 0 { // Check may use index
+		hits, indexed, err = searchWithIndex(validAddresses, searchQuery, options.Progress) // Search index
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	}
+
+	w := wow.New(options.Progress, spin.Get(spin.Dots), emoji.Sprintf(":mag: Searching the blockmesh...")) // Init logger
+
+	w.Start() // Start spinner
+
+	defer w.Stop() // Stop spinner
+
+	events := make(chan *scanEvent) // Init events
+	jobs := make(chan int)          // Init jobs
+
+	scanCtx, cancel := context.WithCancel(ctx) // Init scan context
+
+	defer cancel() // Stop workers
+
+	workers := sync.WaitGroup{} // Init workers
+
+	for x := 0; x < options.Parallelism; x++ { // Start workers
+		workers.Add(1) // Add worker
+
+		go func() {
+			defer workers.Done() // Finish worker
+
+			for chain := range jobs { // Iterate through jobs
+				var chainHits []*index.Hit // Init chain hits buffer
+
+				if indexed { // Check searched index
+					chainHits = hits[addresses[chain]] // Get chain hits
+				}
+
+				err := scanChain(scanCtx, localSearchChains[chain], addresses[chain], searchQuery, indexed, chainHits, func(result *searchResult) bool {
+					select {
+					case events <- &scanEvent{chain: chain, result: result}: // Send result
+						return true // Continue
+					case <-scanCtx.Done():
+						return false // Stop
+					}
+				}) // Scan chain
+
+				select {
+				case events <- &scanEvent{chain: chain, err: err}: // Send chain finished
+				case <-scanCtx.Done():
+					return // Stop
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(events) // Close events once every worker has stopped
+
+		defer workers.Wait() // Wait for workers
+
+		defer close(jobs) // Stop workers once every job is queued
+
+		for chain := range localSearchChains { // Iterate through search chains
+			if !valid[chain] { // Check invalid address
+				continue // Continue
+			}
+
+			select {
+			case jobs <- chain: // Queue chain
+			case <-scanCtx.Done():
+				return // Stop queueing
+			}
+		}
+	}()
+
+	results := make([][]*searchResult, len(localSearchChains)) // Init results buffer
+	found := 0                                                 // Init found buffer
+
+	for event := range events { // Iterate through events
+		switch {
+		case event.result != nil:
+			results[event.chain] = append(results[event.chain], event.result) // Append result
+
+			found++ // Increment found
+
+			if options.Found != nil { // Check must stream results
+				if err := options.Found(event.result); err != nil { // Stream result
+					cancel() // Stop workers
+
+					for range events { // Drain events
+					}
+
+					return nil, err // Return found error
+				}
+			}
+		case event.err == context.Canceled:
+			continue // Continue
+		case event.err != nil:
+			summary.Errors = append(summary.Errors, &chainError{Chain: localSearchChains[event.chain], Err: event.err}) // Record error
+		default:
+			summary.Scanned++ // Increment scanned
+		}
+
+		w.Text(emoji.Sprintf(":mag: Searching the blockmesh... %d results in %d of %d chains", found, summary.Scanned, summary.Chains)) // Update progress
+	}
+
+	for _, chainResults := range results { // Iterate through chains in order
+		summary.Results = append(summary.Results, chainResults...) // Append results
+	}
+
+	sort.SliceStable(summary.Errors, func(i, j int) bool { return summary.Errors[i].Chain < summary.Errors[j].Chain }) // Sort errors by chain
+
+	summary.Cancelled = ctx.Err() != nil && summary.Scanned+len(summary.Errors) < summary.Chains // Set cancelled
+
+	return summary, nil // Return summary
+}
+
+// scanChain finds the results in the chain of a given account, calling found with each result until it returns false.
+// If the search index has been searched, the given hits are used in place of reading the chain.
+func scanChain(ctx context.Context, chainName string, address summercashCommon.Address, searchQuery query.Query, indexed bool, hits []*index.Hit, found func(*searchResult) bool) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil { // Check invalid chain file
+			err = fmt.Errorf("invalid chain file: %v", recovered) // Set error
+		}
+	}()
+
+	file := filepath.FromSlash(fmt.Sprintf("%s/chain_%s.json", chainDir(), address.String())) // Get chain file
+
+	if term, ok := searchQuery.(*query.Term); ok && term.Field == query.FieldAny && strings.EqualFold(chainName, term.Value) { // Check direct match
+		chain, err := types.ReadChainFromMemory(address) // Read chain
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		found(&searchResult{ChainAddress: address, File: file, Chain: chain, Reasons: []string{"chain"}}) // Send chain
+
+		return nil // No error occurred, return nil
+	}
+
+	if indexed { // Check searched index
+		for _, hit := range hits { // Iterate through hits
+			if !found(&searchResult{ChainAddress: address, File: file, Transaction: hit.Transaction, Reasons: query.Reasons(searchQuery, address, hit.Transaction)}) { // Send transaction
+				return ctx.Err() // Stop
+			}
+		}
+
+		return nil // No error occurred, return nil
+	}
+
+	chain, err := types.ReadChainFromMemory(address) // Read chain
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	for _, transaction := range chain.Transactions { // Iterate through transactions
+		if ctx.Err() != nil { // Check cancelled
+			return ctx.Err() // Stop
+		}
+
+		if transaction != nil && transaction.Hash != nil && searchQuery.Match(address, transaction) { // Check match
+			if !found(&searchResult{ChainAddress: address, File: file, Transaction: transaction, Reasons: query.Reasons(searchQuery, address, transaction)}) { // Send transaction
+				return ctx.Err() // Stop
+			}
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// interruptContext gets a context cancelled when the process is interrupted (e.g. with Ctrl-C), along with a function
+// that restores the default interrupt behavior.
+func interruptContext() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background()) // Init context
+
+	interrupts := make(chan os.Signal, 1) // Init interrupts
+
+	signal.Notify(interrupts, os.Interrupt) // Catch interrupts
+
+	go func() {
+		select {
+		case <-interrupts:
+			cancel() // Cancel context
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(interrupts) // Restore default behavior
+		cancel()                // Release context
+	} // Return context
+}
+
+// report writes the chains that could not be searched, and whether the search was cancelled, to a given writer.
+func (summary *searchSummary) report(writer io.Writer) {
+	if summary.Cancelled { // Check cancelled
+		fmt.Fprintf(writer, "Search cancelled after searching %d of %d chains.\n", summary.Scanned, summary.Chains) // Log cancelled
+	}
+
+	if len(summary.Errors) == 0 { // Check no errors
+		return // Nothing else to report
+	}
+
+	fmt.Fprintf(writer, "%d of %d chains could not be searched:\n", len(summary.Errors), summary.Chains) // Log errors
+
+	for _, chainErr := range summary.Errors { // Iterate through errors
+		fmt.Fprintf(writer, "  %s: %s\n", chainErr.Chain, chainErr.Err) // Log error
+	}
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,198 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/internal/fixtures"
+	"github.com/SummerCash/puppet/query"
+)
+
+const (
+	// testCorruptChain is a valid address whose chain file can't be read in tests.
+	testCorruptChain = "0x0401a6d99270788014429e092dd9f1f3ee9d"
+)
+
+/* BEGIN INTERNAL METHODS TESTS */
+
+// TestSearchBlockmeshParallelism tests that the searchBlockmesh() method finds the same results, in the same order,
+// regardless of the number of workers scanning chains.
+func TestSearchBlockmeshParallelism(t *testing.T) {
+	cleanup := testSearchDataDir(t) // Make network
+
+	defer cleanup() // Clean up
+
+	var expected []string // Init expected hashes buffer
+
+	for _, parallelism := range []int{1, 2, 8} { // Iterate through worker pool sizes
+		summary := testSearch(context.Background(), t, nil, `amount>=10`, parallelism, nil) // Search
+
+		if summary.Chains != 4 || summary.Scanned != 3 || len(summary.Errors) != 1 || summary.Cancelled { // Check invalid summary
+			t.Fatalf("parallelism %d: expected 3 of 4 chains scanned, found %+v", parallelism, summary) // Panic
+		}
+
+		hashes := make([]string, len(summary.Results)) // Init hashes buffer
+
+		for i, result := range summary.Results { // Iterate through results
+			hashes[i] = result.ChainAddress.String() + "/" + result.Transaction.Hash.String() // Set hash
+		}
+
+		if expected == nil { // Check first search
+			expected = hashes // Set expected hashes
+		}
+
+		if len(hashes) != 5 || strings.Join(hashes, " ") != strings.Join(expected, " ") { // Check invalid results
+			t.Fatalf("parallelism %d: expected results %v, found %v", parallelism, expected, hashes) // Panic
+		}
+	}
+
+	if _, err := searchBlockmesh(context.Background(), nil, testParseQuery(t, `amount>=10`), &searchOptions{Progress: ioutil.Discard}); err != ErrInvalidParallelism { // Check empty worker pool accepted
+		t.Fatalf("expected invalid parallelism error, found %v", err) // Panic
+	}
+}
+
+// TestSearchBlockmeshCancel tests that cancelling a search stops it early, keeping the results found so far.
+func TestSearchBlockmeshCancel(t *testing.T) {
+	cleanup := testSearchDataDir(t) // Make network
+
+	defer cleanup() // Clean up
+
+	ctx, cancel := context.WithCancel(context.Background()) // Init context
+
+	defer cancel() // Release context
+
+	summary := testSearch(ctx, t, nil, `amount>=10`, 1, func(*searchResult) error {
+		cancel() // Cancel search once a result has been found
+
+		return nil // Keep result
+	}) // Search
+
+	if !summary.Cancelled || len(summary.Results) == 0 || summary.Scanned+len(summary.Errors) >= summary.Chains { // Check not cancelled
+		t.Fatalf("expected the search to stop after its first result, found %+v", summary) // Panic
+	}
+
+	errFound := errors.New("output closed") // Init result error
+
+	if _, err := searchBlockmesh(context.Background(), nil, testParseQuery(t, `amount>=10`), &searchOptions{
+		Parallelism: 2,                                             // Set parallelism
+		Progress:    ioutil.Discard,                                // Discard progress
+		Found:       func(*searchResult) error { return errFound }, // Fail to write results
+	}); err != errFound { // Check result error ignored
+		t.Fatalf("expected the search to fail with the result error, found %v", err) // Panic
+	}
+}
+
+// TestSearchBlockmeshErrors tests that the searchBlockmesh() method records the chains it can't search rather than
+// stopping, and rejects chain dirs it can't list.
+func TestSearchBlockmeshErrors(t *testing.T) {
+	cleanup := testSearchDataDir(t) // Make network
+
+	defer cleanup() // Clean up
+
+	summary := testSearch(context.Background(), t, []string{fixtures.Sender, "x", testCorruptChain, "0xzz"}, `amount>=10`, 2, nil) // Search
+
+	if summary.Chains != 4 || summary.Scanned != 1 || len(summary.Results) != 3 { // Check invalid summary
+		t.Fatalf("expected the sender chain to be searched, found %+v", summary) // Panic
+	}
+
+	var failed []string // Init failed chains buffer
+
+	for _, chainErr := range summary.Errors { // Iterate through errors
+		failed = append(failed, chainErr.Chain) // Append chain
+	}
+
+	if expected := []string{testCorruptChain, "0xzz", "x"}; strings.Join(failed, " ") != strings.Join(expected, " ") { // Check invalid errors
+		t.Fatalf("expected errors for %v, sorted by chain, found %v", expected, failed) // Panic
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(chainDir(), "notes.txt"), nil, 0644); err != nil { // Write stray file
+		t.Fatal(err) // Panic
+	}
+
+	if _, err := searchBlockmesh(context.Background(), nil, testParseQuery(t, `amount>=10`), &searchOptions{Parallelism: 1, Progress: ioutil.Discard}); err == nil { // Check stray file accepted
+		t.Fatal("expected a chain dir with a stray file to be rejected") // Panic
+	}
+}
+
+/* END INTERNAL METHODS TESTS */
+
+/* BEGIN INTERNAL METHODS */
+
+// testSearchDataDir makes a network in which fixtures.Sender sends fixtures.Recipient 10 and then 20, and
+// fixtures.Recipient sends fixtures.ThirdParty 5, along with a chain that can't be read. The returned cleanup function
+// removes the network and resets the current data dir.
+func testSearchDataDir(t *testing.T) func() {
+	dataDir, cleanup := testDataDir(t, "search") // Make data dir
+
+	smcDataDir := summercashCommon.DataDir // Get current smc data dir
+
+	common.DataDir, summercashCommon.DataDir = dataDir, dataDir // Set data dir
+
+	sender := fixtures.Address(fixtures.Sender)         // Parse sender
+	recipient := fixtures.Address(fixtures.Recipient)   // Parse recipient
+	thirdParty := fixtures.Address(fixtures.ThirdParty) // Parse third party
+
+	genesis := fixtures.Transaction("genesis", nil, &sender, 1000, fixtures.Start)       // Init genesis transaction
+	first := fixtures.Transaction("first", &sender, &recipient, 10, fixtures.Start)      // Init first transfer
+	second := fixtures.Transaction("second", &sender, &recipient, 20, fixtures.Start)    // Init second transfer
+	onward := fixtures.Transaction("onward", &recipient, &thirdParty, 5, fixtures.Start) // Init onward transfer
+
+	for _, chain := range []*types.Chain{
+		{Account: sender, Genesis: *genesis.Hash, Transactions: []*types.Transaction{genesis, first, second}}, // Sender chain
+		{Account: recipient, Transactions: []*types.Transaction{first, second, onward}},                       // Recipient chain
+		{Account: thirdParty, Transactions: []*types.Transaction{onward}},                                     // Third party chain
+	} { // Iterate through chains
+		if err := chain.WriteToMemory(); err != nil { // Write chain
+			cleanup() // Clean up
+
+			t.Fatal(err) // Panic
+		}
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(chainDir(), "chain_"+testCorruptChain+".json"), []byte("{"), 0644); err != nil { // Write corrupt chain
+		cleanup() // Clean up
+
+		t.Fatal(err) // Panic
+	}
+
+	return func() {
+		summercashCommon.DataDir = smcDataDir // Reset smc data dir
+		cleanup()                             // Remove network
+	} // Return cleanup
+}
+
+// testSearch searches the current data dir for a given query, without the search index, failing the test on error.
+func testSearch(ctx context.Context, t *testing.T, searchChains []string, s string, parallelism int, found func(*searchResult) error) *searchSummary {
+	summary, err := searchBlockmesh(ctx, searchChains, testParseQuery(t, s), &searchOptions{
+		Parallelism: parallelism,    // Set parallelism
+		Progress:    ioutil.Discard, // Discard progress
+		Found:       found,          // Set found
+	}) // Search
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	return summary // Return summary
+}
+
+// testParseQuery parses a given query, failing the test on error.
+func testParseQuery(t *testing.T, s string) query.Query {
+	parsed, err := query.Parse(s) // Parse query
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	return parsed // Return query
+}
+
+/* END INTERNAL METHODS */
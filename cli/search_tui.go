@@ -0,0 +1,587 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/ssh/terminal"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+// resultBrowser represents the terminal UI used to browse search results.
+type resultBrowser struct {
+	views []*resultView // View stack; the last view is shown
+
+	filtering bool   // Whether a filter is being typed
+	filter    string // Filter being typed
+	raw       bool   // Whether the detail pane shows the selected result's JSON encoding
+	status    string // Message shown in place of the help line until the next key
+
+	width  int // Terminal width
+	height int // Terminal height
+}
+
+// resultView represents a list of results shown by a result browser.
+type resultView struct {
+	title   string          // View title
+	results []*searchResult // Results
+
+	filter  string // Applied filter
+	visible []int  // Positions of the results matching the filter
+
+	cursor int // Position of the selected result in visible
+	offset int // Position of the first listed result in visible
+}
+
+// clipboardCommands are the commands tried, in order, to copy text to the system clipboard.
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+/* BEGIN INTERNAL METHODS */
+
+// interactiveTerminal checks whether both stdin and stdout are terminals, and can therefore host the result browser.
+func interactiveTerminal() bool {
+	return terminal.IsTerminal(int(os.Stdin.Fd())) && terminal.IsTerminal(int(os.Stdout.Fd())) // Check terminals
+}
+
+// browseSearchResults shows a set of search results in a terminal UI, until the user quits.
+func browseSearchResults(title string, results []*searchResult) error {
+	state, err := terminal.MakeRaw(int(os.Stdin.Fd())) // Enter raw mode
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	defer terminal.Restore(int(os.Stdin.Fd()), state) // Restore terminal
+
+	fmt.Print("\x1b[?1049h\x1b[?25l")       // Enter alternate screen, hide cursor
+	defer fmt.Print("\x1b[?25h\x1b[?1049l") // Show cursor, leave alternate screen
+
+	browser := &resultBrowser{} // Init browser
+
+	browser.push(title, results) // Show results
+
+	input := make([]byte, 64) // Init input buffer
+
+	for {
+		browser.width, browser.height, err = terminal.GetSize(int(os.Stdout.Fd())) // Get terminal size
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		fmt.Print(browser.render()) // Draw screen
+
+		n, err := os.Stdin.Read(input) // Read keys
+
+		if err == io.EOF { // Check input closed
+			return nil // Finished
+		} else if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		for _, key := range parseKeys(input[:n]) { // Iterate through keys
+			if !browser.handle(key) { // Check quit
+				return nil // Finished
+			}
+		}
+	}
+}
+
+// parseKeys splits terminal input into key names (e.g. up, pgdn, enter) and printable characters.
+func parseKeys(input []byte) []string {
+	sequences := map[string]string{
+		"\x1b[A": "up", "\x1b[B": "down", "\x1b[C": "right", "\x1b[D": "left",
+		"\x1bOA": "up", "\x1bOB": "down", "\x1bOC": "right", "\x1bOD": "left",
+		"\x1b[5~": "pgup", "\x1b[6~": "pgdn",
+		"\x1b[H": "home", "\x1b[1~": "home", "\x1bOH": "home",
+		"\x1b[F": "end", "\x1b[4~": "end", "\x1bOF": "end",
+	} // Init escape sequences
+
+	var keys []string // Init keys buffer
+
+	for len(input) > 0 { // Iterate through input
+		if input[0] == 0x1b { // Check escape sequence
+			matched := false // Init matched buffer
+
+			for sequence, key := range sequences { // Iterate through sequences
+				if bytes.HasPrefix(input, []byte(sequence)) { // Check matches
+					keys, input, matched = append(keys, key), input[len(sequence):], true // Append key
+
+					break // Break
+				}
+			}
+
+			switch {
+			case matched:
+				continue // Continue
+			case len(input) > 1 && (input[1] == '[' || input[1] == 'O'): // Check unknown sequence
+				end := 2 // Init end buffer
+
+				for end < len(input) && (input[end] < 0x40 || input[end] > 0x7e) { // Find final byte
+					end++ // Skip parameter byte
+				}
+
+				if end < len(input) { // Check has final byte
+					end++ // Skip final byte
+				}
+
+				input = input[end:] // Skip sequence
+			default:
+				keys, input = append(keys, "esc"), input[1:] // Append escape
+			}
+
+			continue // Continue
+		}
+
+		r, size := utf8.DecodeRune(input) // Decode character
+
+		input = input[size:] // Consume character
+
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, "enter") // Append enter
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace") // Append backspace
+		case 0x03:
+			keys = append(keys, "ctrl-c") // Append interrupt
+		default:
+			if unicode.IsPrint(r) { // Check printable
+				keys = append(keys, string(r)) // Append character
+			}
+		}
+	}
+
+	return keys // Return keys
+}
+
+// push shows a new list of results, keeping the current list to return to.
+func (browser *resultBrowser) push(title string, results []*searchResult) {
+	view := &resultView{title: title, results: results} // Init view
+
+	view.apply("") // Show every result
+
+	browser.views = append(browser.views, view) // Push view
+}
+
+// view gets the shown list of results.
+func (browser *resultBrowser) view() *resultView {
+	return browser.views[len(browser.views)-1] // Return last view
+}
+
+// handle handles a key, returning false if the browser should quit.
+func (browser *resultBrowser) handle(key string) bool {
+	view := browser.view() // Get view
+
+	browser.status = "" // Clear status
+
+	if browser.filtering { // Check typing filter
+		switch key {
+		case "enter":
+			browser.filtering = false // Finish filter
+
+			view.apply(browser.filter) // Apply filter
+		case "esc", "ctrl-c":
+			browser.filtering = false // Cancel filter
+		case "backspace":
+			if _, size := utf8.DecodeLastRuneInString(browser.filter); size > 0 { // Check has characters
+				browser.filter = browser.filter[:len(browser.filter)-size] // Remove last character
+			}
+		default:
+			if utf8.RuneCountInString(key) == 1 { // Check character
+				browser.filter += key // Append character
+			}
+		}
+
+		return true // Continue
+	}
+
+	page := browser.listHeight() // Get page size
+
+	switch key {
+	case "q", "ctrl-c":
+		return false // Quit
+	case "esc":
+		if view.filter != "" { // Check filtered
+			view.apply("") // Clear filter
+		} else if len(browser.views) > 1 { // Check has previous view
+			browser.views = browser.views[:len(browser.views)-1] // Pop view
+		} else {
+			return false // Quit
+		}
+	case "up", "k":
+		view.move(-1) // Move up
+	case "down", "j":
+		view.move(1) // Move down
+	case "pgup":
+		view.move(-page) // Move up a page
+	case "pgdn", " ":
+		view.move(page) // Move down a page
+	case "home", "g":
+		view.move(-len(view.visible)) // Move to first
+	case "end", "G":
+		view.move(len(view.visible)) // Move to last
+	case "/":
+		browser.filtering, browser.filter = true, view.filter // Start filter
+	case "enter":
+		browser.raw = !browser.raw // Toggle JSON
+	case "s", "r":
+		browser.jump(key == "s") // Jump to chain
+	case "b", "backspace", "left":
+		if len(browser.views) > 1 { // Check has previous view
+			browser.views = browser.views[:len(browser.views)-1] // Pop view
+		}
+	case "c", "y":
+		browser.copyHash() // Copy hash
+	}
+
+	return true // Continue
+}
+
+// jump shows the chain of the selected transaction's sender, or recipient.
+func (browser *resultBrowser) jump(sender bool) {
+	result := browser.view().selected() // Get selected result
+
+	if result == nil || result.Transaction == nil { // Check no transaction
+		browser.status = "Select a transaction to jump to its sender or recipient." // Set status
+
+		return // Return
+	}
+
+	address, role := result.Transaction.Recipient, "recipient" // Get recipient
+
+	if sender { // Check sender
+		address, role = result.Transaction.Sender, "sender" // Get sender
+	}
+
+	if address == nil { // Check no address
+		browser.status = fmt.Sprintf("This transaction has no %s.", role) // Set status
+
+		return // Return
+	}
+
+	chain, err := types.ReadChainFromMemory(*address) // Read chain
+
+	if err != nil { // Check for errors
+		browser.status = fmt.Sprintf("Could not read the chain of %s: %s", address.String(), err) // Set status
+
+		return // Return
+	}
+
+	file := fmt.Sprintf("%s/chain_%s.json", chainDir(), address.String()) // Get chain file
+
+	results := make([]*searchResult, 0, len(chain.Transactions)) // Init results buffer
+
+	for _, transaction := range chain.Transactions { // Iterate through transactions
+		if transaction != nil && transaction.Hash != nil { // Check valid transaction
+			results = append(results, &searchResult{ChainAddress: *address, File: file, Transaction: transaction}) // Append transaction
+		}
+	}
+
+	browser.push(fmt.Sprintf("chain of %s %s", role, address.String()), results) // Show chain
+}
+
+// copyHash copies the hash of the selected transaction, or the address of the selected chain, to the clipboard.
+func (browser *resultBrowser) copyHash() {
+	result := browser.view().selected() // Get selected result
+
+	if result == nil { // Check no result
+		return // Nothing to copy
+	}
+
+	value := result.ChainAddress.String() // Init value buffer
+
+	if result.Transaction != nil { // Check transaction
+		value = result.Transaction.Hash.String() // Set hash
+	}
+
+	for _, command := range clipboardCommands { // Iterate through clipboard commands
+		if _, err := exec.LookPath(command[0]); err != nil { // Check not installed
+			continue // Continue
+		}
+
+		copyCommand := exec.Command(command[0], command[1:]...) // Init command
+
+		copyCommand.Stdin = strings.NewReader(value) // Set input
+
+		if err := copyCommand.Run(); err == nil { // Check copied
+			browser.status = fmt.Sprintf("Copied %s to the clipboard.", value) // Set status
+
+			return // Return
+		}
+	}
+
+	fmt.Printf("\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(value))) // Ask terminal to copy
+
+	browser.status = fmt.Sprintf("Asked the terminal to copy %s.", value) // Set status
+}
+
+// listHeight gets the number of results listed at once.
+func (browser *resultBrowser) listHeight() int {
+	height := (browser.height - 3) / 2 // Give half the screen to the list
+
+	if height < 1 { // Check too small
+		height = 1 // List at least one result
+	}
+
+	return height // Return height
+}
+
+// render draws the browser.
+func (browser *resultBrowser) render() string {
+	view := browser.view() // Get view
+
+	listHeight := browser.listHeight() // Get list height
+
+	view.scroll(listHeight) // Keep selection visible
+
+	var screen strings.Builder // Init screen buffer
+
+	screen.WriteString("\x1b[H") // Move to top left
+
+	line := func(s string, style string) {
+		s = fit(s, browser.width) // Fit to width
+
+		if style != "" { // Check styled
+			s = style + s + strings.Repeat(" ", browser.width-utf8.RuneCountInString(s)) + "\x1b[0m" // Style line
+		}
+
+		screen.WriteString(s + "\x1b[K\r\n") // Write line
+	}
+
+	title := fmt.Sprintf(" %s: %d results", view.title, len(view.results)) // Init title
+
+	if view.filter != "" { // Check filtered
+		title += fmt.Sprintf(" (%d matching %q)", len(view.visible), view.filter) // Add filter
+	}
+
+	line(title, "\x1b[1;7m") // Write title
+
+	for row := 0; row < listHeight; row++ { // Iterate through rows
+		position := view.offset + row // Get position
+
+		switch {
+		case position >= len(view.visible):
+			line("", "") // Write empty row
+		case position == view.cursor:
+			line(describeResult(view.visible[position], view.results[view.visible[position]]), "\x1b[7m") // Write selected row
+		default:
+			line(describeResult(view.visible[position], view.results[view.visible[position]]), "") // Write row
+		}
+	}
+
+	line(strings.Repeat("─", browser.width), "") // Write separator
+
+	detail := browser.detail() // Get detail
+
+	for row := 0; row < browser.height-listHeight-3; row++ { // Iterate through detail rows
+		if row < len(detail) { // Check has line
+			line(detail[row], "") // Write detail line
+		} else {
+			line("", "") // Write empty line
+		}
+	}
+
+	switch {
+	case browser.filtering:
+		screen.WriteString(fit("/"+browser.filter, browser.width) + "\x1b[K") // Write filter prompt
+	case browser.status != "":
+		screen.WriteString(fit(browser.status, browser.width) + "\x1b[K") // Write status
+	default:
+		screen.WriteString("\x1b[2m" + fit("↑↓ move  PgUp/PgDn page  / filter  enter JSON  s/r jump to sender/recipient  b back  c copy  q quit", browser.width) + "\x1b[0m\x1b[K") // Write help
+	}
+
+	return screen.String() // Return screen
+}
+
+// detail gets the lines describing the selected result.
+func (browser *resultBrowser) detail() []string {
+	result := browser.view().selected() // Get selected result
+
+	if result == nil { // Check no result
+		return []string{"No results."} // Return empty detail
+	}
+
+	if browser.raw { // Check JSON
+		return wrap(strings.Split(result.String(), "\n"), browser.width) // Return JSON
+	}
+
+	lines := []string{
+		"Chain:      " + result.ChainAddress.String(), // Write chain
+		"File:       " + result.File,                  // Write file
+	} // Init lines
+
+	if result.Chain != nil { // Check chain match
+		return append(lines, fmt.Sprintf("Transactions: %d", len(result.Chain.Transactions))) // Return chain detail
+	}
+
+	transaction := result.Transaction // Get transaction
+
+	lines = append(lines, "Hash:       "+transaction.Hash.String()) // Write hash
+
+	for _, address := range []struct {
+		label   string
+		address *summercashCommon.Address
+	}{{"Sender:     ", transaction.Sender}, {"Recipient:  ", transaction.Recipient}} { // Iterate through addresses
+		if address.address != nil { // Check has address
+			lines = append(lines, address.label+address.address.String()) // Write address
+		} else {
+			lines = append(lines, address.label+"-") // Write missing address
+		}
+	}
+
+	if transaction.Amount != nil { // Check has amount
+		lines = append(lines, "Amount:     "+transaction.Amount.Text('f', -1)) // Write amount
+	}
+
+	lines = append(lines, fmt.Sprintf("Nonce:      %d", transaction.AccountNonce), "Timestamp:  "+transaction.Timestamp.Format(time.RFC3339)) // Write nonce, timestamp
+
+	if len(result.Reasons) > 0 { // Check has reasons
+		lines = append(lines, "Matched:    "+strings.Join(result.Reasons, "; ")) // Write reasons
+	}
+
+	if utf8.Valid(transaction.Payload) { // Check text payload
+		lines = append(lines, "Payload:") // Write payload label
+
+		lines = append(lines, wrap(strings.Split(printable(string(transaction.Payload)), "\n"), browser.width)...) // Write payload
+	} else {
+		lines = append(lines, "Payload (hex):") // Write payload label
+
+		lines = append(lines, wrap([]string{fmt.Sprintf("%x", transaction.Payload)}, browser.width)...) // Write payload
+	}
+
+	return lines // Return lines
+}
+
+// apply filters a view to the results whose fields contain a given string, ignoring case.
+func (view *resultView) apply(filter string) {
+	view.filter, view.visible, view.cursor, view.offset = filter, nil, 0, 0 // Reset view
+
+	for i, result := range view.results { // Iterate through results
+		if filter == "" || strings.Contains(strings.ToLower(strings.Join(result.record().fields(), " ")), strings.ToLower(filter)) { // Check matches
+			view.visible = append(view.visible, i) // Show result
+		}
+	}
+}
+
+// move moves the selection by a given number of results.
+func (view *resultView) move(delta int) {
+	view.cursor += delta // Move cursor
+
+	if view.cursor >= len(view.visible) { // Check past end
+		view.cursor = len(view.visible) - 1 // Select last
+	}
+
+	if view.cursor < 0 { // Check before start
+		view.cursor = 0 // Select first
+	}
+}
+
+// scroll scrolls a view listing a given number of results so that the selected result is listed.
+func (view *resultView) scroll(rows int) {
+	if view.cursor < view.offset { // Check above list
+		view.offset = view.cursor // Scroll up
+	} else if view.cursor >= view.offset+rows { // Check below list
+		view.offset = view.cursor - rows + 1 // Scroll down
+	}
+}
+
+// selected gets the selected result, or nil if no result is visible.
+func (view *resultView) selected() *searchResult {
+	if len(view.visible) == 0 { // Check no results
+		return nil // No result
+	}
+
+	return view.results[view.visible[view.cursor]] // Return result
+}
+
+// describeResult describes a result in a single list row.
+func describeResult(position int, result *searchResult) string {
+	if result.Transaction == nil { // Check chain match
+		return fmt.Sprintf("%5d  chain %s", position, result.ChainAddress.String()) // Return chain
+	}
+
+	transaction := result.Transaction // Get transaction
+
+	amount := "-" // Init amount buffer
+
+	if transaction.Amount != nil { // Check has amount
+		amount = transaction.Amount.Text('f', -1) // Set amount
+	}
+
+	sender, recipient := "-", "-" // Init addresses buffer
+
+	if transaction.Sender != nil { // Check has sender
+		sender = shorten(transaction.Sender.String()) // Set sender
+	}
+
+	if transaction.Recipient != nil { // Check has recipient
+		recipient = shorten(transaction.Recipient.String()) // Set recipient
+	}
+
+	return fmt.Sprintf("%5d  %s  %14s  %s → %s", position, shorten(transaction.Hash.String()), amount, sender, recipient) // Return transaction
+}
+
+// shorten abbreviates a hash or address to its first and last characters.
+func shorten(s string) string {
+	if len(s) <= 16 { // Check already short
+		return s // Return unchanged
+	}
+
+	return s[:10] + "…" + s[len(s)-4:] // Return shortened
+}
+
+// fit truncates a line to a given number of columns.
+func fit(s string, width int) string {
+	if width < 1 || utf8.RuneCountInString(s) <= width { // Check fits
+		return s // Return unchanged
+	}
+
+	return string([]rune(s)[:width-1]) + "…" // Return truncated
+}
+
+// wrap splits lines longer than a given number of columns.
+func wrap(lines []string, width int) []string {
+	var wrapped []string // Init wrapped buffer
+
+	for _, line := range lines { // Iterate through lines
+		runes := []rune(line) // Get characters
+
+		for width > 0 && len(runes) > width { // Iterate while too long
+			wrapped, runes = append(wrapped, string(runes[:width])), runes[width:] // Split line
+		}
+
+		wrapped = append(wrapped, string(runes)) // Append remainder
+	}
+
+	return wrapped // Return wrapped
+}
+
+// printable replaces the control characters in a string, other than newlines, with dots.
+func printable(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\n' && !unicode.IsPrint(r) { // Check control character
+			return '.' // Replace
+		}
+
+		return r // Keep
+	}, s) // Return printable
+}
+
+/* END INTERNAL METHODS */
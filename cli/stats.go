@@ -0,0 +1,314 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/fork"
+	"github.com/SummerCash/puppet/stats"
+)
+
+// statsOutput represents the machine-readable analytics of a network.
+type statsOutput struct {
+	NetworkID uint `json:"networkID"` // Network ID
+	Chains    int  `json:"chains"`    // Number of chains analyzed
+
+	GenesisAlloc string `json:"genesisAlloc"` // Supply issued at genesis
+	Supply       string `json:"supply"`       // Sum of every account balance
+	Minted       string `json:"minted"`       // Supply created since genesis
+
+	Transactions        int    `json:"transactions"`        // Number of distinct non-genesis transactions
+	GenesisTransactions int    `json:"genesisTransactions"` // Number of genesis transactions
+	Volume              string `json:"volume"`              // Total amount of the non-genesis transactions
+
+	Inflation *statsInflation `json:"inflation"` // Configured vs. realised inflation
+
+	Accounts []*statsAccount `json:"accounts"` // Accounts, ordered by balance
+
+	Interval stats.Interval `json:"interval"` // Activity period length
+	Periods  []*statsPeriod `json:"periods"`  // Activity per period
+
+	Errors []*statsError `json:"errors,omitempty"` // Chains that could not be read
+}
+
+// statsInflation represents the machine-readable inflation of a network.
+type statsInflation struct {
+	Configured     float64            `json:"configured"`           // Configured yearly rate
+	Changes        []*statsRateChange `json:"changes,omitempty"`    // Changes to the configured rate
+	Realised       float64            `json:"realised"`             // Realised supply growth since genesis
+	Annualised     *float64           `json:"annualised,omitempty"` // Realised yearly rate
+	ExpectedSupply string             `json:"expectedSupply"`       // Supply at the configured rate
+	Since          time.Time          `json:"since"`                // Genesis time
+	Until          time.Time          `json:"until"`                // Time the analytics were computed at
+}
+
+// statsRateChange represents a machine-readable change to the configured inflation rate of a network.
+type statsRateChange struct {
+	At   time.Time `json:"at"`   // Time the rate takes effect
+	Rate float64   `json:"rate"` // Yearly rate from then on
+}
+
+// statsAccount represents the machine-readable balance and activity of an account.
+type statsAccount struct {
+	Address  string  `json:"address"`         // Account address
+	Balance  string  `json:"balance"`         // Balance
+	Share    float64 `json:"share"`           // Fraction of the supply held
+	Alloc    string  `json:"alloc,omitempty"` // Genesis allocation
+	Sent     int     `json:"sent"`            // Number of transactions sent
+	Received int     `json:"received"`        // Number of transactions received
+}
+
+// statsPeriod represents the machine-readable activity in a period.
+type statsPeriod struct {
+	Start        time.Time `json:"start"`        // Period start
+	Transactions int       `json:"transactions"` // Number of transactions
+	Volume       string    `json:"volume"`       // Total amount transacted
+}
+
+// statsError represents a chain that could not be analyzed.
+type statsError struct {
+	Chain string `json:"chain"` // Chain account address
+	Error string `json:"error"` // Error
+}
+
+/* BEGIN EXPORTED METHODS */
+
+// SetupStatsCommand sets up the stats CLI command.
+func (app *CLI) SetupStatsCommand() {
+	(*app).App.Commands = append((*app).App.Commands, cli.Command{
+		Name:   "stats",                                                                 // Set name
+		Usage:  "report a network's supply, balances, activity, and realised inflation", // Set usage
+		Action: app.networkStats,                                                        // Set action
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:        "data-dir, data",                 // Set name
+				Value:       common.DataDir,                   // Set value
+				Usage:       "path of the network to analyze", // Set usage
+				Destination: &common.DataDir,                  // Set destination
+			},
+			cli.IntFlag{
+				Name:  "top",                                                                // Set name
+				Value: 10,                                                                   // Set value
+				Usage: "number of top holders to list in the table (0 lists every account)", // Set usage
+			},
+			cli.StringFlag{
+				Name:  "interval",                                          // Set name
+				Value: string(stats.Day),                                   // Set value
+				Usage: "period to group activity by (day, week, or month)", // Set usage
+			},
+			cli.BoolFlag{
+				Name:  "json",                          // Set name
+				Usage: "print every statistic as JSON", // Set usage
+			},
+		},
+	})
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// networkStats handles the stats command.
+func (app *CLI) networkStats(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	interval, err := stats.ParseInterval(c.String("interval")) // Parse interval
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	chainConfig, err := config.ReadChainConfigFromMemory() // Read chain config
+
+	if err != nil { // Check for errors
+		return fmt.Errorf("could not read the chain config of %s: %s", common.DataDir, err) // Return error
+	}
+
+	chains, chainErrors, err := readLocalChains() // Read chains
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	changes, err := inflationChanges(chains) // Get scheduled inflation changes
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	report := stats.Compute(chains, chainConfig, changes, interval, time.Now().UTC()) // Compute analytics
+
+	output := newStatsOutput(chainConfig, report, interval, chainErrors) // Convert analytics
+
+	if c.Bool("json") { // Check JSON
+		return printJSON(output) // Print analytics
+	}
+
+	reportChainErrors(os.Stderr, chainErrors) // Report unreadable chains
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) // Init table writer
+
+	fmt.Fprintf(writer, "NETWORK ID\t%d\n", output.NetworkID)                                                                                                     // Write network ID
+	fmt.Fprintf(writer, "CHAINS\t%d\n", output.Chains)                                                                                                            // Write chains
+	fmt.Fprintf(writer, "GENESIS ALLOC\t%s\n", output.GenesisAlloc)                                                                                               // Write genesis alloc
+	fmt.Fprintf(writer, "SUPPLY\t%s\n", output.Supply)                                                                                                            // Write supply
+	fmt.Fprintf(writer, "MINTED\t%s\n", output.Minted)                                                                                                            // Write minted
+	fmt.Fprintf(writer, "TRANSACTIONS\t%d (plus %d genesis)\n", output.Transactions, output.GenesisTransactions)                                                  // Write transactions
+	fmt.Fprintf(writer, "VOLUME\t%s\n", output.Volume)                                                                                                            // Write volume
+	fmt.Fprintf(writer, "INFLATION\t%s per year configured, %s realised since genesis", percent(output.Inflation.Configured), percent(output.Inflation.Realised)) // Write inflation
+
+	if output.Inflation.Annualised != nil { // Check annualised
+		fmt.Fprintf(writer, " (%s per year)", percent(*output.Inflation.Annualised)) // Write annualised
+	}
+
+	for _, change := range output.Inflation.Changes { // Iterate through rate changes
+		fmt.Fprintf(writer, "\n\tchanged to %s per year at %s by a scheduled fork", percent(change.Rate), change.At.Format(time.RFC3339)) // Write rate change
+	}
+
+	fmt.Fprintf(writer, "\nEXPECTED SUPPLY\t%s\n", output.Inflation.ExpectedSupply) // Write expected supply
+
+	accounts := output.Accounts // Get accounts
+
+	if top := c.Int("top"); top > 0 && top < len(accounts) { // Check limited
+		accounts = accounts[:top] // Limit accounts
+	}
+
+	fmt.Fprintf(writer, "\nTOP HOLDERS (%d of %d)\n", len(accounts), len(output.Accounts)) // Write holders header
+	fmt.Fprintln(writer, "RANK\tADDRESS\tBALANCE\tSHARE\tGENESIS ALLOC\tSENT\tRECEIVED")   // Write columns
+
+	for i, account := range accounts { // Iterate through accounts
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%d\t%d\n", i+1, account.Address, account.Balance, percent(account.Share), orDash(account.Alloc), account.Sent, account.Received) // Write account
+	}
+
+	fmt.Fprintf(writer, "\nACTIVITY (per %s)\n", interval) // Write activity header
+
+	if len(output.Periods) == 0 { // Check no activity
+		fmt.Fprintln(writer, "no transactions since genesis") // Write no activity
+	} else {
+		fmt.Fprintln(writer, "PERIOD\tTRANSACTIONS\tVOLUME") // Write columns
+	}
+
+	for _, period := range output.Periods { // Iterate through periods
+		fmt.Fprintf(writer, "%s\t%d\t%s\n", formatPeriod(period.Start, interval), period.Transactions, period.Volume) // Write period
+	}
+
+	return writer.Flush() // Flush table
+}
+
+// newStatsOutput converts the analytics of a network to their machine-readable representation.
+func newStatsOutput(chainConfig *config.ChainConfig, report *stats.Report, interval stats.Interval, chainErrors []*chainError) *statsOutput {
+	output := &statsOutput{
+		NetworkID:           chainConfig.NetworkID,             // Set network ID
+		Chains:              report.Chains,                     // Set chains
+		GenesisAlloc:        formatAmount(report.GenesisAlloc), // Set genesis alloc
+		Supply:              formatAmount(report.Supply),       // Set supply
+		Minted:              formatAmount(report.Minted),       // Set minted
+		Transactions:        report.Transactions,               // Set transactions
+		GenesisTransactions: report.GenesisTransactions,        // Set genesis transactions
+		Volume:              formatAmount(report.Volume),       // Set volume
+		Interval:            interval,                          // Set interval
+		Accounts:            []*statsAccount{},                 // Init accounts
+		Periods:             []*statsPeriod{},                  // Init periods
+		Inflation: &statsInflation{
+			Configured:     report.Inflation.Configured, // Set configured
+			Realised:       report.Inflation.Realised,   // Set realised
+			Annualised:     report.Inflation.Annualised, // Set annualised
+			ExpectedSupply: "0",                         // Init expected supply
+			Since:          report.Inflation.Since,      // Set since
+			Until:          report.Inflation.Until,      // Set until
+		}, // Set inflation
+	} // Init output
+
+	for _, change := range report.Inflation.Changes { // Iterate through rate changes
+		output.Inflation.Changes = append(output.Inflation.Changes, &statsRateChange{At: change.At, Rate: change.Rate}) // Append change
+	}
+
+	if report.Inflation.ExpectedSupply != nil { // Check has expected supply
+		output.Inflation.ExpectedSupply = formatAmount(report.Inflation.ExpectedSupply) // Set expected supply
+	}
+
+	for _, account := range report.Accounts { // Iterate through accounts
+		converted := &statsAccount{Address: account.Address, Balance: formatAmount(account.Balance), Share: account.Share, Sent: account.Sent, Received: account.Received} // Convert account
+
+		if account.Alloc != nil { // Check has alloc
+			converted.Alloc = formatAmount(account.Alloc) // Set alloc
+		}
+
+		output.Accounts = append(output.Accounts, converted) // Append account
+	}
+
+	for _, period := range report.Periods { // Iterate through periods
+		output.Periods = append(output.Periods, &statsPeriod{Start: period.Start, Transactions: period.Transactions, Volume: formatAmount(period.Volume)}) // Append period
+	}
+
+	for _, chainErr := range chainErrors { // Iterate through chain errors
+		output.Errors = append(output.Errors, &statsError{Chain: chainErr.Chain, Error: chainErr.Err.Error()}) // Append error
+	}
+
+	return output // Return output
+}
+
+// inflationChanges gets the changes to the configured inflation rate made by the forks scheduled for the network in
+// the current data dir. A fork activated by a transaction count takes effect, for supply purposes, from the first time
+// any chain reached it; one that no chain has reached yet is left out.
+func inflationChanges(chains []*types.Chain) ([]*stats.RateChange, error) {
+	schedule, err := fork.ReadSchedule(common.DataDir) // Read schedule
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	var changes []*stats.RateChange // Init changes buffer
+
+	for _, spec := range schedule { // Iterate through schedule
+		if spec.Config == nil || spec.Config.InflationRate == nil || spec.Activation == nil { // Check doesn't change inflation
+			continue // Continue
+		}
+
+		if at := spec.Activation.Effective(chains); !at.IsZero() { // Check has activation time
+			changes = append(changes, &stats.RateChange{At: at, Rate: *spec.Config.InflationRate}) // Append change
+		}
+	}
+
+	return changes, nil // Return changes
+}
+
+// percent formats a fraction as a percentage.
+func percent(fraction float64) string {
+	formatted := strconv.FormatFloat(fraction*100, 'f', 2, 64) // Format percentage
+
+	formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".") // Trim trailing zeros
+
+	if formatted == "-0" { // Check rounded negative zero
+		formatted = "0" // Drop sign
+	}
+
+	return formatted + "%" // Return percentage
+}
+
+// formatPeriod formats the start of an activity period.
+func formatPeriod(start time.Time, interval stats.Interval) string {
+	switch interval {
+	case stats.Week:
+		return "week of " + start.Format("2006-01-02") // Return week
+	case stats.Month:
+		return start.Format("2006-01") // Return month
+	}
+
+	return start.Format("2006-01-02") // Return day
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,145 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/urfave/cli"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/verify"
+)
+
+// verifyOutput represents the machine-readable result of verifying a network.
+type verifyOutput struct {
+	Chains       int                `json:"chains"`       // Number of chains verified
+	Transactions int                `json:"transactions"` // Number of transactions verified
+	Violations   []*verifyViolation `json:"violations"`   // Violations
+}
+
+// verifyViolation represents a machine-readable integrity violation.
+type verifyViolation struct {
+	File        string       `json:"file"`                  // Path of the chain file holding the violation
+	Chain       string       `json:"chain"`                 // Chain account address
+	Transaction string       `json:"transaction,omitempty"` // Transaction hash
+	Check       verify.Check `json:"check"`                 // Failed check
+	Message     string       `json:"message"`               // Description of the violation
+}
+
+// ErrIntegrityViolations is an error definition describing a network whose chains failed verification.
+var ErrIntegrityViolations = errors.New("blockmesh failed verification")
+
+/* BEGIN EXPORTED METHODS */
+
+// SetupVerifyCommand sets up the verify CLI command.
+func (app *CLI) SetupVerifyCommand() {
+	(*app).App.Commands = append((*app).App.Commands, cli.Command{
+		Name:   "verify",                                                                            // Set name
+		Usage:  "check chain IDs, transaction hashes and signatures, genesis, and cross-references", // Set usage
+		Action: app.verifyNetwork,                                                                   // Set action
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:        "data-dir, data",                // Set name
+				Value:       common.DataDir,                  // Set value
+				Usage:       "path of the network to verify", // Set usage
+				Destination: &common.DataDir,                 // Set destination
+			},
+			cli.BoolFlag{
+				Name:  "json",                          // Set name
+				Usage: "print every violation as JSON", // Set usage
+			},
+		},
+	})
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// verifyNetwork handles the verify command.
+func (app *CLI) verifyNetwork(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	chainConfig, err := config.ReadChainConfigFromMemory() // Read chain config
+
+	if err != nil { // Check for errors
+		return fmt.Errorf("could not read the chain config of %s: %s", common.DataDir, err) // Return error
+	}
+
+	chains, chainErrors, err := readLocalChains() // Read chains
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	report := verify.Verify(chainDir(), chains, chainConfig) // Verify chains
+
+	output := &verifyOutput{Chains: len(chains) + len(chainErrors), Transactions: report.Transactions, Violations: []*verifyViolation{}} // Init output
+
+	for _, chainErr := range chainErrors { // Iterate through unreadable chains
+		output.Violations = append(output.Violations, &verifyViolation{File: verify.File(chainDir(), chainErr.Chain), Chain: chainErr.Chain, Check: verify.CheckChainFile, Message: chainErr.Err.Error()}) // Append violation
+	}
+
+	for _, violation := range report.Violations { // Iterate through violations
+		output.Violations = append(output.Violations, &verifyViolation{File: violation.File, Chain: violation.Chain, Transaction: violation.Transaction, Check: violation.Check, Message: violation.Message}) // Append violation
+	}
+
+	sort.SliceStable(output.Violations, func(i, j int) bool { return output.Violations[i].File < output.Violations[j].File }) // Group by file
+
+	if c.Bool("json") { // Check JSON
+		err = printJSON(output) // Print result
+	} else {
+		err = writeViolations(output) // Print result
+	}
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if len(output.Violations) > 0 { // Check failed
+		return fmt.Errorf("%s: %d violations", ErrIntegrityViolations, len(output.Violations)) // Return error
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// writeViolations writes the result of verifying a network as a table, grouped by file.
+func writeViolations(output *verifyOutput) error {
+	if len(output.Violations) == 0 { // Check passed
+		fmt.Printf("verified %d chains and %d transactions; no violations found\n", output.Chains, output.Transactions) // Log success
+
+		return nil // No error occurred, return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) // Init table writer
+
+	lastFile := "" // Init last file buffer
+
+	for _, violation := range output.Violations { // Iterate through violations
+		if violation.File != lastFile { // Check new file
+			if lastFile != "" { // Check not first file
+				fmt.Fprintln(writer) // Separate files
+			}
+
+			fmt.Fprintf(writer, "%s\n", violation.File) // Write file
+
+			lastFile = violation.File // Set last file
+		}
+
+		fmt.Fprintf(writer, "  %s\t%s\t%s\n", violation.Check, orDash(violation.Transaction), violation.Message) // Write violation
+	}
+
+	fmt.Fprintf(writer, "\nverified %d chains and %d transactions; found %d violations\n", output.Chains, output.Transactions, len(output.Violations)) // Write summary
+
+	return writer.Flush() // Flush table
+}
+
+/* END INTERNAL METHODS */
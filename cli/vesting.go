@@ -0,0 +1,196 @@
+// Package cli defines helpful cli helper methods.
+package cli
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/puppet/common"
+	"github.com/SummerCash/puppet/genesis"
+	"github.com/SummerCash/puppet/vesting"
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// SetupVestingCommand sets up the vesting CLI command.
+func (app *CLI) SetupVestingCommand() {
+	(*app).App.Commands = append((*app).App.Commands, cli.Command{
+		Name:  "vesting",                                    // Set name
+		Usage: "inspect the vesting of genesis allocations", // Set usage
+		Subcommands: []cli.Command{
+			{
+				Name:      "status",                                                                     // Set name
+				Usage:     "report the unlocked and locked portions of an account's genesis allocation", // Set usage
+				ArgsUsage: "ADDRESS",                                                                    // Set args usage
+				Action:    app.vestingStatus,                                                            // Set action
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:        "data-dir, data",                 // Set name
+						Value:       common.DataDir,                   // Set value
+						Usage:       "path of the network to inspect", // Set usage
+						Destination: &common.DataDir,                  // Set destination
+					},
+					cli.StringFlag{
+						Name:  "at",                                                  // Set name
+						Usage: "RFC 3339 time to report the status at (default now)", // Set usage
+					},
+				},
+			},
+		},
+	})
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// vestingStatus handles the vesting status command.
+func (app *CLI) vestingStatus(c *cli.Context) error {
+	summercashCommon.Silent = true // Silence logs
+
+	summercashCommon.DataDir = common.DataDir // Set smc data dir
+
+	address, err := addressArg(c) // Get address
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	at := time.Now().UTC() // Init time buffer
+
+	if c.String("at") != "" { // Check has time flag
+		at, err = time.Parse(time.RFC3339, c.String("at")) // Parse time
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+	}
+
+	locks, err := vesting.ReadLocks(vestingPath()) // Read locks
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	schedule := &vesting.Schedule{} // Init schedule buffer
+
+	var balance *big.Float // Init balance buffer
+
+	if lock, err := vesting.FindLock(locks, address.String()); err == nil { // Check has lock
+		schedule = lock.Schedule // Set schedule
+
+		balance, _ = new(big.Float).SetPrec(350).SetString(lock.Balance) // Parse balance
+	} else {
+		chainConfig, configErr := config.ReadChainConfigFromMemory() // Read chain config
+
+		if configErr != nil || chainConfig.Alloc[address.String()] == nil { // Check not allocated
+			return err // Return no lock error
+		}
+
+		balance = chainConfig.Alloc[address.String()] // Set balance
+	}
+
+	if balance == nil { // Check invalid balance
+		return fmt.Errorf("invalid vesting balance for %s", address.String()) // Return error
+	}
+
+	status := schedule.Status(balance, at) // Get status
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) // Init table writer
+
+	fmt.Fprintf(writer, "ADDRESS\t%s\n", address.String())               // Write address
+	fmt.Fprintf(writer, "SCHEDULE\t%s\n", describeSchedule(schedule))    // Write schedule
+	fmt.Fprintf(writer, "AT\t%s\n", at.Format(time.RFC3339))             // Write time
+	fmt.Fprintf(writer, "TOTAL\t%s\n", formatAmount(status.Total))       // Write total
+	fmt.Fprintf(writer, "UNLOCKED\t%s\n", formatAmount(status.Unlocked)) // Write unlocked
+	fmt.Fprintf(writer, "LOCKED\t%s\n", formatAmount(status.Locked))     // Write locked
+
+	if status.NextUnlock != nil { // Check has next unlock
+		fmt.Fprintf(writer, "NEXT UNLOCK\t%s\n", status.NextUnlock.Format(time.RFC3339)) // Write next unlock
+	}
+
+	if status.FullyUnlocked != nil { // Check still locked
+		fmt.Fprintf(writer, "FULLY UNLOCKED\t%s\n", status.FullyUnlocked.Format(time.RFC3339)) // Write fully unlocked
+	}
+
+	return writer.Flush() // Flush table
+}
+
+// describeSchedule describes a vesting schedule in a single line.
+func describeSchedule(schedule *vesting.Schedule) string {
+	switch {
+	case len(schedule.Unlocks) > 0:
+		return fmt.Sprintf("%d fixed unlocks", len(schedule.Unlocks)) // Return fixed unlocks
+	case schedule.Start != nil:
+		return fmt.Sprintf("linear from %s over %s, %s cliff", schedule.Start.Format(time.RFC3339), schedule.Duration, schedule.Cliff) // Return linear release
+	}
+
+	return "none" // No schedule
+}
+
+// formatAmount formats an amount, rounded to 18 decimal places.
+func formatAmount(amount *big.Float) string {
+	formatted := amount.Text('f', 18) // Round amount
+
+	if strings.Contains(formatted, ".") { // Check has fraction
+		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".") // Trim trailing zeros
+	}
+
+	return formatted // Return formatted
+}
+
+// recordVesting records the vesting schedules of a given alloc's entries in the current data dir.
+func recordVesting(alloc genesis.Alloc) error {
+	var locks []*vesting.Lock // Init locks buffer
+
+	for _, entry := range alloc { // Iterate through entries
+		if entry.Vesting != nil { // Check has vesting schedule
+			locks = append(locks, &vesting.Lock{Address: entry.Address, Balance: entry.Balance, Schedule: entry.Vesting}) // Append lock
+		}
+	}
+
+	if len(locks) == 0 { // Check no locks
+		return nil // Nothing to record
+	}
+
+	err := summercashCommon.CreateDirIfDoesNotExist(filepath.Dir(vestingPath())) // Create config dir
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return vesting.WriteLocks(vestingPath(), locks) // Write locks
+}
+
+// applyVesting sets the vesting schedule of each entry in a given alloc to that recorded when the network was created.
+func applyVesting(alloc genesis.Alloc) error {
+	locks, err := vesting.ReadLocks(vestingPath()) // Read locks
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	for _, entry := range alloc { // Iterate through entries
+		if lock, err := vesting.FindLock(locks, entry.Address); err == nil { // Check has lock
+			entry.Vesting = lock.Schedule // Set schedule
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// vestingPath gets the path of the file the vesting schedules of the genesis allocations in the current data dir are recorded in.
+func vestingPath() string {
+	return filepath.FromSlash(fmt.Sprintf("%s/config/vesting.json", common.DataDir)) // Return path
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,210 @@
+// Package common defines common helper methods and variables.
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+var (
+	// ErrEmptySeed is an error definition describing an attempt to derive keys from an empty seed.
+	ErrEmptySeed = errors.New("seed must not be empty")
+
+	// one is a big.Int with the value 1.
+	one = big.NewInt(1)
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// NewSeededReader initializes a deterministic reader of pseudo-random bytes, derived from a given seed
+// via HKDF-SHA256. Readers for different purposes must be initialized with different info values.
+func NewSeededReader(seed []byte, info string) (io.Reader, error) {
+	if len(seed) == 0 { // Check empty seed
+		return nil, ErrEmptySeed // Return error
+	}
+
+	return hkdf.New(sha256.New, seed, nil, []byte("puppet/"+info)), nil // Return reader
+}
+
+// GenerateKey generates an ECDSA private key using randomness from a given reader.
+// Unlike ecdsa.GenerateKey, the generated key is fully determined by the bytes read, which allows
+// keys to be derived from a seeded reader.
+func GenerateKey(curve elliptic.Curve, random io.Reader) (*ecdsa.PrivateKey, error) {
+	params := curve.Params() // Get curve params
+
+	b := make([]byte, params.BitSize/8+8) // Init buffer, with extra bytes to reduce bias
+
+	_, err := io.ReadFull(random, b) // Read random bytes
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	d := new(big.Int).SetBytes(b) // Get scalar
+
+	n := new(big.Int).Sub(params.N, one) // Get N - 1
+
+	d.Mod(d, n)   // Reduce into [0, N - 2]
+	d.Add(d, one) // Shift into [1, N - 1]
+
+	privateKey := &ecdsa.PrivateKey{D: d} // Init private key
+
+	privateKey.PublicKey.Curve = curve                                               // Set curve
+	privateKey.PublicKey.X, privateKey.PublicKey.Y = curve.ScalarBaseMult(d.Bytes()) // Set public key
+
+	return privateKey, nil // Return private key
+}
+
+// SignDeterministic signs a given hash with a private key, deriving the nonce from the key and hash
+// as described in RFC 6979 (with HMAC-SHA256), such that the same key and hash always yield the same signature.
+// The resulting signature can be verified with ecdsa.Verify.
+func SignDeterministic(privateKey *ecdsa.PrivateKey, hash []byte) (*big.Int, *big.Int, error) {
+	n := privateKey.Curve.Params().N // Get curve order
+
+	e := hashToInt(hash, n) // Get hash as int
+
+	nonces := newNonceGenerator(privateKey.D, hash, n) // Init nonce generator
+
+	for {
+		k := nonces.next() // Get nonce
+
+		x, _ := privateKey.Curve.ScalarBaseMult(k.Bytes()) // Compute k * G
+
+		r := new(big.Int).Mod(x, n) // Get r
+
+		if r.Sign() == 0 { // Check invalid r
+			continue // Try next nonce
+		}
+
+		s := new(big.Int).Mul(r, privateKey.D)  // Compute r * d
+		s.Add(s, e)                             // Compute e + r * d
+		s.Mul(s, new(big.Int).ModInverse(k, n)) // Compute k^-1 * (e + r * d)
+		s.Mod(s, n)                             // Reduce
+
+		if s.Sign() == 0 { // Check invalid s
+			continue // Try next nonce
+		}
+
+		return r, s, nil // Return signature
+	}
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// nonceGenerator generates RFC 6979 nonces for a given key and hash.
+type nonceGenerator struct {
+	n *big.Int // Curve order
+
+	k []byte // HMAC key
+	v []byte // HMAC value
+}
+
+// newNonceGenerator initializes an RFC 6979 nonce generator for a given private scalar and hash.
+func newNonceGenerator(d *big.Int, hash []byte, n *big.Int) *nonceGenerator {
+	generator := &nonceGenerator{
+		n: n,                         // Set curve order
+		k: make([]byte, sha256.Size), // Set K = 0x00 0x00 ...
+		v: make([]byte, sha256.Size), // Set V = 0x01 0x01 ...
+	} // Init generator
+
+	for i := range generator.v { // Iterate through V
+		generator.v[i] = 0x01 // Set byte
+	}
+
+	x := int2octets(d, n)                                      // Get private key octets
+	h := int2octets(new(big.Int).Mod(bits2int(hash, n), n), n) // Get hash octets
+
+	generator.k = generator.mac(generator.k, generator.v, []byte{0x00}, x, h) // K = HMAC_K(V || 0x00 || x || h)
+	generator.v = generator.mac(generator.k, generator.v)                     // V = HMAC_K(V)
+	generator.k = generator.mac(generator.k, generator.v, []byte{0x01}, x, h) // K = HMAC_K(V || 0x01 || x || h)
+	generator.v = generator.mac(generator.k, generator.v)                     // V = HMAC_K(V)
+
+	return generator // Return generator
+}
+
+// next gets the next candidate nonce.
+func (generator *nonceGenerator) next() *big.Int {
+	qlen := generator.n.BitLen() // Get order length
+
+	for {
+		var t []byte // Init T buffer
+
+		for len(t)*8 < qlen { // Fill T
+			generator.v = generator.mac(generator.k, generator.v) // V = HMAC_K(V)
+
+			t = append(t, generator.v...) // Append V
+		}
+
+		k := bits2int(t, generator.n) // Get candidate
+
+		generator.k = generator.mac(generator.k, generator.v, []byte{0x00}) // K = HMAC_K(V || 0x00)
+		generator.v = generator.mac(generator.k, generator.v)               // V = HMAC_K(V)
+
+		if k.Sign() > 0 && k.Cmp(generator.n) < 0 { // Check in range
+			return k // Return candidate
+		}
+	}
+}
+
+// mac computes the HMAC-SHA256 of the concatenation of a set of byte slices.
+func (generator *nonceGenerator) mac(key []byte, data ...[]byte) []byte {
+	h := hmac.New(sha256.New, key) // Init HMAC
+
+	for _, b := range data { // Iterate through data
+		h.Write(b) // Write data
+	}
+
+	return h.Sum(nil) // Return sum
+}
+
+// bits2int converts a byte string to an integer of at most the bit length of n, as described in RFC 6979.
+func bits2int(b []byte, n *big.Int) *big.Int {
+	x := new(big.Int).SetBytes(b) // Get integer
+
+	if excess := len(b)*8 - n.BitLen(); excess > 0 { // Check too long
+		x.Rsh(x, uint(excess)) // Truncate
+	}
+
+	return x // Return integer
+}
+
+// int2octets converts an integer to a byte string of the byte length of n, as described in RFC 6979.
+func int2octets(x *big.Int, n *big.Int) []byte {
+	b := x.Bytes() // Get bytes
+
+	length := (n.BitLen() + 7) / 8 // Get byte length
+
+	if len(b) >= length { // Check long enough
+		return b[len(b)-length:] // Return trimmed
+	}
+
+	return append(make([]byte, length-len(b)), b...) // Return padded
+}
+
+// hashToInt converts a hash to an integer the way ecdsa.Sign and ecdsa.Verify do.
+func hashToInt(hash []byte, n *big.Int) *big.Int {
+	orderBytes := (n.BitLen() + 7) / 8 // Get order byte length
+
+	if len(hash) > orderBytes { // Check too long
+		hash = hash[:orderBytes] // Truncate
+	}
+
+	x := new(big.Int).SetBytes(hash) // Get integer
+
+	if excess := len(hash)*8 - n.BitLen(); excess > 0 { // Check too long
+		x.Rsh(x, uint(excess)) // Truncate
+	}
+
+	return x // Return integer
+}
+
+/* END INTERNAL METHODS */
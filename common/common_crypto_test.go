@@ -0,0 +1,145 @@
+// Package common defines common helper methods and variables.
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestNewSeededReader tests the functionality of the NewSeededReader() method.
+func TestNewSeededReader(t *testing.T) {
+	if _, err := NewSeededReader(nil, "genesis"); err != ErrEmptySeed { // Check empty seed accepted
+		t.Fatal("empty seeds must be rejected") // Panic
+	}
+}
+
+// TestGenerateKey tests that the GenerateKey() method derives the same key from the same seed.
+func TestGenerateKey(t *testing.T) {
+	var keys []*ecdsa.PrivateKey // Init keys buffer
+
+	for _, info := range []string{"genesis", "genesis", "faucet"} { // Iterate through purposes
+		reader, err := NewSeededReader([]byte("seed"), info) // Init reader
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		privateKey, err := GenerateKey(elliptic.P521(), reader) // Generate key
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		if !privateKey.Curve.IsOnCurve(privateKey.X, privateKey.Y) { // Check invalid public key
+			t.Fatal("public key must be on curve") // Panic
+		}
+
+		keys = append(keys, privateKey) // Append key
+	}
+
+	if keys[0].D.Cmp(keys[1].D) != 0 { // Check not deterministic
+		t.Fatal("keys derived from the same seed must match") // Panic
+	}
+
+	if keys[0].D.Cmp(keys[2].D) == 0 { // Check purposes not separated
+		t.Fatal("keys derived for different purposes must differ") // Panic
+	}
+}
+
+// TestSignDeterministic tests the functionality of the SignDeterministic() method.
+func TestSignDeterministic(t *testing.T) {
+	reader, err := NewSeededReader([]byte("seed"), "genesis") // Init reader
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	privateKey, err := GenerateKey(elliptic.P521(), reader) // Generate key
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	hash := sha256.Sum256([]byte("test")) // Hash message
+
+	r, s, err := SignDeterministic(privateKey, hash[:]) // Sign hash
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if !ecdsa.Verify(&privateKey.PublicKey, hash[:], r, s) { // Check invalid signature
+		t.Fatal("signature must be valid") // Panic
+	}
+
+	r2, s2, err := SignDeterministic(privateKey, hash[:]) // Sign hash again
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if r.Cmp(r2) != 0 || s.Cmp(s2) != 0 { // Check not deterministic
+		t.Fatal("signatures of the same hash must match") // Panic
+	}
+}
+
+// TestSignDeterministicVectors tests the SignDeterministic() method against the P-256, SHA-256 test vectors published
+// in RFC 6979, appendix A.2.5.
+func TestSignDeterministicVectors(t *testing.T) {
+	privateKey := &ecdsa.PrivateKey{D: testHexInt(t, "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721")} // Init private key
+
+	privateKey.PublicKey.Curve = elliptic.P256()                                                          // Set curve
+	privateKey.PublicKey.X, privateKey.PublicKey.Y = elliptic.P256().ScalarBaseMult(privateKey.D.Bytes()) // Set public key
+
+	if privateKey.X.Cmp(testHexInt(t, "60FED4BA255A9D31C961EB74C6356D68C049B8923B61FA6CE669622E60F29FB6")) != 0 { // Check invalid public key
+		t.Fatal("expected the public key from the RFC") // Panic
+	}
+
+	for _, vector := range []struct {
+		message string // Message signed
+		k       string // Expected nonce
+		r       string // Expected r
+		s       string // Expected s
+	}{
+		{"sample", "A6E3C57DD01ABE90086538398355DD4C3B17AA873382B0F24D6129493D8AAD60", "EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716", "F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8"},
+		{"test", "D16B6AE827F17175E040871A1C7EC3500192C4C92677336EC2537ACAEE0008E0", "F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367", "019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083"},
+	} { // Iterate through vectors
+		hash := sha256.Sum256([]byte(vector.message)) // Hash message
+
+		if k := newNonceGenerator(privateKey.D, hash[:], privateKey.Curve.Params().N).next(); k.Cmp(testHexInt(t, vector.k)) != 0 { // Check invalid nonce
+			t.Fatalf("%s: expected k = %s, found %X", vector.message, vector.k, k) // Panic
+		}
+
+		r, s, err := SignDeterministic(privateKey, hash[:]) // Sign hash
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		if r.Cmp(testHexInt(t, vector.r)) != 0 || s.Cmp(testHexInt(t, vector.s)) != 0 { // Check invalid signature
+			t.Fatalf("%s: expected (r, s) = (%s, %s), found (%X, %X)", vector.message, vector.r, vector.s, r, s) // Panic
+		}
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
+
+/* BEGIN INTERNAL METHODS */
+
+// testHexInt parses a hex-encoded integer.
+func testHexInt(t *testing.T, s string) *big.Int {
+	x, ok := new(big.Int).SetString(s, 16) // Parse integer
+
+	if !ok { // Check invalid integer
+		t.Fatalf("invalid hex integer %s", s) // Panic
+	}
+
+	return x // Return integer
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,502 @@
+// Package export defines helper methods for exporting the chains of a SummerCash network to other data stores.
+package export
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/stats"
+
+	_ "github.com/mattn/go-sqlite3" // Register the sqlite3 driver
+)
+
+// SchemaVersion is the version of the SQLite schema written by SQLite. Incremental exports into a database with
+// another schema version are refused.
+const SchemaVersion = 1
+
+// TimeFormat is the format transaction times are stored in: UTC, fixed-width (so that text ordering is chronological),
+// and understood by SQLite's date and time functions.
+const TimeFormat = "2006-01-02 15:04:05.000000000"
+
+// Network represents the data of a network to export.
+type Network struct {
+	Config *config.ChainConfig // Chain config
+	Chains []*types.Chain      // Chains
+	Roles  map[string]*Role    // Genesis roles, by account address
+}
+
+// Role represents the genesis role of an account.
+type Role struct {
+	Role string // Role (e.g. genesis, faucet, or treasury)
+	Name string // Name, if any
+}
+
+// Stats represents the result of an export.
+type Stats struct {
+	Chains       int  // Number of chains written
+	Accounts     int  // Number of accounts written
+	Transactions int  // Number of transactions written
+	Incremental  bool // Whether transactions were appended to a previous export
+}
+
+var (
+	// ErrSchemaMismatch is an error definition describing an incremental export into a database written with a
+	// different schema version.
+	ErrSchemaMismatch = errors.New("database was exported with a different schema version; run a full export instead")
+
+	// ErrNetworkMismatch is an error definition describing an incremental export into a database holding another network.
+	ErrNetworkMismatch = errors.New("database holds another network; run a full export instead")
+)
+
+// schema defines the tables and indexes written by SQLite.
+var schema = []string{
+	`CREATE TABLE meta (
+		key   TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	)`,
+	`CREATE TABLE chain_config (
+		network_id     INTEGER NOT NULL,
+		chain_id       TEXT NOT NULL,
+		chain_version  TEXT NOT NULL,
+		inflation_rate REAL NOT NULL
+	)`,
+	`CREATE TABLE alloc (
+		address      TEXT PRIMARY KEY,
+		position     INTEGER NOT NULL,
+		amount       REAL NOT NULL,
+		amount_exact TEXT NOT NULL
+	)`,
+	`CREATE TABLE chains (
+		account      TEXT PRIMARY KEY,
+		id           TEXT NOT NULL,
+		network_id   INTEGER NOT NULL,
+		genesis      TEXT,
+		contract     BLOB,
+		transactions INTEGER NOT NULL
+	)`,
+	`CREATE TABLE accounts (
+		address               TEXT PRIMARY KEY,
+		role                  TEXT,
+		name                  TEXT,
+		has_chain             INTEGER NOT NULL,
+		balance               REAL,
+		balance_exact         TEXT,
+		alloc                 TEXT,
+		transactions_sent     INTEGER NOT NULL,
+		transactions_received INTEGER NOT NULL
+	)`,
+	`CREATE TABLE transactions (
+		hash              TEXT PRIMARY KEY,
+		nonce             INTEGER NOT NULL,
+		sender            TEXT,
+		recipient         TEXT,
+		amount            REAL NOT NULL,
+		amount_exact      TEXT NOT NULL,
+		payload           BLOB,
+		parent            TEXT,
+		time              TEXT NOT NULL,
+		unix_nano         INTEGER NOT NULL,
+		genesis           INTEGER NOT NULL,
+		contract_creation INTEGER NOT NULL,
+		deployed_contract TEXT,
+		signed            INTEGER NOT NULL
+	)`,
+	`CREATE TABLE chain_transactions (
+		chain    TEXT NOT NULL,
+		hash     TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		PRIMARY KEY (chain, hash)
+	)`,
+	`CREATE INDEX transactions_sender ON transactions (sender)`,
+	`CREATE INDEX transactions_recipient ON transactions (recipient)`,
+	`CREATE INDEX transactions_time ON transactions (unix_nano)`,
+	`CREATE INDEX chain_transactions_hash ON chain_transactions (hash)`,
+}
+
+// tables are the tables written by SQLite, in the order they're created.
+var tables = []string{"meta", "chain_config", "alloc", "chains", "accounts", "transactions", "chain_transactions"}
+
+// snapshotTables are the tables rewritten on every export, including incremental ones.
+var snapshotTables = []string{"chain_config", "alloc", "chains", "accounts"}
+
+/* BEGIN EXPORTED METHODS */
+
+// SQLite exports a network to the SQLite database at a given path, creating it if it doesn't exist. A full export
+// replaces every table. An incremental export rewrites the chain config, alloc, chain, and account tables, but only
+// appends the transactions the previous export doesn't hold, by hash; if the database holds no previous export, a
+// full export is run instead.
+func SQLite(path string, network *Network, incremental bool) (*Stats, error) {
+	db, err := sql.Open("sqlite3", path) // Open database
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	defer db.Close() // Close database
+
+	tx, err := db.Begin() // Begin transaction
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	defer tx.Rollback() // Roll back unless committed
+
+	exported := &Stats{} // Init stats
+
+	var previousLatest int64 // Init previous latest transaction buffer
+
+	if incremental { // Check incremental
+		exported.Incremental, previousLatest, err = previousExport(tx, network.Config) // Get previous export
+	}
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if exported.Incremental { // Check appending
+		for _, table := range snapshotTables { // Iterate through snapshot tables
+			if _, err = tx.Exec("DELETE FROM " + table); err != nil { // Clear table
+				return nil, err // Return found error
+			}
+		}
+	} else {
+		for i := len(tables) - 1; i >= 0; i-- { // Iterate through tables, in reverse
+			if _, err = tx.Exec("DROP TABLE IF EXISTS " + tables[i]); err != nil { // Drop table
+				return nil, err // Return found error
+			}
+		}
+
+		for _, statement := range schema { // Iterate through schema
+			if _, err = tx.Exec(statement); err != nil { // Create table or index
+				return nil, err // Return found error
+			}
+		}
+	}
+
+	if err = writeChainConfig(tx, network.Config); err != nil { // Write chain config
+		return nil, err // Return found error
+	}
+
+	latest, err := writeChains(tx, network.Chains, exported) // Write chains and transactions
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if err = writeAccounts(tx, network, exported); err != nil { // Write accounts
+		return nil, err // Return found error
+	}
+
+	if latest < previousLatest { // Check no newer transactions
+		latest = previousLatest // Keep previous latest
+	}
+
+	meta := map[string]string{
+		"schema_version":   strconv.Itoa(SchemaVersion),                              // Set schema version
+		"network_id":       strconv.FormatUint(uint64(network.Config.NetworkID), 10), // Set network ID
+		"latest_unix_nano": strconv.FormatInt(latest, 10),                            // Set latest transaction
+		"exported_at":      time.Now().UTC().Format(TimeFormat),                      // Set export time
+	} // Init meta
+
+	for key, value := range meta { // Iterate through meta
+		if _, err = tx.Exec("INSERT OR REPLACE INTO meta (key, value) VALUES (?, ?)", key, value); err != nil { // Write meta
+			return nil, err // Return found error
+		}
+	}
+
+	return exported, tx.Commit() // Commit export
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// previousExport checks whether a database holds a previous export of a network with a given chain config that
+// transactions can be appended to, and gets the time of the latest transaction it holds.
+func previousExport(tx *sql.Tx, chainConfig *config.ChainConfig) (bool, int64, error) {
+	var count int // Init count buffer
+
+	if err := tx.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'meta'").Scan(&count); err != nil || count == 0 { // Check no previous export
+		return false, 0, err // Nothing to append to
+	}
+
+	meta := make(map[string]string) // Init meta buffer
+
+	rows, err := tx.Query("SELECT key, value FROM meta") // Query meta
+
+	if err != nil { // Check for errors
+		return false, 0, err // Return found error
+	}
+
+	defer rows.Close() // Close rows
+
+	for rows.Next() { // Iterate through rows
+		var key, value string // Init row buffers
+
+		if err = rows.Scan(&key, &value); err != nil { // Scan row
+			return false, 0, err // Return found error
+		}
+
+		meta[key] = value // Set value
+	}
+
+	if err = rows.Err(); err != nil { // Check for errors
+		return false, 0, err // Return found error
+	}
+
+	if meta["schema_version"] != strconv.Itoa(SchemaVersion) { // Check schema changed
+		return false, 0, ErrSchemaMismatch // Return error
+	}
+
+	if meta["network_id"] != strconv.FormatUint(uint64(chainConfig.NetworkID), 10) { // Check other network
+		return false, 0, fmt.Errorf("%s (network %s, not %d)", ErrNetworkMismatch, meta["network_id"], chainConfig.NetworkID) // Return error
+	}
+
+	latest, err := strconv.ParseInt(meta["latest_unix_nano"], 10, 64) // Parse latest transaction
+
+	if err != nil { // Check for errors
+		return false, 0, err // Return found error
+	}
+
+	return true, latest, nil // Return previous export
+}
+
+// writeChainConfig writes the chain config and alloc of a network.
+func writeChainConfig(tx *sql.Tx, chainConfig *config.ChainConfig) error {
+	_, err := tx.Exec("INSERT INTO chain_config (network_id, chain_id, chain_version, inflation_rate) VALUES (?, ?, ?, ?)", chainConfig.NetworkID, chainConfig.ChainID.String(), chainConfig.ChainVersion, chainConfig.InflationRate) // Write chain config
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	for i, address := range chainConfig.AllocAddresses { // Iterate through alloc addresses
+		alloc := chainConfig.Alloc[address.String()] // Get alloc
+
+		if alloc == nil { // Check no alloc
+			continue // Continue
+		}
+
+		value, _ := alloc.Float64() // Get approximate value
+
+		if _, err = tx.Exec("INSERT OR REPLACE INTO alloc (address, position, amount, amount_exact) VALUES (?, ?, ?, ?)", address.String(), i, value, alloc.Text('f', -1)); err != nil { // Write alloc
+			return err // Return found error
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// writeChains writes a set of chains, along with every transaction they hold that the database doesn't hold yet, and
+// gets the time of the latest transaction they hold (in nanoseconds since the Unix epoch). Transactions are identified
+// by their hash, such that transactions older than the latest one in a previous export are still appended.
+func writeChains(tx *sql.Tx, chains []*types.Chain, exported *Stats) (int64, error) {
+	latest := int64(0) // Init latest buffer
+
+	for _, chain := range chains { // Iterate through chains
+		var genesis interface{} // Init genesis buffer
+
+		if chain.Genesis != (summercashCommon.Hash{}) { // Check has genesis
+			genesis = chain.Genesis.String() // Set genesis
+		}
+
+		var contract interface{} // Init contract buffer
+
+		if len(chain.ContractSource) > 0 { // Check has contract
+			contract = chain.ContractSource // Set contract
+		}
+
+		_, err := tx.Exec("INSERT INTO chains (account, id, network_id, genesis, contract, transactions) VALUES (?, ?, ?, ?, ?, ?)", chain.Account.String(), chain.ID.String(), chain.NetworkID, genesis, contract, len(chain.Transactions)) // Write chain
+
+		if err != nil { // Check for errors
+			return 0, err // Return found error
+		}
+
+		exported.Chains++ // Increment chains
+
+		for position, transaction := range chain.Transactions { // Iterate through transactions
+			if transaction == nil || transaction.Hash == nil { // Check not exportable
+				continue // Continue
+			}
+
+			if _, err = tx.Exec("INSERT OR IGNORE INTO chain_transactions (chain, hash, position) VALUES (?, ?, ?)", chain.Account.String(), transaction.Hash.String(), position); err != nil { // Write chain membership
+				return 0, err // Return found error
+			}
+
+			if transaction.Timestamp.UnixNano() > latest { // Check later
+				latest = transaction.Timestamp.UnixNano() // Set latest
+			}
+
+			result, err := tx.Exec("INSERT OR IGNORE INTO transactions (hash, nonce, sender, recipient, amount, amount_exact, payload, parent, time, unix_nano, genesis, contract_creation, deployed_contract, signed) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", transactionValues(chain, transaction)...) // Write transaction
+
+			if err != nil { // Check for errors
+				return 0, err // Return found error
+			}
+
+			if written, _ := result.RowsAffected(); written > 0 { // Check new transaction
+				exported.Transactions++ // Increment transactions
+			}
+		}
+	}
+
+	return latest, nil // Return latest
+}
+
+// writeAccounts writes every account that holds a chain, has an alloc or role, or appears in a transaction.
+func writeAccounts(tx *sql.Tx, network *Network, exported *Stats) error {
+	type account struct {
+		chain    *types.Chain // Chain, if held locally
+		sent     int          // Number of transactions sent
+		received int          // Number of transactions received
+	}
+
+	accounts := make(map[string]*account) // Init accounts buffer
+
+	get := func(address string) *account {
+		if accounts[address] == nil { // Check new account
+			accounts[address] = &account{} // Init account
+		}
+
+		return accounts[address] // Return account
+	}
+
+	seen := make(map[summercashCommon.Hash]bool) // Init seen transactions buffer
+
+	for _, chain := range network.Chains { // Iterate through chains
+		get(chain.Account.String()).chain = chain // Set chain
+
+		for _, transaction := range chain.Transactions { // Iterate through transactions
+			if transaction == nil || transaction.Hash == nil || seen[*transaction.Hash] { // Check invalid or already counted
+				continue // Continue
+			}
+
+			seen[*transaction.Hash] = true // Set seen
+
+			if transaction.Sender != nil { // Check has sender
+				get(transaction.Sender.String()).sent++ // Increment sent
+			}
+
+			if transaction.Recipient != nil { // Check has recipient
+				get(transaction.Recipient.String()).received++ // Increment received
+			}
+		}
+	}
+
+	for address := range network.Config.Alloc { // Iterate through alloc
+		get(address) // Add account
+	}
+
+	for address := range network.Roles { // Iterate through roles
+		get(address) // Add account
+	}
+
+	addresses := make([]string, 0, len(accounts)) // Init addresses buffer
+
+	for address := range accounts { // Iterate through accounts
+		addresses = append(addresses, address) // Append address
+	}
+
+	sort.Strings(addresses) // Sort addresses
+
+	for _, address := range addresses { // Iterate through addresses
+		entry := accounts[address] // Get account
+
+		var role, name, balance, balanceExact, alloc interface{} // Init nullable buffers
+
+		if accountRole := network.Roles[address]; accountRole != nil { // Check has role
+			role, name = nullable(accountRole.Role), nullable(accountRole.Name) // Set role and name
+		}
+
+		if entry.chain != nil { // Check has chain
+			chainBalance, _, _ := stats.Balance(entry.chain) // Get balance
+
+			balance, _ = chainBalance.Float64()       // Set balance
+			balanceExact = chainBalance.Text('f', -1) // Set exact balance
+		}
+
+		if amount := network.Config.Alloc[address]; amount != nil { // Check has alloc
+			alloc = amount.Text('f', -1) // Set alloc
+		}
+
+		_, err := tx.Exec("INSERT INTO accounts (address, role, name, has_chain, balance, balance_exact, alloc, transactions_sent, transactions_received) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", address, role, name, entry.chain != nil, balance, balanceExact, alloc, entry.sent, entry.received) // Write account
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		exported.Accounts++ // Increment accounts
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// transactionValues gets the column values of a transaction held by a given chain.
+func transactionValues(chain *types.Chain, transaction *types.Transaction) []interface{} {
+	amount := new(big.Float) // Init amount buffer
+
+	if transaction.Amount != nil { // Check has amount
+		amount = transaction.Amount // Set amount
+	}
+
+	value, _ := amount.Float64() // Get approximate amount
+
+	var sender, recipient, parent, deployedContract, payload interface{} // Init nullable buffers
+
+	if transaction.Sender != nil { // Check has sender
+		sender = transaction.Sender.String() // Set sender
+	}
+
+	if transaction.Recipient != nil { // Check has recipient
+		recipient = transaction.Recipient.String() // Set recipient
+	}
+
+	if transaction.ParentTx != nil && *transaction.ParentTx != (summercashCommon.Hash{}) { // Check has parent
+		parent = transaction.ParentTx.String() // Set parent
+	}
+
+	if transaction.DeployedContractAddress != nil { // Check has deployed contract
+		deployedContract = transaction.DeployedContractAddress.String() // Set deployed contract
+	}
+
+	if len(transaction.Payload) > 0 { // Check has payload
+		payload = transaction.Payload // Set payload
+	}
+
+	genesis := transaction.Genesis || *transaction.Hash == chain.Genesis || string(transaction.Payload) == "genesisChild" // Check genesis
+
+	return []interface{}{
+		transaction.Hash.String(),       // Hash
+		int64(transaction.AccountNonce), // Nonce
+		sender,                          // Sender
+		recipient,                       // Recipient
+		value,                           // Amount
+		amount.Text('f', -1),            // Exact amount
+		payload,                         // Payload
+		parent,                          // Parent
+		transaction.Timestamp.UTC().Format(TimeFormat), // Time
+		transaction.Timestamp.UnixNano(),               // Unix time
+		genesis,                                        // Genesis
+		transaction.ContractCreation,                   // Contract creation
+		deployedContract,                               // Deployed contract
+		transaction.Signature != nil,                   // Signed
+	} // Return values
+}
+
+// nullable converts an empty string to a SQL NULL.
+func nullable(s string) interface{} {
+	if s == "" { // Check empty
+		return nil // Return NULL
+	}
+
+	return s // Return string
+}
+
+/* END INTERNAL METHODS */
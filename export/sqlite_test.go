@@ -0,0 +1,138 @@
+// Package export defines helper methods for exporting the chains of a SummerCash network to other data stores.
+package export
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/internal/fixtures"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestSQLite tests the functionality of the SQLite() method.
+func TestSQLite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "puppet-export") // Make temp dir
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	defer os.RemoveAll(dir) // Remove temp dir
+
+	path := filepath.Join(dir, "network.db") // Get database path
+
+	network, start := testNetwork() // Init network
+
+	sender := fixtures.Address(fixtures.Sender)       // Parse sender
+	recipient := fixtures.Address(fixtures.Recipient) // Parse recipient
+
+	exported, err := SQLite(path, network, true) // Export network, falling back to a full export
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if exported.Incremental || exported.Chains != 2 || exported.Accounts != 2 || exported.Transactions != 2 { // Check invalid export
+		t.Fatalf("expected a full export of 2 chains, 2 accounts, and 2 transactions, found %+v", exported) // Panic
+	}
+
+	db, err := sql.Open("sqlite3", path) // Open database
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	defer db.Close() // Close database
+
+	var role, balance string // Init row buffers
+
+	if err = db.QueryRow("SELECT role, balance_exact FROM accounts WHERE address = ?", fixtures.Recipient).Scan(&role, &balance); err != nil || role != "treasury" || balance != "10" { // Check invalid account
+		t.Fatalf("expected treasury %s to hold 10, found %s holding %s (%v)", fixtures.Recipient, role, balance, err) // Panic
+	}
+
+	network.Chains[0].Transactions = append(network.Chains[0].Transactions, fixtures.Transaction("later", &sender, &recipient, 5, start.Add(time.Hour))) // Append transfer
+	network.Chains[1].Transactions = append(network.Chains[1].Transactions, network.Chains[0].Transactions[2])                                           // Append transfer to recipient chain
+
+	if exported, err = SQLite(path, network, true); err != nil || !exported.Incremental || exported.Transactions != 1 { // Check not appended
+		t.Fatalf("expected a single appended transaction, found %+v (%v)", exported, err) // Panic
+	}
+
+	var transactions, links int // Init count buffers
+
+	if err = db.QueryRow("SELECT (SELECT COUNT(*) FROM transactions), (SELECT COUNT(*) FROM chain_transactions)").Scan(&transactions, &links); err != nil || transactions != 3 || links != 5 { // Check invalid counts
+		t.Fatalf("expected 3 transactions held across 5 chain entries, found %d and %d (%v)", transactions, links, err) // Panic
+	}
+
+	if err = db.QueryRow("SELECT balance_exact FROM accounts WHERE address = ?", fixtures.Recipient).Scan(&balance); err != nil || balance != "15" { // Check account not rewritten
+		t.Fatalf("expected %s to hold 15, found %s (%v)", fixtures.Recipient, balance, err) // Panic
+	}
+
+	late := fixtures.Transaction("late", &recipient, &sender, 1, start.Add(-time.Minute)) // Init transfer arriving after a later one was exported
+
+	network.Chains[1].Transactions = append(network.Chains[1].Transactions, late) // Append late transfer
+
+	if exported, err = SQLite(path, network, true); err != nil || exported.Transactions != 1 { // Check late transfer skipped
+		t.Fatalf("expected the late transaction to be appended, found %+v (%v)", exported, err) // Panic
+	}
+
+	var latest string // Init latest buffer
+
+	if err = db.QueryRow("SELECT value FROM meta WHERE key = 'latest_unix_nano'").Scan(&latest); err != nil || latest != strconv.FormatInt(start.Add(time.Hour).UnixNano(), 10) { // Check latest moved back
+		t.Fatalf("expected the latest transaction to stay at %s, found %s (%v)", start.Add(time.Hour), latest, err) // Panic
+	}
+
+	network.Chains[1].Transactions = network.Chains[1].Transactions[:len(network.Chains[1].Transactions)-1] // Remove late transfer
+
+	network.Config.NetworkID = 8 // Move to another network
+
+	if _, err = SQLite(path, network, true); err == nil || !strings.HasPrefix(err.Error(), ErrNetworkMismatch.Error()) { // Check other network appended
+		t.Fatalf("expected %v, found %v", ErrNetworkMismatch, err) // Panic
+	}
+
+	if exported, err = SQLite(path, network, false); err != nil || exported.Incremental || exported.Transactions != 3 { // Check not replaced
+		t.Fatalf("expected a full export of 3 transactions, found %+v (%v)", exported, err) // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
+
+/* BEGIN INTERNAL METHODS */
+
+// testNetwork initializes a network in which the genesis account fixtures.Sender sends fixtures.Recipient 10, along
+// with the time of the transfer.
+func testNetwork() (*Network, time.Time) {
+	sender := fixtures.Address(fixtures.Sender)       // Parse sender
+	recipient := fixtures.Address(fixtures.Recipient) // Parse recipient
+
+	start := fixtures.Start // Get transfer time
+
+	genesis := fixtures.Transaction("genesis", nil, &sender, 1000, start.Add(-time.Hour)) // Init genesis transaction
+	transfer := fixtures.Transaction("transfer", &sender, &recipient, 10, start)          // Init transfer
+
+	return &Network{
+		Config: &config.ChainConfig{
+			Alloc:          map[string]*big.Float{sender.String(): big.NewFloat(1000)}, // Set alloc
+			AllocAddresses: []summercashCommon.Address{sender},                         // Set alloc addresses
+			NetworkID:      7,                                                          // Set network ID
+			ChainVersion:   "0.0.1",                                                    // Set chain version
+		}, // Set config
+		Chains: []*types.Chain{
+			{Account: sender, Genesis: *genesis.Hash, NetworkID: 7, Transactions: []*types.Transaction{genesis, transfer}}, // Sender chain
+			{Account: recipient, NetworkID: 7, Transactions: []*types.Transaction{transfer}},                               // Recipient chain
+		}, // Set chains
+		Roles: map[string]*Role{recipient.String(): {Role: "treasury"}}, // Set roles
+	}, start // Return network
+}
+
+/* END INTERNAL METHODS */
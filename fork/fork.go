@@ -0,0 +1,523 @@
+// Package fork defines helper methods for hard forking a SummerCash network: snapshotting its data dir, migrating its
+// chains, persisting its new chain version, and recording its fork history.
+package fork
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+// Dir is the dir, relative to a data dir, that fork history and snapshots are kept in.
+const Dir = "forks"
+
+// Migration represents a step run over every chain of a network when it is forked to (or past) a given version.
+type Migration struct {
+	Version     string                                                          // Version the migration was introduced in
+	Description string                                                          // Description
+	Migrate     func(chain *types.Chain, chainConfig *config.ChainConfig) error // Migrates a single chain
+}
+
+// Entry represents a single fork in the history of a network.
+type Entry struct {
+	From       string    `json:"from"`                 // Version forked from
+	To         string    `json:"to"`                   // Version forked to
+	Time       time.Time `json:"time"`                 // Time of fork
+	ConfigHash string    `json:"config_hash"`          // SHA-256 of the config (or, if scheduled, the schedule) written
+	Snapshot   string    `json:"snapshot"`             // Name of the snapshot of the pre-fork data dir
+	Migrations []string  `json:"migrations,omitempty"` // Descriptions of the migrations run
+
+	Activation *Activation `json:"activation,omitempty"`  // Activation point, if the fork was scheduled
+	RolledBack *time.Time  `json:"rolled_back,omitempty"` // Time the fork was rolled back, if it has been
+}
+
+var (
+	// ErrNotNewer is an error definition describing a fork to a version that isn't newer than the current one.
+	ErrNotNewer = errors.New("fork version must be newer than the current chain version")
+
+	// ErrInvalidStoredVersion is an error definition describing a network whose stored chain version isn't a valid
+	// semantic version.
+	ErrInvalidStoredVersion = errors.New("the chain version stored in config/config.json is not a valid semantic version")
+)
+
+// definedError is an error definition, along with the details of a particular occurrence of it.
+type definedError struct {
+	definition error  // Error definition
+	details    string // Details of the occurrence
+}
+
+// migrations are the registered migrations, in order of registration.
+var migrations []*Migration
+
+/* BEGIN EXPORTED METHODS */
+
+// Register registers a migration to run when a network is forked past its version.
+func Register(migration *Migration) error {
+	if _, err := ParseVersion(migration.Version); err != nil { // Check invalid version
+		return err // Return found error
+	}
+
+	migrations = append(migrations, migration) // Append migration
+
+	return nil // No error occurred, return nil
+}
+
+// Pending gets the registered migrations introduced after a given version, up to and including another, ordered by
+// version (and by registration within a version).
+func Pending(from string, to string) ([]*Migration, error) {
+	var pending []*Migration // Init pending buffer
+
+	for _, migration := range migrations { // Iterate through migrations
+		afterFrom, err := CompareVersions(migration.Version, from) // Compare to from
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		beforeTo, err := CompareVersions(migration.Version, to) // Compare to to
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		if afterFrom > 0 && beforeTo <= 0 { // Check in range
+			pending = append(pending, migration) // Append migration
+		}
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		comparison, _ := CompareVersions(pending[i].Version, pending[j].Version) // Compare versions
+
+		return comparison < 0 // Return is earlier
+	})
+
+	return pending, nil // Return pending
+}
+
+// Fork forks the network in a given data dir to a given semantic version, which must be newer than any version the
+// network is at or scheduled to fork to unless downgrades are allowed. Allowing downgrades also allows forking a
+// network whose stored chain version is invalid. The data dir is snapshotted and the fork is appended to the
+// network's fork history before anything is changed. Any migrations introduced after the network's version (up to the
+// new one) are then run over every chain, and the config is rewritten with the new chain version. Other files in the
+// config dir (such as roles.json and vesting.json) are left intact. If any step fails, the config and chains are
+// restored from the snapshot and the fork is removed from the history, but the snapshot is kept. Should restoring
+// fail too, the fork stays in the history, so that it can be rolled back. Downgrades run no migrations.
+func Fork(dataDir string, version string, allowDowngrade bool) (*Entry, error) {
+	target, err := ParseVersion(version) // Parse version
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	chainConfig, err := readChainConfig(dataDir) // Read chain config
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	schedule, err := ReadSchedule(dataDir) // Read schedule
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	upgrade := false // Init upgrade buffer
+
+	latest, err := latestVersion(chainConfig, schedule) // Get latest version
+
+	if err != nil && !allowDowngrade { // Check for errors
+		return nil, err // Return found error
+	} else if err == nil { // Check valid latest version
+		parsedLatest, _ := ParseVersion(latest) // Parse latest version
+
+		comparison := target.Compare(parsedLatest) // Compare versions
+
+		if target.String() == parsedLatest.String() || (comparison <= 0 && !allowDowngrade) { // Check not newer
+			return nil, fmt.Errorf("%s (%s is not newer than %s)", ErrNotNewer, target, latest) // Return error
+		}
+
+		upgrade = comparison > 0 // Set upgrade
+	}
+
+	version = target.String() // Normalize version
+
+	var pending []*Migration // Init pending buffer
+
+	if upgrade { // Check upgrade
+		if pending, err = Pending(chainConfig.ChainVersion, version); err != nil { // Get pending migrations
+			return nil, err // Return found error
+		}
+	}
+
+	entry := &Entry{From: chainConfig.ChainVersion, To: version, Time: time.Now().UTC()} // Init entry
+
+	for _, migration := range pending { // Iterate through migrations
+		entry.Migrations = append(entry.Migrations, fmt.Sprintf("%s: %s", migration.Version, migration.Description)) // Append migration
+	}
+
+	if err = record(dataDir, entry, func() error { return apply(dataDir, chainConfig, version, pending, entry) }); err != nil { // Apply and record fork
+		return nil, err // Return found error
+	}
+
+	return entry, nil // No error occurred, return entry
+}
+
+// Snapshot copies every file in a data dir, other than its fork dir, into a new snapshot of the data dir at a given
+// version taken at a given time, and returns the name of the snapshot. The SHA-256 checksum of each copied file is
+// written next to the snapshot (see ChecksumsPath), in the format read by sha256sum -c.
+func Snapshot(dataDir string, version string, at time.Time) (string, error) {
+	name := fmt.Sprintf("%s-%d", strings.Map(func(character rune) rune {
+		if validIdentifier(string(character)) || character == '.' || character == '+' { // Check safe in a file name
+			return character // Keep character
+		}
+
+		return '_' // Replace character
+	}, version), at.UnixNano()) // Get name
+
+	target := SnapshotPath(dataDir, name) // Get target
+
+	var checksums []string // Init checksums buffer
+
+	err := filepath.Walk(dataDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		relative, err := filepath.Rel(dataDir, path) // Get relative path
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if relative == Dir { // Check fork dir
+			return filepath.SkipDir // Skip fork dir
+		}
+
+		if info.IsDir() { // Check is dir
+			return os.MkdirAll(filepath.Join(target, relative), 0755) // Make dir
+		}
+
+		checksum, err := copyFile(path, filepath.Join(target, relative), info.Mode()) // Copy file
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		checksums = append(checksums, fmt.Sprintf("%s  %s\n", checksum, filepath.ToSlash(relative))) // Append checksum
+
+		return nil // No error occurred, return nil
+	})
+
+	if err != nil { // Check for errors
+		return name, err // Return found error
+	}
+
+	return name, ioutil.WriteFile(ChecksumsPath(dataDir, name), []byte(strings.Join(checksums, "")), 0644) // Write checksums
+}
+
+// History reads the fork history of the network in a given data dir, oldest first.
+func History(dataDir string) ([]*Entry, error) {
+	data, err := ioutil.ReadFile(HistoryPath(dataDir)) // Read history
+
+	if os.IsNotExist(err) { // Check no history
+		return nil, nil // No history
+	} else if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	var history []*Entry // Init history buffer
+
+	err = json.Unmarshal(data, &history) // Unmarshal history
+
+	return history, err // Return history
+}
+
+// HistoryPath gets the path of the fork history file in a given data dir.
+func HistoryPath(dataDir string) string {
+	return filepath.Join(dataDir, Dir, "history.json") // Return path
+}
+
+// SnapshotPath gets the path of the snapshot with a given name in a given data dir.
+func SnapshotPath(dataDir string, name string) string {
+	return filepath.Join(dataDir, Dir, "snapshots", name) // Return path
+}
+
+// ChecksumsPath gets the path of the checksums of the files in the snapshot with a given name in a given data dir.
+func ChecksumsPath(dataDir string, name string) string {
+	return SnapshotPath(dataDir, name) + ".sha256" // Return path
+}
+
+// IsInvalidStoredVersion checks whether an error describes a network whose stored chain version isn't a valid
+// semantic version (see ErrInvalidStoredVersion).
+func IsInvalidStoredVersion(err error) bool {
+	return isDefinedError(err, ErrInvalidStoredVersion) // Return matches
+}
+
+// Error formats an error definition, along with the details of its occurrence.
+func (err *definedError) Error() string {
+	return err.definition.Error() + err.details // Return error
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// isDefinedError checks whether an error is an occurrence of a given error definition.
+func isDefinedError(err error, definition error) bool {
+	if defined, ok := err.(*definedError); ok { // Check has details
+		return defined.definition == definition // Return matches
+	}
+
+	return err == definition // Return matches
+}
+
+// record snapshots a given data dir, appends a given fork entry to its history, and then applies the fork. If
+// applying or recording the fork fails, the config and chains are restored from the snapshot and the entry is removed
+// from the history again, but the snapshot is kept. Should restoring fail too, the entry is kept, so that the fork can
+// still be rolled back.
+func record(dataDir string, entry *Entry, apply func() error) error {
+	history, err := History(dataDir) // Read history
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if entry.Snapshot, err = Snapshot(dataDir, entry.From, entry.Time); err != nil { // Snapshot data dir
+		return err // Return found error
+	}
+
+	if err = writeHistory(dataDir, append(history, entry)); err != nil { // Record fork before changing anything
+		return err // Return found error
+	}
+
+	if err = apply(); err == nil { // Apply fork
+		err = writeHistory(dataDir, append(history, entry)) // Record config hash
+	}
+
+	if err == nil { // Check applied
+		return nil // No error occurred, return nil
+	}
+
+	if restoreErr := restore(dataDir, entry.Snapshot); restoreErr != nil { // Restore snapshot
+		return fmt.Errorf("%s (could not restore the data dir: %s; the fork is recorded, so it can still be rolled back to snapshot %s)", err, restoreErr, entry.Snapshot) // Return error
+	}
+
+	if historyErr := writeHistory(dataDir, history); historyErr != nil { // Remove fork from history
+		return fmt.Errorf("%s (data dir restored, but the fork could not be removed from its history: %s)", err, historyErr) // Return error
+	}
+
+	return fmt.Errorf("%s (data dir restored; snapshot kept in %s)", err, SnapshotPath(dataDir, entry.Snapshot)) // Return error
+}
+
+// apply runs a set of migrations over every chain in a data dir, and rewrites its config with a given chain version,
+// setting the config hash of a given fork entry.
+func apply(dataDir string, chainConfig *config.ChainConfig, version string, pending []*Migration, entry *Entry) error {
+	err := migrate(dataDir, chainConfig, pending) // Migrate chains
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	chainConfig.ChainVersion = version // Set version
+
+	entry.ConfigHash, err = writeChainConfig(dataDir, chainConfig) // Write chain config
+
+	return err // Return error
+}
+
+// migrate runs a set of migrations over every chain in a data dir. Every chain is migrated and written to a staging
+// dir before any is moved into place, so a failing migration leaves the chains unchanged.
+func migrate(dataDir string, chainConfig *config.ChainConfig, pending []*Migration) error {
+	if len(pending) == 0 { // Check nothing to migrate
+		return nil // Nothing to migrate
+	}
+
+	chains, paths, err := readChains(dataDir) // Read chains
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	migrated := make([][]byte, len(chains)) // Init migrated buffer
+
+	for i, chain := range chains { // Iterate through chains
+		for _, migration := range pending { // Iterate through migrations
+			if err = migration.Migrate(chain, chainConfig); err != nil { // Migrate chain
+				return fmt.Errorf("migration %s (%s) failed on %s: %s", migration.Version, migration.Description, filepath.Base(paths[i]), err) // Return error
+			}
+		}
+
+		if err = chain.MakeEncodingSafe(); err != nil { // Make encoding safe
+			return err // Return found error
+		}
+
+		if migrated[i], err = json.MarshalIndent(chain, "", "  "); err != nil { // Marshal chain
+			return err // Return found error
+		}
+	}
+
+	staging := filepath.Join(dataDir, Dir, fmt.Sprintf("migrate-%d", time.Now().UnixNano())) // Get staging dir
+
+	if err = os.MkdirAll(staging, 0755); err != nil { // Make staging dir
+		return err // Return found error
+	}
+
+	defer os.RemoveAll(staging) // Remove staging dir
+
+	for i, path := range paths { // Iterate through chain paths
+		if err = ioutil.WriteFile(filepath.Join(staging, filepath.Base(path)), migrated[i], 0644); err != nil { // Stage chain
+			return err // Return found error
+		}
+	}
+
+	for _, path := range paths { // Iterate through chain paths
+		if err = os.Rename(filepath.Join(staging, filepath.Base(path)), path); err != nil { // Move chain into place
+			return err // Return found error
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// readChains reads every chain in a data dir, along with the paths they were read from.
+func readChains(dataDir string) ([]*types.Chain, []string, error) {
+	paths, err := filepath.Glob(filepath.Join(dataDir, "db", "chain", "chain_*.json")) // Get chain paths
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	chains := make([]*types.Chain, len(paths)) // Init chains buffer
+
+	for i, path := range paths { // Iterate through chain paths
+		data, err := ioutil.ReadFile(path) // Read chain
+
+		if err != nil { // Check for errors
+			return nil, nil, err // Return found error
+		}
+
+		chains[i] = &types.Chain{} // Init chain buffer
+
+		if err = json.Unmarshal(data, chains[i]); err != nil { // Unmarshal chain
+			return nil, nil, fmt.Errorf("%s: %s", filepath.Base(path), err) // Return error
+		}
+
+		if err = chains[i].RecoverSafeEncoding(); err != nil { // Recover encoding
+			return nil, nil, fmt.Errorf("%s: %s", filepath.Base(path), err) // Return error
+		}
+	}
+
+	return chains, paths, nil // Return chains
+}
+
+// readChainConfig reads the chain config in a given data dir.
+func readChainConfig(dataDir string) (*config.ChainConfig, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dataDir, "config", "config.json")) // Read config
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return config.FromBytes(data) // Return config
+}
+
+// writeChainConfig writes a chain config to a given data dir, in the same format as config.WriteToMemory, and returns
+// the SHA-256 hash of the written file.
+func writeChainConfig(dataDir string, chainConfig *config.ChainConfig) (string, error) {
+	data, err := json.MarshalIndent(*chainConfig, "", "  ") // Marshal config
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	hash := sha256.Sum256(data) // Hash config
+
+	return hex.EncodeToString(hash[:]), writeFile(filepath.Join(dataDir, "config", "config.json"), data) // Write config
+}
+
+// writeHistory writes the fork history of the network in a given data dir.
+func writeHistory(dataDir string, history []*Entry) error {
+	data, err := json.MarshalIndent(history, "", "  ") // Marshal history
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if err = os.MkdirAll(filepath.Dir(HistoryPath(dataDir)), 0755); err != nil { // Make fork dir
+		return err // Return found error
+	}
+
+	return writeFile(HistoryPath(dataDir), data) // Write history
+}
+
+// writeFile writes data to a temporary file next to a given path, and then moves it into place, such that the file
+// at the path is never partially written.
+func writeFile(path string, data []byte) error {
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp-") // Create temporary file
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	_, err = file.Write(data) // Write data
+
+	if closeErr := file.Close(); err == nil { // Close file
+		err = closeErr // Set error
+	}
+
+	if err == nil { // Check written
+		err = os.Chmod(file.Name(), 0644) // Make readable
+	}
+
+	if err == nil { // Check written
+		err = os.Rename(file.Name(), path) // Move into place
+	}
+
+	if err != nil { // Check for errors
+		os.Remove(file.Name()) // Remove temporary file
+	}
+
+	return err // Return error
+}
+
+// copyFile copies the file at a given path to another, with a given mode, and returns the SHA-256 checksum of its
+// contents.
+func copyFile(source string, destination string, mode os.FileMode) (string, error) {
+	in, err := os.Open(source) // Open source
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	defer in.Close() // Close source
+
+	out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode) // Open destination
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	hash := sha256.New() // Init hash
+
+	if _, err = io.Copy(io.MultiWriter(out, hash), in); err != nil { // Copy file
+		out.Close() // Close destination
+
+		return "", err // Return found error
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), out.Close() // Close destination
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,236 @@
+// Package fork defines helper methods for hard forking a SummerCash network: snapshotting its data dir, migrating its
+// chains, persisting its new chain version, and recording its fork history.
+package fork
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+// testAccount is a valid address used as the account of the chain in test data dirs.
+const testAccount = "0x040000fe1cb145827b9833a8b3668190d6df"
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestFork tests the functionality of the Fork() method.
+func TestFork(t *testing.T) {
+	dataDir := testDataDir(t, "1.0.0") // Init data dir
+
+	defer os.RemoveAll(dataDir) // Remove data dir
+
+	defer func() { migrations = nil }() // Reset migrations
+
+	Register(&Migration{Version: "1.0.1", Description: "set network ID", Migrate: func(chain *types.Chain, chainConfig *config.ChainConfig) error {
+		chain.NetworkID = chainConfig.NetworkID // Set network ID
+
+		return nil // No error occurred, return nil
+	}}) // Register migration
+
+	Register(&Migration{Version: "2.0.0", Description: "not yet due", Migrate: func(chain *types.Chain, chainConfig *config.ChainConfig) error {
+		return errors.New("ran early") // Return error
+	}}) // Register later migration
+
+	entry, err := Fork(dataDir, "1.0.1", false) // Fork data dir
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if entry.From != "1.0.0" || entry.To != "1.0.1" || len(entry.Migrations) != 1 || entry.ConfigHash == "" { // Check invalid entry
+		t.Fatalf("expected a fork from 1.0.0 to 1.0.1 running a single migration, found %+v", entry) // Panic
+	}
+
+	if chainConfig, err := readChainConfig(dataDir); err != nil || chainConfig.ChainVersion != "1.0.1" || chainConfig.NetworkID != 7 { // Check config not persisted
+		t.Fatalf("expected the persisted config of network 7 to be at 1.0.1, found %+v (%v)", chainConfig, err) // Panic
+	}
+
+	if chain := testReadChain(t, dataDir); chain.NetworkID != 7 { // Check not migrated
+		t.Fatalf("expected the migrated chain to be on network 7, found %d", chain.NetworkID) // Panic
+	}
+
+	if roles, err := ioutil.ReadFile(filepath.Join(dataDir, "config", "roles.json")); err != nil || string(roles) != "[]" { // Check roles changed
+		t.Fatalf("expected roles.json to be left intact, found %s (%v)", roles, err) // Panic
+	}
+
+	if snapshotted, err := readChainConfig(SnapshotPath(dataDir, entry.Snapshot)); err != nil || snapshotted.ChainVersion != "1.0.0" { // Check not snapshotted
+		t.Fatalf("expected a snapshot of the data dir at 1.0.0, found %+v (%v)", snapshotted, err) // Panic
+	}
+
+	if _, err = os.Stat(filepath.Join(SnapshotPath(dataDir, entry.Snapshot), Dir)); !os.IsNotExist(err) { // Check fork dir snapshotted
+		t.Fatal("expected the fork dir to be left out of the snapshot") // Panic
+	}
+
+	if history, err := History(dataDir); err != nil || len(history) != 1 || history[0].ConfigHash != entry.ConfigHash { // Check not recorded
+		t.Fatalf("expected a single recorded fork, found %d (%v)", len(history), err) // Panic
+	}
+
+	if _, err = Fork(dataDir, "1.0.1", false); err == nil { // Check same version forked
+		t.Fatal("expected an error for a fork to the current version") // Panic
+	}
+
+	if _, err = Fork(dataDir, "2.0.0", false); err == nil { // Check failing migration applied
+		t.Fatal("expected an error for a failing migration") // Panic
+	}
+
+	if chainConfig, _ := readChainConfig(dataDir); chainConfig.ChainVersion != "1.0.1" { // Check config changed
+		t.Fatalf("expected a failed fork to leave the config at 1.0.1, found %s", chainConfig.ChainVersion) // Panic
+	}
+
+	if history, _ := History(dataDir); len(history) != 1 { // Check failed fork recorded
+		t.Fatalf("expected a failed fork to go unrecorded, found %d forks", len(history)) // Panic
+	}
+}
+
+// TestForkRestore tests that Fork() restores the data dir from its snapshot, and leaves the fork unrecorded, when the
+// config can't be written after the chains have been migrated.
+func TestForkRestore(t *testing.T) {
+	dataDir := testDataDir(t, "1.0.0") // Init data dir
+
+	defer os.RemoveAll(dataDir) // Remove data dir
+
+	defer func() { migrations = nil }() // Reset migrations
+
+	original, err := ioutil.ReadFile(filepath.Join(dataDir, "db", "chain", "chain_"+testAccount+".json")) // Read chain
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	Register(&Migration{Version: "1.0.1", Description: "break config", Migrate: func(chain *types.Chain, chainConfig *config.ChainConfig) error {
+		chain.NetworkID = chainConfig.NetworkID // Set network ID
+		chainConfig.InflationRate = math.NaN()  // Make config unencodable
+
+		return nil // No error occurred, return nil
+	}}) // Register migration
+
+	if _, err = Fork(dataDir, "1.0.1", false); err == nil || !strings.Contains(err.Error(), "data dir restored") { // Check not restored
+		t.Fatalf("expected the data dir to be restored after a failed config write, found %v", err) // Panic
+	}
+
+	if restored, err := ioutil.ReadFile(filepath.Join(dataDir, "db", "chain", "chain_"+testAccount+".json")); err != nil || string(restored) != string(original) { // Check chain left migrated
+		t.Fatalf("expected the chain to be restored, found %s (%v)", restored, err) // Panic
+	}
+
+	if chainConfig, err := readChainConfig(dataDir); err != nil || chainConfig.ChainVersion != "1.0.0" { // Check config changed
+		t.Fatalf("expected the config to be left at 1.0.0, found %+v (%v)", chainConfig, err) // Panic
+	}
+
+	if history, err := History(dataDir); err != nil || len(history) != 0 { // Check failed fork recorded
+		t.Fatalf("expected a failed fork to go unrecorded, found %d forks (%v)", len(history), err) // Panic
+	}
+
+	if snapshots, err := filepath.Glob(filepath.Join(dataDir, Dir, "*")); err != nil || len(snapshots) != 2 { // Check snapshot removed
+		t.Fatalf("expected the snapshot to be kept next to the history, found %v (%v)", snapshots, err) // Panic
+	}
+}
+
+// TestForkDowngrade tests that Fork() only downgrades a network, or replaces an invalid stored version, when
+// downgrades are allowed.
+func TestForkDowngrade(t *testing.T) {
+	dataDir := testDataDir(t, "1.0") // Init data dir with an invalid version
+
+	defer os.RemoveAll(dataDir) // Remove data dir
+
+	if _, err := Fork(dataDir, "1.1.0", false); err == nil || !IsInvalidStoredVersion(err) { // Check invalid stored version accepted
+		t.Fatalf("expected %v, found %v", ErrInvalidStoredVersion, err) // Panic
+	}
+
+	if entry, err := Fork(dataDir, "v1.1.0", true); err != nil || entry.From != "1.0" || entry.To != "1.1.0" { // Check invalid stored version not replaced
+		t.Fatalf("expected a fork from 1.0 to 1.1.0, found %+v (%v)", entry, err) // Panic
+	}
+
+	if _, err := Fork(dataDir, "1.0.0", false); err == nil { // Check downgrade allowed
+		t.Fatal("expected an error for a downgrade") // Panic
+	}
+
+	if _, err := Fork(dataDir, "1.1.0", true); err == nil { // Check same version forked
+		t.Fatal("expected an error for a fork to the current version") // Panic
+	}
+
+	if entry, err := Fork(dataDir, "1.1.0-rc.1", true); err != nil || entry.To != "1.1.0-rc.1" { // Check downgrade not allowed
+		t.Fatalf("expected a downgrade to 1.1.0-rc.1, found %+v (%v)", entry, err) // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
+
+/* BEGIN INTERNAL METHODS */
+
+// testDataDir initializes a data dir holding the config of network 7 at a given version, an empty roles.json, and a
+// single chain.
+func testDataDir(t *testing.T, version string) string {
+	dataDir, err := ioutil.TempDir("", "puppet-fork") // Make temp dir
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	account, _ := summercashCommon.StringToAddress(testAccount) // Parse account
+
+	for _, dir := range []string{"config", filepath.Join("db", "chain")} { // Iterate through dirs
+		if err = os.MkdirAll(filepath.Join(dataDir, dir), 0755); err != nil { // Make dir
+			t.Fatal(err) // Panic
+		}
+	}
+
+	chainConfig := &config.ChainConfig{Alloc: map[string]*big.Float{account.String(): big.NewFloat(10)}, AllocAddresses: []summercashCommon.Address{account}, NetworkID: 7, ChainVersion: version} // Init chain config
+
+	if _, err = writeChainConfig(dataDir, chainConfig); err != nil { // Write chain config
+		t.Fatal(err) // Panic
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(dataDir, "config", "roles.json"), []byte("[]"), 0644); err != nil { // Write roles
+		t.Fatal(err) // Panic
+	}
+
+	chain, _ := json.Marshal(&types.Chain{Account: account}) // Marshal chain
+
+	if err = ioutil.WriteFile(filepath.Join(dataDir, "db", "chain", "chain_"+account.String()+".json"), chain, 0644); err != nil { // Write chain
+		t.Fatal(err) // Panic
+	}
+
+	return dataDir // Return data dir
+}
+
+// testReadChain reads the single chain in a test data dir.
+func testReadChain(t *testing.T, dataDir string) *types.Chain {
+	data, err := ioutil.ReadFile(filepath.Join(dataDir, "db", "chain", "chain_"+testAccount+".json")) // Read chain
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	chain := &types.Chain{} // Init chain buffer
+
+	if err = json.Unmarshal(data, chain); err != nil { // Unmarshal chain
+		t.Fatal(err) // Panic
+	}
+
+	return chain // Return chain
+}
+
+// testWriteChain writes the single chain in a test data dir.
+func testWriteChain(t *testing.T, dataDir string, chain *types.Chain) {
+	data, err := json.Marshal(chain) // Marshal chain
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(dataDir, "db", "chain", "chain_"+testAccount+".json"), data, 0644); err != nil { // Write chain
+		t.Fatal(err) // Panic
+	}
+}
+
+/* END INTERNAL METHODS */
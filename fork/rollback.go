@@ -0,0 +1,451 @@
+// Package fork defines helper methods for hard forking a SummerCash network: snapshotting its data dir, migrating its
+// chains, persisting its new chain version, and recording its fork history.
+package fork
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SummerCash/go-summercash/types"
+)
+
+// Rollback represents the rollback of a network to the data dir snapshotted before one of its forks.
+type Rollback struct {
+	From       string   // Version rolled back from
+	To         string   // Version rolled back to
+	RolledBack []*Entry // Forks rolled back, oldest first
+	Snapshot   string   // Name of the snapshot of the data dir taken before rolling back
+
+	Transactions int    // Number of transactions added since the fork
+	Replay       string // Path of the file the transactions added since the fork were exported to, if any
+}
+
+var (
+	// ErrNoFork is an error definition describing a rollback with no matching fork to roll back.
+	ErrNoFork = errors.New("no fork to roll back")
+
+	// ErrNoChecksums is an error definition describing a snapshot taken without recording the checksums of its files.
+	ErrNoChecksums = errors.New("snapshot has no recorded checksums")
+
+	// ErrChecksumMismatch is an error definition describing a snapshot whose files don't match their recorded checksums.
+	ErrChecksumMismatch = errors.New("snapshot doesn't match its recorded checksums")
+
+	// ErrTransactionsSinceFork is an error definition describing a rollback of a fork that transactions have been added
+	// to the network since.
+	ErrTransactionsSinceFork = errors.New("transactions have been added since the fork")
+
+	// ErrIncompleteSwap is an error definition describing a failed swap of a snapshot into a data dir that couldn't be
+	// undone.
+	ErrIncompleteSwap = errors.New("could not swap the snapshot into the data dir, nor move the replaced files back")
+)
+
+// swapped are the paths, relative to a data dir, that are swapped in when a snapshot is restored.
+var swapped = []string{"db/chain", "config/config.json", "config/schedule.json"}
+
+/* BEGIN EXPORTED METHODS */
+
+// RollbackFork rolls the network in a given data dir back to the snapshot taken before the latest fork to a given
+// version (or, if no version is given, before its latest fork), undoing every fork since. The snapshot is verified
+// against its recorded checksums, and its config, schedule, and chains are copied into a staging dir and verified
+// again before being swapped in; other files (such as roles.json and vesting.json) are left intact. A rollback is
+// refused if transactions have been added to the network since the fork, unless forced, in which case the
+// transactions are exported for replay (see ReplayPath). The data dir is snapshotted before anything is changed, and
+// the forks are marked as rolled back in the network's fork history.
+func RollbackFork(dataDir string, version string, force bool) (*Rollback, error) {
+	history, err := History(dataDir) // Read history
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	index := rollbackIndex(history, version) // Get fork to roll back
+
+	if index < 0 && version != "" { // Check no fork to version
+		return nil, fmt.Errorf("%s to %s", ErrNoFork, version) // Return error
+	} else if index < 0 { // Check no fork
+		return nil, ErrNoFork // Return error
+	}
+
+	entry := history[index] // Get fork
+
+	rollback := &Rollback{To: entry.From} // Init rollback
+
+	for _, rolledBack := range history[index:] { // Iterate through forks since
+		if rolledBack.RolledBack == nil { // Check not already rolled back
+			rollback.RolledBack = append(rollback.RolledBack, rolledBack) // Append fork
+			rollback.From = rolledBack.To                                 // Set version rolled back from
+		}
+	}
+
+	checksums, err := verifySnapshot(dataDir, entry.Snapshot) // Verify snapshot
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	since, err := transactionsSince(dataDir, entry.Snapshot) // Get transactions added since fork
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	rollback.Transactions = len(since) // Set transactions
+
+	if len(since) > 0 && !force { // Check transactions added
+		return nil, &definedError{definition: ErrTransactionsSinceFork, details: fmt.Sprintf(" (%d since the fork to %s)", len(since), entry.To)} // Return error
+	}
+
+	now := time.Now().UTC() // Get time
+
+	if rollback.Snapshot, err = Snapshot(dataDir, rollback.From, now); err != nil { // Snapshot data dir
+		return nil, err // Return found error
+	}
+
+	if len(since) > 0 { // Check has transactions to export
+		rollback.Replay = ReplayPath(dataDir, entry.Snapshot) // Set replay path
+
+		if err = writeReplay(rollback.Replay, since); err != nil { // Export transactions
+			return nil, err // Return found error
+		}
+	}
+
+	staging := filepath.Join(dataDir, Dir, fmt.Sprintf("rollback-%d", now.UnixNano())) // Get staging dir
+
+	if err = restage(dataDir, entry.Snapshot, staging, checksums); err != nil { // Swap in snapshot
+		return nil, fmt.Errorf("%s (pre-rollback data dir kept in %s)", err, SnapshotPath(dataDir, rollback.Snapshot)) // Return error
+	}
+
+	for _, rolledBack := range rollback.RolledBack { // Iterate through forks rolled back
+		rolledBack.RolledBack = &now // Mark rolled back
+	}
+
+	return rollback, writeHistory(dataDir, history) // Record rollback
+}
+
+// ReplayPath gets the path of the file that transactions added since the fork whose pre-fork snapshot has a given
+// name are exported to when it is rolled back.
+func ReplayPath(dataDir string, name string) string {
+	return filepath.Join(dataDir, Dir, "replay", name+".json") // Return path
+}
+
+// IsTransactionsSinceFork checks whether an error describes a rollback of a fork that transactions have been added
+// since (see ErrTransactionsSinceFork).
+func IsTransactionsSinceFork(err error) bool {
+	return isDefinedError(err, ErrTransactionsSinceFork) // Return matches
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// rollbackIndex gets the index in a fork history of the latest fork to a given version (or, if no version is given,
+// the latest fork) that hasn't been rolled back, or -1 if there isn't one.
+func rollbackIndex(history []*Entry, version string) int {
+	if parsed, err := ParseVersion(version); err == nil { // Check valid version
+		version = parsed.String() // Normalize version
+	}
+
+	for i := len(history) - 1; i >= 0; i-- { // Iterate through history, latest first
+		if history[i].RolledBack == nil && (version == "" || history[i].To == version) { // Check matches
+			return i // Return index
+		}
+	}
+
+	return -1 // No matching fork
+}
+
+// verifySnapshot checks every file in the snapshot with a given name in a given data dir against its recorded
+// checksums, and returns the checksums by slash-separated path relative to the snapshot.
+func verifySnapshot(dataDir string, name string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(ChecksumsPath(dataDir, name)) // Read checksums
+
+	if os.IsNotExist(err) { // Check no checksums
+		return nil, fmt.Errorf("%s: %s", ErrNoChecksums, name) // Return error
+	} else if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	checksums := make(map[string]string) // Init checksums buffer
+
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") { // Iterate through lines
+		if line == "" { // Check empty snapshot
+			continue // Continue
+		}
+
+		fields := strings.SplitN(line, "  ", 2) // Split checksum and path
+
+		if len(fields) != 2 { // Check invalid line
+			return nil, fmt.Errorf("%s: invalid line %q in %s", ErrChecksumMismatch, line, ChecksumsPath(dataDir, name)) // Return error
+		}
+
+		checksums[fields[1]] = fields[0] // Set checksum
+	}
+
+	target := SnapshotPath(dataDir, name) // Get snapshot path
+
+	verified := 0 // Init verified buffer
+
+	err = filepath.Walk(target, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() { // Check for errors, or is dir
+			return err // Return error
+		}
+
+		relative, err := filepath.Rel(target, path) // Get relative path
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		checksum, err := hashFile(path) // Hash file
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if expected, ok := checksums[filepath.ToSlash(relative)]; !ok || expected != checksum { // Check mismatch
+			return fmt.Errorf("%s: %s", ErrChecksumMismatch, filepath.ToSlash(relative)) // Return error
+		}
+
+		verified++ // Increment verified
+
+		return nil // No error occurred, return nil
+	})
+
+	if err == nil && verified != len(checksums) { // Check files missing
+		return nil, fmt.Errorf("%s: %d files missing from %s", ErrChecksumMismatch, len(checksums)-verified, name) // Return error
+	}
+
+	return checksums, err // Return checksums
+}
+
+// transactionsSince gets the transactions in the chains of a given data dir that aren't in the chains of the snapshot
+// with a given name, oldest first.
+func transactionsSince(dataDir string, name string) ([]*types.Transaction, error) {
+	snapshotted, _, err := readChains(SnapshotPath(dataDir, name)) // Read snapshotted chains
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	current, _, err := readChains(dataDir) // Read current chains
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	known := make(map[string]bool) // Init known transactions buffer
+
+	for _, chain := range snapshotted { // Iterate through snapshotted chains
+		for _, transaction := range chain.Transactions { // Iterate through transactions
+			known[transactionKey(transaction)] = true // Set known
+		}
+	}
+
+	var since []*types.Transaction // Init transactions buffer
+
+	for _, chain := range current { // Iterate through current chains
+		for _, transaction := range chain.Transactions { // Iterate through transactions
+			if key := transactionKey(transaction); !known[key] { // Check new, and not already found on another chain
+				since = append(since, transaction) // Append transaction
+
+				known[key] = true // Set known
+			}
+		}
+	}
+
+	sort.SliceStable(since, func(i, j int) bool {
+		return since[i].Timestamp.Before(since[j].Timestamp) // Sort by time
+	}) // Sort transactions
+
+	return since, nil // Return transactions
+}
+
+// transactionKey gets a key identifying a transaction: its hash, or, if it has none, its contents.
+func transactionKey(transaction *types.Transaction) string {
+	if transaction.Hash != nil { // Check has hash
+		return transaction.Hash.String() // Return hash
+	}
+
+	return string(transaction.Bytes()) // Return contents
+}
+
+// writeReplay exports a set of transactions to a given path, encoded as they are in chain files.
+func writeReplay(path string, transactions []*types.Transaction) error {
+	for _, transaction := range transactions { // Iterate through transactions
+		if err := transaction.MakeEncodingSafe(); err != nil { // Make encoding safe
+			return err // Return found error
+		}
+	}
+
+	data, err := json.MarshalIndent(transactions, "", "  ") // Marshal transactions
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil { // Make replay dir
+		return err // Return found error
+	}
+
+	return ioutil.WriteFile(path, data, 0644) // Write transactions
+}
+
+// stage copies the config, schedule, and chains in the snapshot with a given name in a given data dir into a staging
+// dir, checking each copy against its recorded checksum.
+func stage(dataDir string, name string, staging string, checksums map[string]string) error {
+	if err := os.MkdirAll(filepath.Join(staging, "db", "chain"), 0755); err != nil { // Make staged chain dir
+		return err // Return found error
+	}
+
+	for relative, expected := range checksums { // Iterate through snapshotted files
+		if relative != "config/config.json" && relative != "config/schedule.json" && !strings.HasPrefix(relative, "db/chain/") { // Check not restored
+			continue // Continue
+		}
+
+		source := filepath.Join(SnapshotPath(dataDir, name), filepath.FromSlash(relative)) // Get source
+		destination := filepath.Join(staging, filepath.FromSlash(relative))                // Get destination
+
+		info, err := os.Stat(source) // Stat source
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if err = os.MkdirAll(filepath.Dir(destination), 0755); err != nil { // Make dir
+			return err // Return found error
+		}
+
+		checksum, err := copyFile(source, destination, info.Mode()) // Copy file
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if checksum != expected { // Check mismatch
+			return fmt.Errorf("%s: staged copy of %s", ErrChecksumMismatch, relative) // Return error
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// swap swaps the staged config, schedule, and chains in a staging dir into a given data dir. The current config,
+// schedule, and chain dir are first moved into the staging dir's replaced dir, such that the current schedule is
+// removed if none was staged. If any move fails, every move made is undone. Should undoing fail too, the staging dir
+// holds whatever couldn't be moved back, and must be kept.
+func swap(dataDir string, staging string) error {
+	var moves [][2]string // Init moves buffer, each from, to
+
+	move := func(from string, to string) error {
+		if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil { // Make dir
+			return err // Return found error
+		}
+
+		if err := os.Rename(from, to); err != nil { // Move
+			return err // Return found error
+		}
+
+		moves = append(moves, [2]string{from, to}) // Record move
+
+		return nil // No error occurred, return nil
+	} // Init move
+
+	err := func() error {
+		for _, relative := range swapped { // Iterate through swapped paths
+			current := filepath.Join(dataDir, filepath.FromSlash(relative)) // Get current path
+
+			if _, err := os.Stat(current); os.IsNotExist(err) { // Check nothing to replace
+				continue // Continue
+			}
+
+			if err := move(current, filepath.Join(staging, "replaced", filepath.FromSlash(relative))); err != nil { // Move replaced file
+				return err // Return found error
+			}
+		}
+
+		for _, relative := range swapped { // Iterate through swapped paths
+			staged := filepath.Join(staging, filepath.FromSlash(relative)) // Get staged path
+
+			if _, err := os.Stat(staged); os.IsNotExist(err) && relative != "db/chain" { // Check not staged
+				continue // Continue
+			}
+
+			if err := move(staged, filepath.Join(dataDir, filepath.FromSlash(relative))); err != nil { // Swap in file
+				return err // Return found error
+			}
+		}
+
+		return nil // No error occurred, return nil
+	}() // Swap files
+
+	if err == nil { // Check swapped
+		return nil // No error occurred, return nil
+	}
+
+	for i := len(moves) - 1; i >= 0; i-- { // Iterate through moves, latest first
+		if undoErr := os.Rename(moves[i][1], moves[i][0]); undoErr != nil { // Undo move
+			return &definedError{definition: ErrIncompleteSwap, details: fmt.Sprintf(": %s; %s (replaced files kept in %s)", err, undoErr, filepath.Join(staging, "replaced"))} // Return error
+		}
+	}
+
+	return err // Return found error
+}
+
+// restore restores the config, schedule, and chains in a given data dir from the snapshot with a given name, after
+// verifying the snapshot against its recorded checksums.
+func restore(dataDir string, name string) error {
+	checksums, err := verifySnapshot(dataDir, name) // Verify snapshot
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return restage(dataDir, name, filepath.Join(dataDir, Dir, fmt.Sprintf("restore-%d", time.Now().UnixNano())), checksums) // Swap in snapshot
+}
+
+// restage stages the snapshot with a given name in a given data dir into a given staging dir, and swaps it into the
+// data dir. The staging dir is removed afterwards, unless a failed swap couldn't be undone.
+func restage(dataDir string, name string, staging string, checksums map[string]string) error {
+	err := stage(dataDir, name, staging, checksums) // Stage snapshot
+
+	if err == nil { // Check staged
+		err = swap(dataDir, staging) // Swap in snapshot
+	}
+
+	if !isDefinedError(err, ErrIncompleteSwap) { // Check staging dir not needed
+		os.RemoveAll(staging) // Remove staging dir
+	}
+
+	return err // Return error
+}
+
+// hashFile gets the SHA-256 checksum of the file at a given path.
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path) // Open file
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	defer file.Close() // Close file
+
+	hash := sha256.New() // Init hash
+
+	if _, err = io.Copy(hash, file); err != nil { // Hash file
+		return "", err // Return found error
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil // Return checksum
+}
+
+/* END INTERNAL METHODS */
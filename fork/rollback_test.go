@@ -0,0 +1,165 @@
+// Package fork defines helper methods for hard forking a SummerCash network: snapshotting its data dir, migrating its
+// chains, persisting its new chain version, and recording its fork history.
+package fork
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestRollbackFork tests the functionality of the RollbackFork() method.
+func TestRollbackFork(t *testing.T) {
+	dataDir := testDataDir(t, "1.0.0") // Init data dir
+
+	defer os.RemoveAll(dataDir) // Remove data dir
+
+	if _, err := Fork(dataDir, "1.1.0", false); err != nil { // Fork data dir
+		t.Fatal(err) // Panic
+	}
+
+	if _, err := RollbackFork(dataDir, "2.0.0", false); err == nil || !strings.HasPrefix(err.Error(), ErrNoFork.Error()) { // Check unknown fork rolled back
+		t.Fatalf("expected %v, found %v", ErrNoFork, err) // Panic
+	}
+
+	rollback, err := RollbackFork(dataDir, "v1.1.0", false) // Roll back fork
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if rollback.From != "1.1.0" || rollback.To != "1.0.0" || len(rollback.RolledBack) != 1 || rollback.Replay != "" { // Check invalid rollback
+		t.Fatalf("expected a rollback from 1.1.0 to 1.0.0 with nothing to replay, found %+v", rollback) // Panic
+	}
+
+	if chainConfig, err := readChainConfig(dataDir); err != nil || chainConfig.ChainVersion != "1.0.0" { // Check config not restored
+		t.Fatalf("expected the config to be restored to 1.0.0, found %+v (%v)", chainConfig, err) // Panic
+	}
+
+	if history, _ := History(dataDir); len(history) != 1 || history[0].RolledBack == nil { // Check rollback not recorded
+		t.Fatal("expected the fork to be marked as rolled back") // Panic
+	}
+
+	if _, err = RollbackFork(dataDir, "", false); err != ErrNoFork { // Check fork rolled back twice
+		t.Fatalf("expected %v, found %v", ErrNoFork, err) // Panic
+	}
+
+	entry, err := Fork(dataDir, "1.1.0", false) // Fork data dir again
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	chain := testReadChain(t, dataDir) // Read chain
+
+	chain.Transactions = append(chain.Transactions, &types.Transaction{Hash: &summercashCommon.Hash{1}, Timestamp: time.Now()}) // Add transaction
+
+	testWriteChain(t, dataDir, chain) // Write chain
+
+	if _, err = RollbackFork(dataDir, "", false); err == nil || !IsTransactionsSinceFork(err) { // Check rolled back over new transactions
+		t.Fatalf("expected %v, found %v", ErrTransactionsSinceFork, err) // Panic
+	}
+
+	if rollback, err = RollbackFork(dataDir, "", true); err != nil || rollback.Transactions != 1 || rollback.Replay != ReplayPath(dataDir, entry.Snapshot) { // Check not forced
+		t.Fatalf("expected a forced rollback exporting a single transaction, found %+v (%v)", rollback, err) // Panic
+	}
+
+	var replay []*types.Transaction // Init replay buffer
+
+	if data, err := ioutil.ReadFile(rollback.Replay); err != nil || json.Unmarshal(data, &replay) != nil || len(replay) != 1 { // Check not exported
+		t.Fatalf("expected a single exported transaction, found %d (%v)", len(replay), err) // Panic
+	}
+
+	if chain := testReadChain(t, dataDir); len(chain.Transactions) != 0 { // Check chain not restored
+		t.Fatalf("expected the chain to be restored without transactions, found %d", len(chain.Transactions)) // Panic
+	}
+
+	if _, err = os.Stat(SnapshotPath(dataDir, rollback.Snapshot)); err != nil { // Check pre-rollback data dir not snapshotted
+		t.Fatal(err) // Panic
+	}
+}
+
+// TestRollbackForkChecksums tests that RollbackFork() refuses to restore a snapshot that doesn't match its checksums.
+func TestRollbackForkChecksums(t *testing.T) {
+	dataDir := testDataDir(t, "1.0.0") // Init data dir
+
+	defer os.RemoveAll(dataDir) // Remove data dir
+
+	entry, err := Fork(dataDir, "1.1.0", false) // Fork data dir
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(SnapshotPath(dataDir, entry.Snapshot), "config", "roles.json"), []byte("[{}]"), 0644); err != nil { // Tamper with snapshot
+		t.Fatal(err) // Panic
+	}
+
+	if _, err = RollbackFork(dataDir, "", true); err == nil || !strings.HasPrefix(err.Error(), ErrChecksumMismatch.Error()) { // Check tampered snapshot restored
+		t.Fatalf("expected %v, found %v", ErrChecksumMismatch, err) // Panic
+	}
+
+	if chainConfig, _ := readChainConfig(dataDir); chainConfig.ChainVersion != "1.1.0" { // Check config changed
+		t.Fatalf("expected the config to be left at 1.1.0, found %s", chainConfig.ChainVersion) // Panic
+	}
+
+	if err = os.Remove(ChecksumsPath(dataDir, entry.Snapshot)); err != nil { // Remove checksums
+		t.Fatal(err) // Panic
+	}
+
+	if _, err = RollbackFork(dataDir, "", true); err == nil || !strings.HasPrefix(err.Error(), ErrNoChecksums.Error()) { // Check unverified snapshot restored
+		t.Fatalf("expected %v, found %v", ErrNoChecksums, err) // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
+
+/* BEGIN INTERNAL METHODS TESTS */
+
+// TestSwapUndo tests that the swap() method moves back everything it has moved when a move fails.
+func TestSwapUndo(t *testing.T) {
+	dataDir := testDataDir(t, "1.0.0") // Init data dir
+
+	defer os.RemoveAll(dataDir) // Remove data dir
+
+	staging := filepath.Join(dataDir, Dir, "staging") // Get staging dir
+
+	if err := os.MkdirAll(filepath.Join(staging, "db", "chain"), 0755); err != nil { // Make staged chain dir
+		t.Fatal(err) // Panic
+	}
+
+	if err := os.MkdirAll(filepath.Join(staging, "replaced"), 0755); err != nil { // Make replaced dir
+		t.Fatal(err) // Panic
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(staging, "replaced", "config"), nil, 0644); err != nil { // Block replaced config dir
+		t.Fatal(err) // Panic
+	}
+
+	if err := swap(dataDir, staging); err == nil || isDefinedError(err, ErrIncompleteSwap) { // Check swapped
+		t.Fatalf("expected the swap to fail and be undone, found %v", err) // Panic
+	}
+
+	if chain := testReadChain(t, dataDir); chain.Account.String() != testAccount { // Check chains not moved back
+		t.Fatalf("expected the chain of %s to be moved back, found %s", testAccount, chain.Account.String()) // Panic
+	}
+
+	if _, err := os.Stat(filepath.Join(staging, "db", "chain")); err != nil { // Check staged chains not moved back
+		t.Fatalf("expected the staged chains to be moved back, found %v", err) // Panic
+	}
+
+	if chainConfig, err := readChainConfig(dataDir); err != nil || chainConfig.ChainVersion != "1.0.0" { // Check config moved
+		t.Fatalf("expected the config to be left at 1.0.0, found %+v (%v)", chainConfig, err) // Panic
+	}
+}
+
+/* END INTERNAL METHODS TESTS */
@@ -0,0 +1,376 @@
+// Package fork defines helper methods for hard forking a SummerCash network: snapshotting its data dir, migrating its
+// chains, persisting its new chain version, and recording its fork history.
+package fork
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+// Spec represents a fork specification: the version a network is forked to, the point the fork activates at, and the
+// chain config fields that change once it has.
+type Spec struct {
+	Version    string      `json:"version"`          // Version forked to
+	Activation *Activation `json:"activation"`       // Activation point
+	Config     *Changes    `json:"config,omitempty"` // Chain config changes
+}
+
+// Activation represents the point a scheduled fork activates at. Exactly one of its fields is set.
+type Activation struct {
+	Time         *time.Time `json:"time,omitempty"`         // Activates for transactions at or after a time
+	Transactions *int       `json:"transactions,omitempty"` // Activates for each chain once it holds this many transactions
+}
+
+// Changes represents the chain config fields changed by a fork. Unset fields are left as they are.
+type Changes struct {
+	InflationRate *float64 `json:"inflation,omitempty"` // Inflation rate
+}
+
+// Change represents a single chain config field changed by a fork.
+type Change struct {
+	Field string `json:"field"` // Field name
+	From  string `json:"from"`  // Value before activation
+	To    string `json:"to"`    // Value after activation
+}
+
+// Plan represents what scheduling a fork would change.
+type Plan struct {
+	From       string       // Latest version before the fork
+	To         string       // Version forked to
+	Activation *Activation  // Activation point
+	Changes    []*Change    // Chain config changes
+	Migrations []*Migration // Migrations that would run
+
+	Chains int // Number of local chains
+	Active int // Number of local chains the fork would already be active on
+}
+
+var (
+	// ErrNoActivation is an error definition describing a fork spec without exactly one activation point.
+	ErrNoActivation = errors.New("fork spec must set exactly one of activation.time and activation.transactions")
+
+	// ErrInvalidActivation is an error definition describing a fork spec activating at a negative transaction count.
+	ErrInvalidActivation = errors.New("fork spec activation.transactions must not be negative")
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// ReadSpec reads and validates the fork spec at a given path. Unknown fields (including chain config fields a fork
+// can't change) are rejected.
+func ReadSpec(path string) (*Spec, error) {
+	data, err := ioutil.ReadFile(path) // Read spec
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data)) // Init decoder
+
+	decoder.DisallowUnknownFields() // Reject unknown fields
+
+	spec := &Spec{} // Init spec buffer
+
+	if err = decoder.Decode(spec); err != nil { // Decode spec
+		return nil, fmt.Errorf("invalid fork spec %s: %s", path, err) // Return error
+	}
+
+	if err = spec.Validate(); err != nil { // Validate spec
+		return nil, err // Return found error
+	}
+
+	version, _ := ParseVersion(spec.Version) // Parse version
+
+	spec.Version = version.String() // Normalize version
+
+	return spec, nil // Return spec
+}
+
+// Validate checks that a fork spec has a valid version and exactly one activation point.
+func (spec *Spec) Validate() error {
+	if _, err := ParseVersion(spec.Version); err != nil { // Check invalid version
+		return err // Return found error
+	}
+
+	if spec.Activation == nil || (spec.Activation.Time == nil) == (spec.Activation.Transactions == nil) { // Check not exactly one activation point
+		return ErrNoActivation // Return error
+	}
+
+	if spec.Activation.Transactions != nil && *spec.Activation.Transactions < 0 { // Check negative count
+		return ErrInvalidActivation // Return error
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// Active checks whether an activation point has been reached by the transaction at a given position in its chain,
+// made at a given time.
+func (activation *Activation) Active(at time.Time, position int) bool {
+	if activation.Time != nil { // Check time activation
+		return !at.Before(*activation.Time) // Return reached
+	}
+
+	return activation.Transactions != nil && position >= *activation.Transactions // Return reached
+}
+
+// String formats an activation point.
+func (activation *Activation) String() string {
+	if activation.Time != nil { // Check time activation
+		return "at " + activation.Time.UTC().Format(time.RFC3339) // Return time
+	}
+
+	if activation.Transactions != nil { // Check count activation
+		return fmt.Sprintf("once each chain holds %d transactions", *activation.Transactions) // Return count
+	}
+
+	return "never" // No activation point
+}
+
+// Effective gets the time an activation point takes effect across a network with a given set of chains: its time, or, for
+// a transaction count, the first time any of the chains reached it. The zero time is returned if none has.
+func (activation *Activation) Effective(chains []*types.Chain) time.Time {
+	if activation.Time != nil { // Check time activation
+		return *activation.Time // Return time
+	}
+
+	var earliest time.Time // Init earliest buffer
+
+	for _, chain := range chains { // Iterate through chains
+		if activation.Transactions == nil || len(chain.Transactions) <= *activation.Transactions || chain.Transactions[*activation.Transactions] == nil { // Check not reached
+			continue // Continue
+		}
+
+		if at := chain.Transactions[*activation.Transactions].Timestamp; earliest.IsZero() || at.Before(earliest) { // Check earlier
+			earliest = at // Set earliest
+		}
+	}
+
+	return earliest // Return earliest
+}
+
+// ReadSchedule reads the forks scheduled for the network in a given data dir, in order of version.
+func ReadSchedule(dataDir string) ([]*Spec, error) {
+	data, err := ioutil.ReadFile(SchedulePath(dataDir)) // Read schedule
+
+	if os.IsNotExist(err) { // Check no schedule
+		return nil, nil // No schedule
+	} else if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	var schedule []*Spec // Init schedule buffer
+
+	err = json.Unmarshal(data, &schedule) // Unmarshal schedule
+
+	return schedule, err // Return schedule
+}
+
+// SchedulePath gets the path of the fork schedule in a given data dir. It's kept in the config dir, alongside the
+// chain config it amends.
+func SchedulePath(dataDir string) string {
+	return filepath.Join(dataDir, "config", "schedule.json") // Return path
+}
+
+// LatestVersion gets the newest version of the network in a given data dir, whether in effect or scheduled.
+func LatestVersion(dataDir string) (string, error) {
+	chainConfig, err := readChainConfig(dataDir) // Read chain config
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	schedule, err := ReadSchedule(dataDir) // Read schedule
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	return latestVersion(chainConfig, schedule) // Return latest version
+}
+
+// PlanSchedule gets what scheduling a fork for the network in a given data dir would change, without changing it.
+func PlanSchedule(dataDir string, spec *Spec) (*Plan, error) {
+	chainConfig, schedule, err := readScheduled(dataDir, spec) // Read and check schedule
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	latest, _ := latestVersion(chainConfig, schedule) // Get latest version
+
+	plan := &Plan{From: latest, To: spec.Version, Activation: spec.Activation} // Init plan
+
+	if plan.Migrations, err = Pending(latest, spec.Version); err != nil { // Get pending migrations
+		return nil, err // Return found error
+	}
+
+	previous := finalConfig(chainConfig, schedule) // Get config once every scheduled fork is active
+
+	if spec.Config != nil && spec.Config.InflationRate != nil { // Check changes inflation
+		plan.Changes = append(plan.Changes, &Change{Field: "inflation", From: formatRate(previous.InflationRate), To: formatRate(*spec.Config.InflationRate)}) // Append change
+	}
+
+	plan.Changes = append(plan.Changes, &Change{Field: "version", From: previous.ChainVersion, To: spec.Version}) // Append version change
+
+	chains, _, err := readChains(dataDir) // Read chains
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	plan.Chains = len(chains) // Set chains
+
+	for _, chain := range chains { // Iterate through chains
+		if spec.Activation.Active(time.Now(), len(chain.Transactions)) { // Check active for the chain's next transaction
+			plan.Active++ // Increment active
+		}
+	}
+
+	return plan, nil // Return plan
+}
+
+// Schedule schedules a fork for the network in a given data dir. As with Fork, the data dir is snapshotted, the fork is
+// recorded before anything is changed, any migrations up to the fork's version are run, and the snapshot is restored
+// if any step fails. The chain config is left as it is, however: the fork is appended to the network's fork schedule
+// instead, for nodes sharing the config dir to switch to at its activation point. Puppet itself only follows the
+// schedule when computing inflation; the chain version it reads is always the one in the config.
+func Schedule(dataDir string, spec *Spec) (*Entry, error) {
+	chainConfig, schedule, err := readScheduled(dataDir, spec) // Read and check schedule
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	latest, _ := latestVersion(chainConfig, schedule) // Get latest version
+
+	pending, err := Pending(latest, spec.Version) // Get pending migrations
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	entry := &Entry{From: latest, To: spec.Version, Time: time.Now().UTC(), Activation: spec.Activation} // Init entry
+
+	for _, migration := range pending { // Iterate through migrations
+		entry.Migrations = append(entry.Migrations, fmt.Sprintf("%s: %s", migration.Version, migration.Description)) // Append migration
+	}
+
+	data, err := json.MarshalIndent(append(schedule, spec), "", "  ") // Marshal schedule
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	hash := sha256.Sum256(data) // Hash schedule
+
+	entry.ConfigHash = hex.EncodeToString(hash[:]) // Set hash
+
+	err = record(dataDir, entry, func() error {
+		if err := migrate(dataDir, chainConfig, pending); err != nil { // Migrate chains
+			return err // Return found error
+		}
+
+		return writeFile(SchedulePath(dataDir), data) // Write schedule
+	}) // Apply and record fork
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return entry, nil // No error occurred, return entry
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// readScheduled reads the chain config and fork schedule of the network in a given data dir, and checks that a fork
+// spec can be appended to it.
+func readScheduled(dataDir string, spec *Spec) (*config.ChainConfig, []*Spec, error) {
+	if err := spec.Validate(); err != nil { // Validate spec
+		return nil, nil, err // Return found error
+	}
+
+	chainConfig, err := readChainConfig(dataDir) // Read chain config
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	schedule, err := ReadSchedule(dataDir) // Read schedule
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	latest, err := latestVersion(chainConfig, schedule) // Get latest version
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	if comparison, _ := CompareVersions(spec.Version, latest); comparison <= 0 { // Check not newer
+		return nil, nil, fmt.Errorf("%s (%s is not newer than %s)", ErrNotNewer, spec.Version, latest) // Return error
+	}
+
+	return chainConfig, schedule, nil // Return schedule
+}
+
+// finalConfig gets the chain config in effect once every scheduled fork has activated, by applying each to a copy of a
+// base chain config. A fork's version only replaces the config's if it's newer.
+func finalConfig(base *config.ChainConfig, schedule []*Spec) *config.ChainConfig {
+	effective := *base // Copy base
+
+	for _, spec := range schedule { // Iterate through schedule
+		if comparison, err := CompareVersions(spec.Version, effective.ChainVersion); err == nil && comparison > 0 { // Check newer
+			effective.ChainVersion = spec.Version // Set version
+		}
+
+		if spec.Config != nil && spec.Config.InflationRate != nil { // Check changes inflation
+			effective.InflationRate = *spec.Config.InflationRate // Set inflation rate
+		}
+	}
+
+	return &effective // Return effective config
+}
+
+// latestVersion gets the newest of a chain config's version and the versions of a fork schedule.
+func latestVersion(chainConfig *config.ChainConfig, schedule []*Spec) (string, error) {
+	if _, err := ParseVersion(chainConfig.ChainVersion); err != nil { // Check invalid stored version
+		return "", &definedError{definition: ErrInvalidStoredVersion, details: fmt.Sprintf(": %q (expected MAJOR.MINOR.PATCH)", chainConfig.ChainVersion)} // Return error
+	}
+
+	latest := chainConfig.ChainVersion // Init latest
+
+	for _, spec := range schedule { // Iterate through schedule
+		comparison, err := CompareVersions(spec.Version, latest) // Compare versions
+
+		if err != nil { // Check for errors
+			return "", err // Return found error
+		}
+
+		if comparison > 0 { // Check newer
+			latest = spec.Version // Set latest
+		}
+	}
+
+	return latest, nil // Return latest
+}
+
+// formatRate formats an inflation rate.
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64) // Return rate
+}
+
+/* END INTERNAL METHODS */
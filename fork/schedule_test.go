@@ -0,0 +1,158 @@
+// Package fork defines helper methods for hard forking a SummerCash network: snapshotting its data dir, migrating its
+// chains, persisting its new chain version, and recording its fork history.
+package fork
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestReadSpec tests the functionality of the ReadSpec() method.
+func TestReadSpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "puppet-spec") // Make temp dir
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	defer os.RemoveAll(dir) // Remove temp dir
+
+	for contents, valid := range map[string]bool{
+		`{"version": "0.8.0", "activation": {"time": "2027-01-01T00:00:00Z"}, "config": {"inflation": 0.02}}`: true, // Time activation
+		`{"version": "0.8.0", "activation": {"transactions": 10}}`:                                            true, // Count activation
+		`{"version": "0.8.0"}`: false, // No activation
+		`{"version": "0.8.0", "activation": {"time": "2027-01-01T00:00:00Z", "transactions": 10}}`: false, // Both activations
+		`{"version": "0.8.0", "activation": {"transactions": -1}}`:                                 false, // Negative count
+		`{"version": "0.8.x", "activation": {"transactions": 10}}`:                                 false, // Invalid version
+		`{"version": "0.8.0", "activation": {"transactions": 10}, "config": {"network": 2}}`:       false, // Unchangeable field
+	} { // Iterate through specs
+		path := filepath.Join(dir, "fork.json") // Get spec path
+
+		if err = ioutil.WriteFile(path, []byte(contents), 0644); err != nil { // Write spec
+			t.Fatal(err) // Panic
+		}
+
+		if _, err = ReadSpec(path); (err == nil) != valid { // Check unexpected result
+			t.Fatalf("expected %s to be valid: %t, found %v", contents, valid, err) // Panic
+		}
+	}
+}
+
+// TestSchedule tests the functionality of the Schedule() and PlanSchedule() methods.
+func TestSchedule(t *testing.T) {
+	dataDir := testDataDir(t, "1.0.0") // Init data dir
+
+	defer os.RemoveAll(dataDir) // Remove data dir
+
+	inflation, transactions := 0.5, 0 // Init spec values
+
+	spec := &Spec{Version: "1.1.0", Activation: &Activation{Transactions: &transactions}, Config: &Changes{InflationRate: &inflation}} // Init spec
+
+	plan, err := PlanSchedule(dataDir, spec) // Plan fork
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if plan.From != "1.0.0" || len(plan.Changes) != 2 || plan.Changes[0].To != "0.5" || plan.Chains != 1 || plan.Active != 1 { // Check invalid plan
+		t.Fatalf("expected a plan changing inflation to 0.5, active on the only chain, found %+v", plan) // Panic
+	}
+
+	entry, err := Schedule(dataDir, spec) // Schedule fork
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if entry.To != "1.1.0" || entry.Activation == nil { // Check invalid entry
+		t.Fatalf("expected a scheduled fork to 1.1.0, found %+v", entry) // Panic
+	}
+
+	if chainConfig, _ := readChainConfig(dataDir); chainConfig.ChainVersion != "1.0.0" || chainConfig.InflationRate != 0 { // Check config changed
+		t.Fatalf("expected the config to be left at 1.0.0, found %+v", chainConfig) // Panic
+	}
+
+	if schedule, err := ReadSchedule(dataDir); err != nil || len(schedule) != 1 || schedule[0].Version != "1.1.0" { // Check not scheduled
+		t.Fatalf("expected a single scheduled fork, found %d (%v)", len(schedule), err) // Panic
+	}
+
+	if latest, err := LatestVersion(dataDir); err != nil || latest != "1.1.0" { // Check scheduled version ignored
+		t.Fatalf("expected a latest version of 1.1.0, found %s (%v)", latest, err) // Panic
+	}
+
+	if _, err = Schedule(dataDir, spec); err == nil { // Check same version scheduled twice
+		t.Fatal("expected an error for a fork to an already scheduled version") // Panic
+	}
+
+	if _, err = Fork(dataDir, "1.0.1", false); err == nil { // Check fork to a version behind the schedule
+		t.Fatal("expected an error for a fork to a version older than a scheduled one") // Panic
+	}
+}
+
+// TestScheduleRestore tests that Schedule() restores the data dir from its snapshot, and leaves the fork unrecorded,
+// when the schedule can't be written after the chains have been migrated.
+func TestScheduleRestore(t *testing.T) {
+	dataDir := testDataDir(t, "1.0.0") // Init data dir
+
+	defer os.RemoveAll(dataDir) // Remove data dir
+
+	defer func() { migrations = nil }() // Reset migrations
+
+	Register(&Migration{Version: "1.1.0", Description: "block schedule", Migrate: func(chain *types.Chain, chainConfig *config.ChainConfig) error {
+		chain.NetworkID = chainConfig.NetworkID // Set network ID
+
+		return os.MkdirAll(filepath.Join(SchedulePath(dataDir), "blocked"), 0755) // Make schedule unwritable
+	}}) // Register migration
+
+	transactions := 0 // Init activation count
+
+	if _, err := Schedule(dataDir, &Spec{Version: "1.1.0", Activation: &Activation{Transactions: &transactions}}); err == nil || !strings.Contains(err.Error(), "data dir restored") { // Check not restored
+		t.Fatalf("expected the data dir to be restored after a failed schedule write, found %v", err) // Panic
+	}
+
+	if chain := testReadChain(t, dataDir); chain.NetworkID == 7 { // Check chain left migrated
+		t.Fatal("expected the migrated chain to be restored") // Panic
+	}
+
+	if _, err := os.Stat(SchedulePath(dataDir)); !os.IsNotExist(err) { // Check schedule left behind
+		t.Fatalf("expected no schedule, found %v", err) // Panic
+	}
+
+	if history, err := History(dataDir); err != nil || len(history) != 0 { // Check failed fork recorded
+		t.Fatalf("expected a failed fork to go unrecorded, found %d forks (%v)", len(history), err) // Panic
+	}
+}
+
+// TestActivation tests the functionality of the Active() and Effective() methods of an activation point.
+func TestActivation(t *testing.T) {
+	activation := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC) // Get activation time
+
+	transactions := 3 // Init transaction count
+
+	byTime, byCount := &Activation{Time: &activation}, &Activation{Transactions: &transactions} // Init activation points
+
+	if byTime.Active(activation.Add(-time.Second), 0) || !byTime.Active(activation, 0) { // Check invalid time activation
+		t.Fatalf("expected a time activation point to be reached at %s", activation) // Panic
+	}
+
+	if byCount.Active(activation, 2) || !byCount.Active(activation, 3) { // Check invalid count activation
+		t.Fatal("expected a count activation point to be reached by the fourth transaction of a chain") // Panic
+	}
+
+	chains := []*types.Chain{{Transactions: make([]*types.Transaction, 3)}, {Transactions: []*types.Transaction{{}, {}, {}, {Timestamp: activation}}}} // Init chains
+
+	if at := byCount.Effective(chains); !at.Equal(activation) { // Check invalid activation time
+		t.Fatalf("expected the count to be reached at %s, found %s", activation, at) // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
@@ -0,0 +1,272 @@
+// Package fork defines helper methods for hard forking a SummerCash network: snapshotting its data dir, migrating its
+// chains, persisting its new chain version, and recording its fork history.
+package fork
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SummerCash/go-summercash/config"
+)
+
+// Version represents a semantic version (see https://semver.org): MAJOR.MINOR.PATCH, optionally followed by
+// pre-release identifiers (-rc.1) and build metadata (+build.5).
+type Version struct {
+	Major uint64 // Major version
+	Minor uint64 // Minor version
+	Patch uint64 // Patch version
+
+	PreRelease []string // Pre-release identifiers
+	Build      []string // Build metadata identifiers
+}
+
+var (
+	// ErrInvalidVersion is an error definition describing a version that isn't a valid semantic version.
+	ErrInvalidVersion = errors.New("invalid semantic version; expected MAJOR.MINOR.PATCH with optional -PRERELEASE and +BUILD (e.g. 0.7.3 or 1.0.0-rc.1)")
+
+	// ErrUnknownPart is an error definition describing a version bump of an unsupported version part.
+	ErrUnknownPart = errors.New("unknown version part; expected major, minor, or patch")
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// ParseVersion parses a semantic version. A leading v (as in v1.2.3) is accepted and dropped.
+func ParseVersion(s string) (*Version, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%s: %q %s", ErrInvalidVersion, s, reason) // Return error
+	}
+
+	remaining := strings.TrimPrefix(s, "v") // Trim prefix
+
+	version := &Version{} // Init version buffer
+
+	if i := strings.Index(remaining, "+"); i >= 0 { // Check has build metadata
+		build := strings.Split(remaining[i+1:], ".") // Split build metadata
+
+		for _, identifier := range build { // Iterate through identifiers
+			if !validIdentifier(identifier) { // Check invalid identifier
+				return nil, invalid("has invalid build metadata") // Return error
+			}
+		}
+
+		version.Build, remaining = build, remaining[:i] // Set build metadata
+	}
+
+	if i := strings.Index(remaining, "-"); i >= 0 { // Check has pre-release
+		preRelease := strings.Split(remaining[i+1:], ".") // Split pre-release
+
+		for _, identifier := range preRelease { // Iterate through identifiers
+			if !validIdentifier(identifier) || (isNumeric(identifier) && len(identifier) > 1 && identifier[0] == '0') { // Check invalid identifier
+				return nil, invalid("has an invalid pre-release") // Return error
+			}
+		}
+
+		version.PreRelease, remaining = preRelease, remaining[:i] // Set pre-release
+	}
+
+	core := strings.Split(remaining, ".") // Split core
+
+	if len(core) != 3 { // Check not MAJOR.MINOR.PATCH
+		return nil, invalid(fmt.Sprintf("has %d version numbers instead of 3", len(core))) // Return error
+	}
+
+	for i, target := range []*uint64{&version.Major, &version.Minor, &version.Patch} { // Iterate through version numbers
+		if !isNumeric(core[i]) || (len(core[i]) > 1 && core[i][0] == '0') { // Check not a number, or has leading zeros
+			return nil, invalid(fmt.Sprintf("has an invalid version number %q", core[i])) // Return error
+		}
+
+		number, err := strconv.ParseUint(core[i], 10, 64) // Parse number
+
+		if err != nil { // Check for errors
+			return nil, invalid(fmt.Sprintf("has an out of range version number %q", core[i])) // Return error
+		}
+
+		*target = number // Set number
+	}
+
+	return version, nil // Return version
+}
+
+// String formats a semantic version.
+func (version *Version) String() string {
+	formatted := fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Patch) // Format core
+
+	if len(version.PreRelease) > 0 { // Check has pre-release
+		formatted += "-" + strings.Join(version.PreRelease, ".") // Append pre-release
+	}
+
+	if len(version.Build) > 0 { // Check has build metadata
+		formatted += "+" + strings.Join(version.Build, ".") // Append build metadata
+	}
+
+	return formatted // Return formatted
+}
+
+// Compare compares the precedence of two semantic versions, returning -1, 0, or 1 if the first is older than, the
+// same as, or newer than the second. Build metadata is ignored, and a pre-release precedes its release.
+func (version *Version) Compare(other *Version) int {
+	for _, pair := range [][2]uint64{{version.Major, other.Major}, {version.Minor, other.Minor}, {version.Patch, other.Patch}} { // Iterate through version numbers
+		if pair[0] != pair[1] { // Check differ
+			return compareUints(pair[0], pair[1]) // Return comparison
+		}
+	}
+
+	switch {
+	case len(version.PreRelease) == 0 && len(other.PreRelease) == 0:
+		return 0 // Same release
+	case len(version.PreRelease) == 0:
+		return 1 // Release is newer than pre-release
+	case len(other.PreRelease) == 0:
+		return -1 // Pre-release is older than release
+	}
+
+	for i := 0; i < len(version.PreRelease) && i < len(other.PreRelease); i++ { // Iterate through shared identifiers
+		a, b := version.PreRelease[i], other.PreRelease[i] // Get identifiers
+
+		if a == b { // Check same
+			continue // Continue
+		}
+
+		switch {
+		case isNumeric(a) && isNumeric(b):
+			numberA, _ := strconv.ParseUint(a, 10, 64) // Parse a
+			numberB, _ := strconv.ParseUint(b, 10, 64) // Parse b
+
+			return compareUints(numberA, numberB) // Compare numerically
+		case isNumeric(a):
+			return -1 // Numeric identifiers precede alphanumeric ones
+		case isNumeric(b):
+			return 1 // Alphanumeric identifiers follow numeric ones
+		case a < b:
+			return -1 // Compare lexically
+		}
+
+		return 1 // Compare lexically
+	}
+
+	return compareUints(uint64(len(version.PreRelease)), uint64(len(other.PreRelease))) // Longer pre-release is newer
+}
+
+// Bump gets the version following a semantic version when a given part of it (major, minor, or patch) is bumped.
+// Pre-release and build metadata are dropped. A pre-release is bumped to its own release where that's the next
+// version of the part being bumped (e.g. bumping the patch of 1.2.3-rc.1 gives 1.2.3).
+func (version *Version) Bump(part string) (*Version, error) {
+	bumped := &Version{Major: version.Major, Minor: version.Minor, Patch: version.Patch} // Copy version numbers
+
+	preRelease := len(version.PreRelease) > 0 // Check is pre-release
+
+	switch part {
+	case "major":
+		if !preRelease || version.Minor != 0 || version.Patch != 0 { // Check not a pre-release of the next major version
+			bumped.Major, bumped.Minor, bumped.Patch = version.Major+1, 0, 0 // Bump major
+		}
+	case "minor":
+		if !preRelease || version.Patch != 0 { // Check not a pre-release of the next minor version
+			bumped.Minor, bumped.Patch = version.Minor+1, 0 // Bump minor
+		}
+	case "patch":
+		if !preRelease { // Check not a pre-release of the next patch
+			bumped.Patch++ // Bump patch
+		}
+	default:
+		return nil, fmt.Errorf("%s: %q", ErrUnknownPart, part) // Return error
+	}
+
+	return bumped, nil // Return bumped
+}
+
+// CompareVersions compares two semantic versions, returning -1, 0, or 1 if the first is older than, the same as, or
+// newer than the second.
+func CompareVersions(a string, b string) (int, error) {
+	parsedA, err := ParseVersion(a) // Parse a
+
+	if err != nil { // Check for errors
+		return 0, err // Return found error
+	}
+
+	parsedB, err := ParseVersion(b) // Parse b
+
+	if err != nil { // Check for errors
+		return 0, err // Return found error
+	}
+
+	return parsedA.Compare(parsedB), nil // Return comparison
+}
+
+// BumpVersion bumps a given part (major, minor, or patch) of a semantic version.
+func BumpVersion(current string, part string) (string, error) {
+	parsed, err := ParseVersion(current) // Parse current version
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	bumped, err := parsed.Bump(part) // Bump version
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	return bumped.String(), nil // Return bumped
+}
+
+// NextVersion gets the version a network at a given version is forked to by default: the chain version of the
+// running SummerCash release if it's newer, or otherwise the given version with its patch bumped.
+func NextVersion(current string) (string, error) {
+	if comparison, err := CompareVersions(config.Version, current); err != nil || comparison > 0 { // Check release newer
+		return config.Version, err // Return release version
+	}
+
+	return BumpVersion(current, "patch") // Return bumped version
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// validIdentifier checks whether a pre-release or build metadata identifier is a non-empty run of ASCII alphanumerics
+// and hyphens.
+func validIdentifier(identifier string) bool {
+	if identifier == "" { // Check empty
+		return false // Invalid
+	}
+
+	for _, character := range identifier { // Iterate through characters
+		if !(character >= '0' && character <= '9' || character >= 'a' && character <= 'z' || character >= 'A' && character <= 'Z' || character == '-') { // Check invalid character
+			return false // Invalid
+		}
+	}
+
+	return true // Valid
+}
+
+// isNumeric checks whether a string is a non-empty run of ASCII digits.
+func isNumeric(s string) bool {
+	if s == "" { // Check empty
+		return false // Not numeric
+	}
+
+	for _, character := range s { // Iterate through characters
+		if character < '0' || character > '9' { // Check not a digit
+			return false // Not numeric
+		}
+	}
+
+	return true // Numeric
+}
+
+// compareUints compares two unsigned integers, returning -1, 0, or 1.
+func compareUints(a uint64, b uint64) int {
+	switch {
+	case a < b:
+		return -1 // Lower
+	case a > b:
+		return 1 // Higher
+	}
+
+	return 0 // Equal
+}
+
+/* END INTERNAL METHODS */
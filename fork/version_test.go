@@ -0,0 +1,80 @@
+// Package fork defines helper methods for hard forking a SummerCash network: snapshotting its data dir, migrating its
+// chains, persisting its new chain version, and recording its fork history.
+package fork
+
+import (
+	"testing"
+
+	"github.com/SummerCash/go-summercash/config"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestParseVersion tests the functionality of the ParseVersion() method.
+func TestParseVersion(t *testing.T) {
+	for input, expected := range map[string]string{"0.7.3": "0.7.3", "v1.2.3": "1.2.3", "1.0.0-rc.1+build.5": "1.0.0-rc.1+build.5", "1.0.0+20190314": "1.0.0+20190314"} { // Iterate through valid versions
+		if version, err := ParseVersion(input); err != nil || version.String() != expected { // Check not parsed
+			t.Fatalf("expected %s to parse as %s, found %v (%v)", input, expected, version, err) // Panic
+		}
+	}
+
+	for _, input := range []string{"", "0.7", "1.2.3.4", "01.2.3", "1.2.x", "1.2.3-", "1.2.3-rc..1", "1.2.3-01", "1.2.3+b_1", "-1.2.3"} { // Iterate through invalid versions
+		if _, err := ParseVersion(input); err == nil { // Check parsed
+			t.Fatalf("expected an error for %q", input) // Panic
+		}
+	}
+}
+
+// TestCompareVersions tests the functionality of the CompareVersions() method.
+func TestCompareVersions(t *testing.T) {
+	ordered := []string{"0.7.3", "0.10.0", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0"} // Init versions, in order of precedence
+
+	for i := 1; i < len(ordered); i++ { // Iterate through versions
+		if comparison, err := CompareVersions(ordered[i-1], ordered[i]); err != nil || comparison != -1 { // Check not older
+			t.Fatalf("expected %s to precede %s, found %d (%v)", ordered[i-1], ordered[i], comparison, err) // Panic
+		}
+
+		if comparison, _ := CompareVersions(ordered[i], ordered[i-1]); comparison != 1 { // Check not newer
+			t.Fatalf("expected %s to follow %s, found %d", ordered[i], ordered[i-1], comparison) // Panic
+		}
+	}
+
+	if comparison, err := CompareVersions("1.0.0+a", "1.0.0+b"); err != nil || comparison != 0 { // Check build metadata compared
+		t.Fatalf("expected build metadata to be ignored, found %d (%v)", comparison, err) // Panic
+	}
+
+	if _, err := CompareVersions("0.7.x", "0.7.3"); err == nil { // Check invalid version compared
+		t.Fatal("expected an error for an invalid version") // Panic
+	}
+}
+
+// TestBumpVersion tests the functionality of the BumpVersion() method.
+func TestBumpVersion(t *testing.T) {
+	for _, test := range []struct {
+		current, part, expected string // Bump
+	}{
+		{"1.2.3", "patch", "1.2.4"}, {"1.2.3", "minor", "1.3.0"}, {"1.2.3+build", "major", "2.0.0"}, // Releases
+		{"1.2.3-rc.1", "patch", "1.2.3"}, {"1.3.0-rc.1", "minor", "1.3.0"}, {"1.2.3-rc.1", "minor", "1.3.0"}, {"2.0.0-rc.1", "major", "2.0.0"}, // Pre-releases
+	} { // Iterate through tests
+		if bumped, err := BumpVersion(test.current, test.part); err != nil || bumped != test.expected { // Check invalid bump
+			t.Fatalf("expected bumping the %s of %s to give %s, found %s (%v)", test.part, test.current, test.expected, bumped, err) // Panic
+		}
+	}
+
+	if _, err := BumpVersion("1.2.3", "build"); err == nil { // Check unknown part bumped
+		t.Fatal("expected an error for an unknown version part") // Panic
+	}
+}
+
+// TestNextVersion tests the functionality of the NextVersion() method.
+func TestNextVersion(t *testing.T) {
+	if version, err := NextVersion("0.0.1"); err != nil || version != config.Version { // Check release version not used
+		t.Fatalf("expected %s, found %s (%v)", config.Version, version, err) // Panic
+	}
+
+	if version, err := NextVersion("99.1.0"); err != nil || version != "99.1.1" { // Check patch not bumped
+		t.Fatalf("expected 99.1.1, found %s (%v)", version, err) // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
@@ -0,0 +1,644 @@
+// Package genesis defines the puppet genesis file format, along with helper methods for decoding and validating genesis files.
+package genesis
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+
+	"github.com/SummerCash/puppet/vesting"
+)
+
+// CurrentVersion is the newest genesis file format version understood by puppet.
+// Version 2 adds the chainVersion field. Version 3 adds alloc entry roles and names, along with
+// alloc entries keyed by name, whose accounts are generated when the network is created. Version 4 adds
+// alloc entry vesting schedules.
+const CurrentVersion = 4
+
+const (
+	// RoleGenesis is the role of the genesis account, which holds the network's total supply.
+	RoleGenesis = "genesis"
+
+	// RoleFaucet is the role of a faucet account, which is registered with the wallet server.
+	RoleFaucet = "faucet"
+
+	// RoleTreasury is the role of a treasury account.
+	RoleTreasury = "treasury"
+
+	// RoleValidator is the role of a validator account.
+	RoleValidator = "validator"
+)
+
+// Genesis represents a genesis file. Fields left nil have not been specified.
+type Genesis struct {
+	Version uint `json:"version"` // Genesis file format version
+
+	NetworkID    *uint    `json:"networkID,omitempty"`    // Network ID
+	Inflation    *float64 `json:"inflation,omitempty"`    // Inflation rate
+	ChainVersion *string  `json:"chainVersion,omitempty"` // Chain version
+
+	Alloc Alloc `json:"alloc,omitempty"` // Genesis balances; the first entry holds the total supply
+
+	Comments Comments `json:"-"` // Comments attached to each value, keyed by path
+}
+
+// Alloc represents an ordered set of genesis balances.
+type Alloc []*AllocEntry
+
+// AllocEntry represents the genesis balance of a single address. Entries without an address are keyed
+// by their name, and have their account generated when the network is created.
+type AllocEntry struct {
+	Address string `json:"-"`              // Address
+	Role    string `json:"role,omitempty"` // Role (genesis, faucet, treasury, or validator)
+	Name    string `json:"name,omitempty"` // Name
+	Balance string `json:"balance"`        // Balance
+
+	Vesting *vesting.Schedule `json:"vesting,omitempty"` // Vesting schedule
+}
+
+// ValidationError represents a problem with the value at a particular path in a genesis file.
+type ValidationError struct {
+	Path    string // JSON path of the offending value
+	Message string // Description of the problem
+}
+
+// ValidationErrors represents every problem found in a genesis file.
+type ValidationErrors []*ValidationError
+
+var (
+	// ErrInvalidAllocFlag is an error definition describing an alloc flag not in the form address=amount.
+	ErrInvalidAllocFlag = errors.New("expected address=amount")
+
+	// ErrMissingBalance is an error definition describing a chain config alloc address without a balance.
+	ErrMissingBalance = errors.New("chain config has no alloc balance for address")
+
+	// ErrUngeneratedEntry is an error definition describing an alloc entry whose account has not been generated yet.
+	ErrUngeneratedEntry = errors.New("account has not been generated for alloc entry")
+
+	// Roles are the roles an alloc entry may have.
+	Roles = []string{RoleGenesis, RoleFaucet, RoleTreasury, RoleValidator}
+
+	// namePattern matches valid alloc entry names, which are used in file names and wallet server usernames.
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// Decode strictly decodes a JSON genesis file, rejecting unknown keys, and validates the result.
+// If any problems are found, a ValidationErrors describing each of them is returned.
+func Decode(b []byte) (*Genesis, error) {
+	var raw struct {
+		Version      *uint           `json:"version"`      // Genesis file format version
+		NetworkID    *uint           `json:"networkID"`    // Network ID
+		Inflation    *float64        `json:"inflation"`    // Inflation rate
+		ChainVersion *string         `json:"chainVersion"` // Chain version
+		Alloc        json.RawMessage `json:"alloc"`        // Genesis balances
+	} // Init raw genesis buffer
+
+	if len(bytes.TrimSpace(b)) == 0 { // Check empty
+		b = []byte("{}") // Treat as empty genesis
+	}
+
+	err := decodeStrict(b, &raw) // Decode raw genesis
+
+	if err != nil { // Check for errors
+		return nil, ValidationErrors{decodeError("", err)} // Return decode error
+	}
+
+	genesis := &Genesis{
+		Version:      1,                // Set version
+		NetworkID:    raw.NetworkID,    // Set network ID
+		Inflation:    raw.Inflation,    // Set inflation
+		ChainVersion: raw.ChainVersion, // Set chain version
+	} // Init genesis
+
+	if raw.Version != nil { // Check has version
+		genesis.Version = *raw.Version // Set version
+	}
+
+	var problems ValidationErrors // Init problems buffer
+
+	if len(raw.Alloc) > 0 && string(raw.Alloc) != "null" { // Check has alloc
+		genesis.Alloc, problems = decodeAlloc(raw.Alloc) // Decode alloc
+	}
+
+	problems = append(problems, genesis.validate()...) // Validate
+
+	if len(problems) > 0 { // Check has problems
+		return genesis, problems // Return problems
+	}
+
+	return genesis, nil // No error occurred, return genesis
+}
+
+// Validate checks a genesis for bad addresses, invalid or negative balances, duplicate alloc entries,
+// and a zero supply. If any problems are found, a ValidationErrors describing each of them is returned.
+func (genesis *Genesis) Validate() error {
+	if problems := genesis.validate(); len(problems) > 0 { // Check has problems
+		return problems // Return problems
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// FromChainConfig makes a genesis reproducing the network ID, inflation rate, chain version, and alloc of a given chain config.
+// The genesis account's private key can't be carried over, so its entry is generated rather than keyed by its address.
+func FromChainConfig(chainConfig *config.ChainConfig) (*Genesis, error) {
+	networkID := chainConfig.NetworkID       // Get network ID
+	inflation := chainConfig.InflationRate   // Get inflation rate
+	chainVersion := chainConfig.ChainVersion // Get chain version
+
+	genesis := &Genesis{
+		Version:      CurrentVersion, // Set version
+		NetworkID:    &networkID,     // Set network ID
+		Inflation:    &inflation,     // Set inflation
+		ChainVersion: &chainVersion,  // Set chain version
+		Alloc:        Alloc{},        // Set alloc
+	} // Init genesis
+
+	for i, address := range chainConfig.AllocAddresses { // Iterate through alloc addresses
+		balance, ok := chainConfig.Alloc[address.String()] // Get balance
+
+		if !ok || balance == nil { // Check no balance
+			return nil, fmt.Errorf("%s: %s", ErrMissingBalance, address.String()) // Return error
+		}
+
+		entry := &AllocEntry{Address: address.String(), Balance: balance.Text('f', -1)} // Init entry
+
+		if i == 0 { // Check is genesis account
+			entry.Address, entry.Role, entry.Name = "", RoleGenesis, RoleGenesis // Generate genesis account
+		}
+
+		genesis.Alloc = append(genesis.Alloc, entry) // Append entry
+	}
+
+	return genesis, genesis.Validate() // Return genesis
+}
+
+// ParseAllocFlags parses a set of alloc flag values, each in the form address=amount.
+func ParseAllocFlags(values []string) (Alloc, error) {
+	var alloc Alloc               // Init alloc buffer
+	var problems ValidationErrors // Init problems buffer
+
+	for i, value := range values { // Iterate through values
+		path := fmt.Sprintf("--alloc[%d]", i) // Get path
+
+		split := strings.SplitN(value, "=", 2) // Split address, amount
+
+		if len(split) != 2 { // Check invalid format
+			problems = append(problems, &ValidationError{Path: path, Message: ErrInvalidAllocFlag.Error()}) // Append problem
+
+			continue // Continue
+		}
+
+		alloc = append(alloc, &AllocEntry{Address: split[0], Balance: split[1]}) // Append entry
+	}
+
+	problems = append(problems, alloc.validate("--alloc", false)...) // Validate alloc
+
+	if len(problems) > 0 { // Check has problems
+		return nil, problems // Return problems
+	}
+
+	return alloc, nil // No error occurred, return alloc
+}
+
+// Merge returns a copy of the alloc with the given entries applied on top of it.
+// Entries for addresses already in the alloc only replace the existing balance, keeping its role, name, and vesting schedule; all others are appended.
+func (alloc Alloc) Merge(entries Alloc) Alloc {
+	merged := append(Alloc{}, alloc...) // Copy alloc
+
+	for _, entry := range entries { // Iterate through entries
+		replaced := false // Init replaced buffer
+
+		for i, existing := range merged { // Iterate through existing entries
+			if strings.EqualFold(existing.Key(), entry.Key()) { // Check same entry
+				updated := *existing            // Copy existing entry
+				updated.Balance = entry.Balance // Set balance
+
+				merged[i] = &updated // Replace entry
+				replaced = true      // Set replaced
+
+				break // Break
+			}
+		}
+
+		if !replaced { // Check not replaced
+			merged = append(merged, entry) // Append entry
+		}
+	}
+
+	return merged // Return merged alloc
+}
+
+// Balances converts the alloc to the balances map and ordered address list used by a chain config.
+func (alloc Alloc) Balances() (map[string]*big.Float, []summercashCommon.Address, error) {
+	balances := make(map[string]*big.Float) // Init balances map
+
+	addresses := []summercashCommon.Address{} // Init address buffer
+
+	for _, entry := range alloc { // Iterate through entries
+		if entry.Address == "" { // Check not generated
+			return nil, []summercashCommon.Address{}, fmt.Errorf("%s: %s", ErrUngeneratedEntry, entry.Key()) // Return error
+		}
+
+		address, err := summercashCommon.StringToAddress(entry.Address) // Parse address
+
+		if err != nil { // Check for errors
+			return nil, []summercashCommon.Address{}, err // Return found error
+		}
+
+		balance, _, err := big.ParseFloat(entry.Balance, 10, 350, big.ToNearestEven) // Parse balance
+
+		if err != nil { // Check for errors
+			return nil, []summercashCommon.Address{}, err // Return found error
+		}
+
+		balances[address.String()] = balance   // Set balance
+		addresses = append(addresses, address) // Append address
+	}
+
+	return balances, addresses, nil // No error occurred, return balances
+}
+
+// MarshalJSON marshals an alloc to a JSON object, preserving the order of its entries.
+func (alloc Alloc) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString("{") // Init buffer
+
+	for i, entry := range alloc { // Iterate through entries
+		if i > 0 { // Check not first
+			buffer.WriteString(",") // Write separator
+		}
+
+		key, err := json.Marshal(entry.Key()) // Marshal key
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		marshaled := *entry // Copy entry
+
+		if entry.Address == "" { // Check keyed by name
+			marshaled.Name = "" // Name is implied by key
+		}
+
+		value, err := json.Marshal(marshaled) // Marshal entry
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		buffer.Write(key)       // Write key
+		buffer.WriteString(":") // Write separator
+		buffer.Write(value)     // Write value
+	}
+
+	buffer.WriteString("}") // Close object
+
+	return buffer.Bytes(), nil // Return marshaled
+}
+
+// Key gets the key of an alloc entry: its address, or its name if its account hasn't been generated yet.
+func (entry *AllocEntry) Key() string {
+	if entry.Address == "" { // Check not generated
+		return entry.Name // Return name
+	}
+
+	return entry.Address // Return address
+}
+
+// EntryRole gets the role of the alloc entry at a given index, defaulting to the genesis role for the first entry.
+func (alloc Alloc) EntryRole(i int) string {
+	if i == 0 && alloc[i].Role == "" { // Check implicit genesis
+		return RoleGenesis // Return genesis role
+	}
+
+	return alloc[i].Role // Return role
+}
+
+// IsValidName checks whether a given alloc entry name is valid, and can therefore be used in file names.
+func IsValidName(name string) bool {
+	return namePattern.MatchString(name) // Return is valid
+}
+
+// Error joins every validation problem into a single message.
+func (problems ValidationErrors) Error() string {
+	messages := make([]string, len(problems)) // Init messages buffer
+
+	for i, problem := range problems { // Iterate through problems
+		messages[i] = problem.Error() // Set message
+	}
+
+	return fmt.Sprintf("invalid genesis file:\n  %s", strings.Join(messages, "\n  ")) // Return joined
+}
+
+// Error formats a validation problem as path: message.
+func (problem *ValidationError) Error() string {
+	if problem.Path == "" { // Check no path
+		return problem.Message // Return message
+	}
+
+	return fmt.Sprintf("%s: %s", problem.Path, problem.Message) // Return formatted
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// validate returns every problem found in a genesis.
+func (genesis *Genesis) validate() ValidationErrors {
+	var problems ValidationErrors // Init problems buffer
+
+	if genesis.Version == 0 || genesis.Version > CurrentVersion { // Check unsupported version
+		problems = append(problems, &ValidationError{Path: "version", Message: fmt.Sprintf("unsupported version %d; expected at most %d", genesis.Version, CurrentVersion)}) // Append problem
+	}
+
+	if genesis.ChainVersion != nil && genesis.Version < 2 { // Check chain version unsupported by version
+		problems = append(problems, &ValidationError{Path: "chainVersion", Message: "requires version 2 or later"}) // Append problem
+	} else if genesis.ChainVersion != nil && *genesis.ChainVersion == "" { // Check empty chain version
+		problems = append(problems, &ValidationError{Path: "chainVersion", Message: "must not be empty"}) // Append problem
+	}
+
+	if genesis.Inflation != nil && *genesis.Inflation < 0 { // Check negative inflation
+		problems = append(problems, &ValidationError{Path: "inflation", Message: "must not be negative"}) // Append problem
+	}
+
+	if genesis.Alloc != nil { // Check has alloc
+		problems = append(problems, genesis.Alloc.validate("alloc", true)...) // Validate alloc
+	}
+
+	for _, entry := range genesis.Alloc { // Iterate through entries
+		if genesis.Version < 3 && (entry.Address == "" || entry.Role != "" || entry.Name != "") { // Check roles unsupported by version
+			problems = append(problems, &ValidationError{Path: childPath("alloc", entry.Key()), Message: "roles, names, and generated entries require version 3 or later"}) // Append problem
+		}
+
+		if genesis.Version < 4 && entry.Vesting != nil { // Check vesting unsupported by version
+			problems = append(problems, &ValidationError{Path: childPath("alloc", entry.Key()) + ".vesting", Message: "requires version 4 or later"}) // Append problem
+		}
+	}
+
+	return problems // Return problems
+}
+
+// validate returns every problem found in an alloc. If requireSupply is set, the first entry must hold a
+// non-zero supply large enough to cover every other entry.
+func (alloc Alloc) validate(path string, requireSupply bool) ValidationErrors {
+	var problems ValidationErrors // Init problems buffer
+
+	seen := make(map[string]bool) // Init seen addresses buffer
+
+	names := make(map[string]bool) // Init seen names buffer
+
+	allocated := new(big.Float) // Init allocated buffer
+
+	for i, entry := range alloc { // Iterate through entries
+		entryPath := fmt.Sprintf("%s[%q]", path, entry.Key()) // Get entry path
+
+		if strings.HasPrefix(path, "--") { // Check flag
+			entryPath = fmt.Sprintf("%s[%d]", path, i) // Use flag index
+		}
+
+		if entry.Address == "" { // Check generated
+			if validateAddress("0x"+entry.Name) == nil { // Check address missing prefix
+				problems = append(problems, &ValidationError{Path: entryPath, Message: fmt.Sprintf("address %q must begin with 0x", entry.Name)}) // Append problem
+			} else if !namePattern.MatchString(entry.Name) { // Check invalid name
+				problems = append(problems, &ValidationError{Path: entryPath, Message: fmt.Sprintf("%q is neither an address nor a valid name (letters, digits, '-', and '_')", entry.Name)}) // Append problem
+			}
+		} else if err := validateAddress(entry.Address); err != nil { // Check invalid address
+			problems = append(problems, &ValidationError{Path: entryPath, Message: err.Error()}) // Append problem
+		} else if normalized := strings.ToLower(entry.Address); seen[normalized] { // Check duplicate
+			problems = append(problems, &ValidationError{Path: entryPath, Message: "duplicate alloc entry"}) // Append problem
+		} else {
+			seen[normalized] = true // Set seen
+		}
+
+		if entry.Address != "" && entry.Name != "" && !namePattern.MatchString(entry.Name) { // Check invalid name
+			problems = append(problems, &ValidationError{Path: entryPath + ".name", Message: fmt.Sprintf("%q may only contain letters, digits, '-', and '_'", entry.Name)}) // Append problem
+		}
+
+		if entry.Name != "" && names[entry.Name] { // Check duplicate name
+			problems = append(problems, &ValidationError{Path: entryPath + ".name", Message: fmt.Sprintf("duplicate name %q", entry.Name)}) // Append problem
+		} else if entry.Name != "" {
+			names[entry.Name] = true // Set seen
+		}
+
+		role := entry.Role // Get role
+
+		if requireSupply { // Check first entry is genesis
+			role = alloc.EntryRole(i) // Get role, defaulting to genesis
+		}
+
+		switch {
+		case role != "" && !isRole(role):
+			problems = append(problems, &ValidationError{Path: entryPath + ".role", Message: fmt.Sprintf("unknown role %q; expected one of %s", role, strings.Join(Roles, ", "))}) // Append problem
+		case requireSupply && i == 0 && role != RoleGenesis:
+			problems = append(problems, &ValidationError{Path: entryPath + ".role", Message: "the first alloc entry is the genesis account"}) // Append problem
+		case (!requireSupply || i > 0) && role == RoleGenesis:
+			problems = append(problems, &ValidationError{Path: entryPath + ".role", Message: "only the first alloc entry may be the genesis account"}) // Append problem
+		case role == RoleFaucet && entry.Name == "":
+			problems = append(problems, &ValidationError{Path: entryPath + ".name", Message: "faucets must have a name"}) // Append problem
+		}
+
+		balance, ok := new(big.Float).SetPrec(350).SetString(entry.Balance) // Parse balance
+
+		switch {
+		case entry.Balance == "":
+			problems = append(problems, &ValidationError{Path: entryPath + ".balance", Message: "missing balance"}) // Append problem
+		case !ok:
+			problems = append(problems, &ValidationError{Path: entryPath + ".balance", Message: fmt.Sprintf("%q is not a number", entry.Balance)}) // Append problem
+		case balance.Sign() < 0:
+			problems = append(problems, &ValidationError{Path: entryPath + ".balance", Message: "must not be negative"}) // Append problem
+		case requireSupply && i == 0 && balance.Sign() == 0:
+			problems = append(problems, &ValidationError{Path: entryPath + ".balance", Message: "genesis supply must not be zero"}) // Append problem
+		case i > 0:
+			allocated.Add(allocated, balance) // Add to allocated
+		}
+
+		if entry.Vesting != nil && requireSupply && i == 0 { // Check genesis vests
+			problems = append(problems, &ValidationError{Path: entryPath + ".vesting", Message: "the genesis account cannot vest"}) // Append problem
+		} else if entry.Vesting != nil { // Check has vesting schedule
+			if !ok || balance.Sign() < 0 { // Check invalid balance
+				balance = nil // Skip balance checks
+			}
+
+			for _, problem := range entry.Vesting.Validate(balance) { // Iterate through schedule problems
+				problems = append(problems, &ValidationError{Path: strings.TrimSuffix(entryPath+".vesting."+problem.Field, "."), Message: problem.Message}) // Append problem
+			}
+		}
+	}
+
+	if requireSupply && len(alloc) == 0 { // Check no supply
+		problems = append(problems, &ValidationError{Path: path, Message: "genesis supply must not be zero"}) // Append problem
+	} else if requireSupply { // Check must cover allocations
+		supply, ok := new(big.Float).SetPrec(350).SetString(alloc[0].Balance) // Parse supply
+
+		if ok && supply.Sign() > 0 && allocated.Cmp(supply) > 0 { // Check allocations exceed supply
+			problems = append(problems, &ValidationError{Path: path, Message: fmt.Sprintf("allocations (%s) exceed genesis supply (%s)", allocated.Text('f', -1), supply.Text('f', -1))}) // Append problem
+		}
+	}
+
+	return problems // Return problems
+}
+
+// isRole checks whether a given string is a known alloc entry role.
+func isRole(role string) bool {
+	for _, known := range Roles { // Iterate through roles
+		if role == known { // Check matches
+			return true // Known role
+		}
+	}
+
+	return false // Unknown role
+}
+
+// validateAddress checks that a given string is a valid hex-encoded SummerCash address.
+func validateAddress(address string) error {
+	if !strings.HasPrefix(address, "0x") { // Check no prefix
+		return fmt.Errorf("address %q must begin with 0x", address) // Return error
+	}
+
+	decoded, err := hex.DecodeString(address[2:]) // Decode address
+
+	if err != nil { // Check for errors
+		return fmt.Errorf("address %q is not valid hex", address) // Return error
+	}
+
+	if len(decoded) != summercashCommon.AddressLength-2 { // Check invalid length
+		return fmt.Errorf("address %q must be %d bytes long", address, summercashCommon.AddressLength-2) // Return error
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// decodeAlloc decodes a JSON alloc object, preserving the order of its entries.
+func decodeAlloc(b []byte) (Alloc, ValidationErrors) {
+	var problems ValidationErrors // Init problems buffer
+
+	decoder := json.NewDecoder(bytes.NewReader(b)) // Init decoder
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') { // Check not object
+		return nil, ValidationErrors{{Path: "alloc", Message: "must be an object mapping addresses to balances"}} // Return problem
+	}
+
+	alloc := Alloc{} // Init alloc buffer
+
+	for decoder.More() { // Iterate through entries
+		token, err := decoder.Token() // Read key
+
+		if err != nil { // Check for errors
+			return alloc, append(problems, decodeError("alloc", err)) // Return problem
+		}
+
+		key := token.(string) // Get key
+
+		path := fmt.Sprintf("alloc[%q]", key) // Get entry path
+
+		var value json.RawMessage // Init value buffer
+
+		err = decoder.Decode(&value) // Decode value
+
+		if err != nil { // Check for errors
+			return alloc, append(problems, decodeError(path, err)) // Return problem
+		}
+
+		var rawEntry struct {
+			Role    string          `json:"role"`    // Role
+			Name    string          `json:"name"`    // Name
+			Balance json.RawMessage `json:"balance"` // Balance
+			Vesting json.RawMessage `json:"vesting"` // Vesting schedule
+		} // Init raw entry buffer
+
+		err = decodeStrict(value, &rawEntry) // Decode entry
+
+		if err != nil { // Check for errors
+			problems = append(problems, decodeError(path, err)) // Append problem
+
+			continue // Continue
+		}
+
+		entry := &AllocEntry{Address: key, Role: rawEntry.Role, Name: rawEntry.Name} // Init entry
+
+		if !strings.HasPrefix(key, "0x") { // Check keyed by name
+			if rawEntry.Name != "" && rawEntry.Name != key { // Check conflicting name
+				problems = append(problems, &ValidationError{Path: path + ".name", Message: "must match the key of an entry keyed by name"}) // Append problem
+			}
+
+			entry.Address, entry.Name = "", key // Mark generated
+		}
+
+		if len(rawEntry.Balance) > 0 && rawEntry.Balance[0] == '"' { // Check string balance
+			err = json.Unmarshal(rawEntry.Balance, &entry.Balance) // Unquote balance
+		} else if len(rawEntry.Balance) > 0 && string(rawEntry.Balance) != "null" { // Check numeric balance
+			var number json.Number // Init number buffer
+
+			err = json.Unmarshal(rawEntry.Balance, &number) // Decode number
+
+			entry.Balance = number.String() // Set balance
+		}
+
+		if err != nil { // Check for errors
+			problems = append(problems, &ValidationError{Path: path + ".balance", Message: "expected a string or number"}) // Append problem
+
+			continue // Continue
+		}
+
+		if len(rawEntry.Vesting) > 0 && string(rawEntry.Vesting) != "null" { // Check has vesting schedule
+			entry.Vesting = &vesting.Schedule{} // Init schedule
+
+			err = decodeStrict(rawEntry.Vesting, entry.Vesting) // Decode schedule
+
+			if err != nil { // Check for errors
+				problems = append(problems, decodeError(path+".vesting", err)) // Append problem
+
+				continue // Continue
+			}
+		}
+
+		alloc = append(alloc, entry) // Append entry
+	}
+
+	return alloc, problems // Return alloc
+}
+
+// decodeStrict decodes JSON into a given value, rejecting unknown keys.
+func decodeStrict(b []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(b)) // Init decoder
+
+	decoder.DisallowUnknownFields() // Reject unknown keys
+
+	return decoder.Decode(v) // Decode
+}
+
+// decodeError converts a JSON decoding error into a validation error rooted at a given path.
+func decodeError(path string, err error) *ValidationError {
+	join := func(field string) string {
+		if path == "" { // Check at root
+			return field // Return field
+		}
+
+		return path + "." + field // Return joined
+	}
+
+	switch err := err.(type) {
+	case *json.UnmarshalTypeError:
+		return &ValidationError{Path: join(err.Field), Message: fmt.Sprintf("expected %s, found %s", err.Type.String(), err.Value)} // Return type error
+	case *json.SyntaxError:
+		return &ValidationError{Path: path, Message: fmt.Sprintf("%s (at byte %d)", err.Error(), err.Offset)} // Return syntax error
+	}
+
+	if strings.HasPrefix(err.Error(), "json: unknown field ") { // Check unknown field
+		return &ValidationError{Path: join(strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)), Message: "unknown key"} // Return unknown field error
+	}
+
+	return &ValidationError{Path: path, Message: err.Error()} // Return error
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,698 @@
+// Package genesis defines the puppet genesis file format, along with helper methods for decoding and validating genesis files.
+package genesis
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format represents a genesis file encoding.
+type Format string
+
+// Comment represents the comments attached to a single value in a genesis file.
+type Comment struct {
+	Head string // Comment lines preceding the value
+	Line string // Comment trailing the value on the same line
+	Foot string // Comment lines following the value
+}
+
+// Comments maps the path of each value in a genesis file to its comments. The comments of the
+// file itself are stored under the empty path.
+type Comments map[string]*Comment
+
+const (
+	// FormatJSON is the JSON genesis file format.
+	FormatJSON Format = "json"
+
+	// FormatYAML is the YAML genesis file format.
+	FormatYAML Format = "yaml"
+
+	// FormatTOML is the TOML genesis file format.
+	FormatTOML Format = "toml"
+)
+
+var (
+	// ErrUnknownFormat is an error definition describing an unsupported genesis file format.
+	ErrUnknownFormat = errors.New("unknown genesis format; expected json, yaml, or toml")
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// ParseFormat parses a genesis file format name.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "json", "":
+		return FormatJSON, nil // Return JSON
+	case "yaml", "yml":
+		return FormatYAML, nil // Return YAML
+	case "toml":
+		return FormatTOML, nil // Return TOML
+	}
+
+	return "", fmt.Errorf("%s: %s", ErrUnknownFormat, name) // Return unknown format
+}
+
+// FormatFromPath detects the format of a genesis file from its extension.
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(filepath.Ext(path)) // Parse extension
+}
+
+// DecodeFormat strictly decodes a genesis file in a given format, and validates the result.
+// Comments in YAML and TOML genesis files are preserved in the genesis' Comments field.
+func DecodeFormat(b []byte, format Format) (*Genesis, error) {
+	var rawJSON []byte    // Init JSON buffer
+	var comments Comments // Init comments buffer
+	var err error         // Init error buffer
+
+	switch format {
+	case FormatJSON:
+		return Decode(b) // Decode JSON
+	case FormatYAML:
+		rawJSON, comments, err = yamlToJSON(b) // Convert YAML
+	case FormatTOML:
+		rawJSON, comments, err = tomlToJSON(b) // Convert TOML
+	default:
+		return nil, ErrUnknownFormat // Return unknown format
+	}
+
+	if err != nil { // Check for errors
+		return nil, ValidationErrors{{Message: err.Error()}} // Return syntax error
+	}
+
+	genesis, err := Decode(rawJSON) // Decode converted genesis
+
+	if genesis != nil { // Check decoded
+		genesis.Comments = comments // Set comments
+	}
+
+	return genesis, err // Return genesis
+}
+
+// Encode encodes a genesis in a given format. Comments are written for YAML and TOML genesis files.
+func (genesis *Genesis) Encode(format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		encoded, err := json.MarshalIndent(genesis, "", "  ") // Marshal genesis
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return append(encoded, '\n'), nil // Return encoded
+	case FormatYAML:
+		return genesis.encodeYAML() // Encode YAML
+	case FormatTOML:
+		return genesis.encodeTOML(), nil // Encode TOML
+	}
+
+	return nil, ErrUnknownFormat // Return unknown format
+}
+
+// MoveAllocEntry moves the comments attached to the alloc entry with a given key (and to its values) to another key,
+// e.g. once the account of an entry keyed by name has been generated.
+func (comments Comments) MoveAllocEntry(from string, to string) {
+	fromPath, toPath := childPath("alloc", from), childPath("alloc", to) // Get entry paths
+
+	for path, comment := range comments { // Iterate through comments
+		if path == fromPath || strings.HasPrefix(path, fromPath+".") { // Check attached to entry
+			delete(comments, path) // Remove comment
+
+			comments[toPath+strings.TrimPrefix(path, fromPath)] = comment // Move comment
+		}
+	}
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// comment gets the comment at a given path, creating one if it doesn't exist.
+func (comments Comments) comment(path string) *Comment {
+	if comments[path] == nil { // Check no comment
+		comments[path] = &Comment{} // Init comment
+	}
+
+	return comments[path] // Return comment
+}
+
+// get gets the comment at a given path, returning an empty comment if it doesn't exist.
+func (comments Comments) get(path string) *Comment {
+	if comment := comments[path]; comment != nil { // Check has comment
+		return comment // Return comment
+	}
+
+	return &Comment{} // Return empty comment
+}
+
+// childPath gets the path of the value at a given key within the value at a parent path.
+func childPath(parent string, key string) string {
+	switch parent {
+	case "":
+		return key // Return root key
+	case "alloc":
+		return fmt.Sprintf("alloc[%q]", strings.ToLower(key)) // Return alloc entry key
+	}
+
+	return parent + "." + key // Return nested key
+}
+
+// yamlToJSON converts a YAML document to JSON, preserving key order, and collects its comments.
+func yamlToJSON(b []byte) ([]byte, Comments, error) {
+	var document yaml.Node // Init document buffer
+
+	err := yaml.Unmarshal(b, &document) // Parse document
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	comments := make(Comments) // Init comments buffer
+
+	if len(document.Content) == 0 { // Check empty document
+		return []byte("{}"), comments, nil // Return empty genesis
+	}
+
+	root := document.Content[0] // Get root node
+
+	comments.comment("").Head = stripComment(document.HeadComment + "\n" + root.HeadComment) // Set file head comment
+	comments.comment("").Foot = stripComment(root.FootComment + "\n" + document.FootComment) // Set file foot comment
+
+	buffer := new(bytes.Buffer) // Init buffer
+
+	err = writeYAMLNode(buffer, root, "", comments) // Convert root
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	return buffer.Bytes(), comments, nil // Return converted
+}
+
+// writeYAMLNode writes a YAML node at a given path to a buffer as JSON.
+func writeYAMLNode(buffer *bytes.Buffer, node *yaml.Node, path string, comments Comments) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return writeYAMLNode(buffer, node.Alias, path, comments) // Write aliased node
+	case yaml.MappingNode:
+		buffer.WriteString("{") // Open object
+
+		for i := 0; i+1 < len(node.Content); i += 2 { // Iterate through key-value pairs
+			key, value := node.Content[i], node.Content[i+1] // Get key, value
+
+			if i > 0 { // Check not first
+				buffer.WriteString(",") // Write separator
+			}
+
+			encodedKey, _ := json.Marshal(key.Value) // Marshal key
+
+			buffer.Write(encodedKey) // Write key
+			buffer.WriteString(":")  // Write separator
+
+			valuePath := childPath(path, key.Value) // Get value path
+
+			comment := comments.comment(valuePath) // Get value comment
+
+			comment.Head = stripComment(key.HeadComment)                            // Set head comment
+			comment.Line = stripComment(key.LineComment + "\n" + value.LineComment) // Set line comment
+			comment.Foot = stripComment(key.FootComment + "\n" + value.FootComment) // Set foot comment
+
+			err := writeYAMLNode(buffer, value, valuePath, comments) // Write value
+
+			if err != nil { // Check for errors
+				return err // Return found error
+			}
+		}
+
+		buffer.WriteString("}") // Close object
+
+		return nil // No error occurred, return nil
+	}
+
+	var value interface{} // Init value buffer
+
+	err := node.Decode(&value) // Decode value
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	encoded, err := json.Marshal(value) // Marshal value
+
+	if err != nil { // Check for errors
+		return fmt.Errorf("%s: %s", path, err) // Return found error
+	}
+
+	buffer.Write(encoded) // Write value
+
+	return nil // No error occurred, return nil
+}
+
+// tomlToJSON converts a TOML document to JSON, preserving key order, and collects its comments.
+func tomlToJSON(b []byte) ([]byte, Comments, error) {
+	var values map[string]interface{} // Init values buffer
+
+	metadata, err := toml.Decode(string(b), &values) // Parse document
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	order := make(map[string][]string) // Init key order buffer
+
+	for _, key := range metadata.Keys() { // Iterate through keys in order of appearance
+		parent := strings.Join(key[:len(key)-1], "\x00") // Get parent key
+
+		order[parent] = append(order[parent], key[len(key)-1]) // Append key
+	}
+
+	buffer := new(bytes.Buffer) // Init buffer
+
+	err = writeTOMLValue(buffer, values, nil, order) // Convert root
+
+	if err != nil { // Check for errors
+		return nil, nil, err // Return found error
+	}
+
+	return buffer.Bytes(), tomlComments(b), nil // Return converted
+}
+
+// writeTOMLValue writes a decoded TOML value at a given key to a buffer as JSON.
+func writeTOMLValue(buffer *bytes.Buffer, value interface{}, key []string, order map[string][]string) error {
+	table, ok := value.(map[string]interface{}) // Get table
+
+	if !ok { // Check not table
+		encoded, err := json.Marshal(value) // Marshal value
+
+		if err != nil { // Check for errors
+			return fmt.Errorf("%s: %s", strings.Join(key, "."), err) // Return found error
+		}
+
+		buffer.Write(encoded) // Write value
+
+		return nil // No error occurred, return nil
+	}
+
+	keys := order[strings.Join(key, "\x00")] // Get ordered keys
+
+	if len(keys) != len(table) { // Check order unknown (e.g. inline table)
+		keys = keys[:0] // Reset keys
+
+		for tableKey := range table { // Iterate through table keys
+			keys = append(keys, tableKey) // Append key
+		}
+
+		sort.Strings(keys) // Sort keys
+	}
+
+	buffer.WriteString("{") // Open object
+
+	for i, tableKey := range keys { // Iterate through keys
+		if i > 0 { // Check not first
+			buffer.WriteString(",") // Write separator
+		}
+
+		encodedKey, _ := json.Marshal(tableKey) // Marshal key
+
+		buffer.Write(encodedKey) // Write key
+		buffer.WriteString(":")  // Write separator
+
+		err := writeTOMLValue(buffer, table[tableKey], append(append([]string{}, key...), tableKey), order) // Write value
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+	}
+
+	buffer.WriteString("}") // Close object
+
+	return nil // No error occurred, return nil
+}
+
+// tomlComments collects the comments in a TOML document. Comment lines are attached to the
+// table or key that follows them, and trailing comments to the table or key on the same line.
+func tomlComments(b []byte) Comments {
+	comments := make(Comments) // Init comments buffer
+
+	var pending []string // Init pending comment lines buffer
+	var table []string   // Init current table buffer
+	var seenValue bool   // Init seen value buffer
+
+	scanner := bufio.NewScanner(bytes.NewReader(b)) // Init scanner
+
+	for scanner.Scan() { // Iterate through lines
+		line := strings.TrimSpace(scanner.Text()) // Get line
+
+		switch {
+		case strings.HasPrefix(line, "#"):
+			pending = append(pending, strings.TrimPrefix(strings.TrimPrefix(line, "#"), " ")) // Append comment line
+		case line == "":
+			if !seenValue && len(pending) > 0 { // Check file head comment
+				comments.comment("").Head = strings.Join(pending, "\n") // Set file head comment
+				pending = nil                                           // Reset pending
+			}
+		default:
+			content, trailing := splitTOMLComment(line) // Split trailing comment
+
+			var key []string // Init key buffer
+
+			if strings.HasPrefix(content, "[") { // Check table header
+				table = splitTOMLKey(strings.Trim(content, "[] ")) // Set table
+				key = table                                        // Set key
+			} else {
+				key = append(append([]string{}, table...), splitTOMLKey(strings.SplitN(content, "=", 2)[0])...) // Set key
+			}
+
+			path := "" // Init path buffer
+
+			for _, part := range key { // Iterate through key parts
+				path = childPath(path, part) // Append part
+			}
+
+			comment := comments.comment(path) // Get comment
+
+			if len(pending) > 0 { // Check has pending comment lines
+				comment.Head = strings.Join(pending, "\n") // Set head comment
+			}
+
+			comment.Line = trailing // Set line comment
+
+			pending = nil    // Reset pending
+			seenValue = true // Set seen value
+		}
+	}
+
+	if len(pending) > 0 { // Check trailing comment lines
+		comments.comment("").Foot = strings.Join(pending, "\n") // Set file foot comment
+	}
+
+	return comments // Return comments
+}
+
+// splitTOMLComment splits a TOML line into its content and trailing comment.
+func splitTOMLComment(line string) (string, string) {
+	inString := false // Init in string buffer
+
+	for i := 0; i < len(line); i++ { // Iterate through characters
+		switch {
+		case line[i] == '\\' && inString:
+			i++ // Skip escaped character
+		case line[i] == '"' || line[i] == '\'':
+			inString = !inString // Toggle in string
+		case line[i] == '#' && !inString:
+			return strings.TrimSpace(line[:i]), strings.TrimPrefix(strings.TrimPrefix(line[i:], "#"), " ") // Return split
+		}
+	}
+
+	return line, "" // Return line
+}
+
+// splitTOMLKey splits a dotted TOML key into its parts.
+func splitTOMLKey(key string) []string {
+	var parts []string // Init parts buffer
+
+	for _, part := range strings.Split(strings.TrimSpace(key), ".") { // Iterate through parts
+		parts = append(parts, strings.Trim(strings.TrimSpace(part), `"'`)) // Append unquoted part
+	}
+
+	return parts // Return parts
+}
+
+// stripComment removes the comment markers from a YAML comment.
+func stripComment(comment string) string {
+	var lines []string // Init lines buffer
+
+	for _, line := range strings.Split(comment, "\n") { // Iterate through lines
+		if line = strings.TrimSpace(line); line != "" { // Check not empty
+			lines = append(lines, strings.TrimPrefix(strings.TrimPrefix(line, "#"), " ")) // Append stripped line
+		}
+	}
+
+	return strings.Join(lines, "\n") // Return stripped
+}
+
+// formatComment prefixes each line of a comment with a comment marker.
+func formatComment(comment string) string {
+	if comment == "" { // Check no comment
+		return "" // Return empty
+	}
+
+	return "# " + strings.Replace(comment, "\n", "\n# ", -1) // Return formatted
+}
+
+// encodeYAML encodes a genesis as a YAML document, along with its comments.
+func (genesis *Genesis) encodeYAML() ([]byte, error) {
+	comments := genesis.Comments // Get comments
+
+	if comments == nil { // Check no comments
+		comments = make(Comments) // Init comments
+	}
+
+	root := &yaml.Node{Kind: yaml.MappingNode} // Init root
+
+	addPair := func(mapping *yaml.Node, key string, path string, value *yaml.Node) {
+		comment := comments.get(path) // Get comment
+
+		mapping.Content = append(mapping.Content, &yaml.Node{
+			Kind:        yaml.ScalarNode,             // Set kind
+			Value:       key,                         // Set key
+			HeadComment: formatComment(comment.Head), // Set head comment
+			FootComment: formatComment(comment.Foot), // Set foot comment
+		}, value) // Append pair
+
+		value.LineComment = formatComment(comment.Line) // Set line comment
+	}
+
+	addPair(root, "version", "version", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatUint(uint64(genesis.Version), 10)}) // Add version
+
+	if genesis.NetworkID != nil { // Check has network ID
+		addPair(root, "networkID", "networkID", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatUint(uint64(*genesis.NetworkID), 10)}) // Add network ID
+	}
+
+	if genesis.Inflation != nil { // Check has inflation
+		addPair(root, "inflation", "inflation", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: formatFloat(*genesis.Inflation)}) // Add inflation
+	}
+
+	if genesis.ChainVersion != nil { // Check has chain version
+		addPair(root, "chainVersion", "chainVersion", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: *genesis.ChainVersion}) // Add chain version
+	}
+
+	if genesis.Alloc != nil { // Check has alloc
+		alloc := &yaml.Node{Kind: yaml.MappingNode} // Init alloc
+
+		for _, entry := range genesis.Alloc { // Iterate through entries
+			entryPath := childPath("alloc", entry.Key()) // Get entry path
+
+			entryNode := &yaml.Node{Kind: yaml.MappingNode} // Init entry
+
+			if entry.Role != "" { // Check has role
+				addPair(entryNode, "role", entryPath+".role", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: entry.Role}) // Add role
+			}
+
+			if entry.Name != "" && entry.Address != "" { // Check has name not implied by key
+				addPair(entryNode, "name", entryPath+".name", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: entry.Name}) // Add name
+			}
+
+			addPair(entryNode, "balance", entryPath+".balance", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: entry.Balance}) // Add balance
+
+			if schedule := entry.Vesting; schedule != nil { // Check has vesting schedule
+				vestingPath := entryPath + ".vesting" // Get schedule path
+
+				vestingNode := &yaml.Node{Kind: yaml.MappingNode} // Init schedule
+
+				if schedule.Start != nil { // Check has start
+					addPair(vestingNode, "start", vestingPath+".start", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: schedule.Start.Format(time.RFC3339Nano)}) // Add start
+				}
+
+				if schedule.Cliff != 0 { // Check has cliff
+					addPair(vestingNode, "cliff", vestingPath+".cliff", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: schedule.Cliff.String()}) // Add cliff
+				}
+
+				if schedule.Duration != 0 { // Check has duration
+					addPair(vestingNode, "duration", vestingPath+".duration", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: schedule.Duration.String()}) // Add duration
+				}
+
+				if len(schedule.Unlocks) > 0 { // Check has unlocks
+					unlocks := &yaml.Node{Kind: yaml.SequenceNode} // Init unlocks
+
+					for _, unlock := range schedule.Unlocks { // Iterate through unlocks
+						unlocks.Content = append(unlocks.Content, &yaml.Node{
+							Kind: yaml.MappingNode, // Set kind
+							Content: []*yaml.Node{
+								{Kind: yaml.ScalarNode, Value: "at"}, // Set at key
+								{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: unlock.At.Format(time.RFC3339Nano)}, // Set at
+								{Kind: yaml.ScalarNode, Value: "amount"},                                                   // Set amount key
+								{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: unlock.Amount}, // Set amount
+							},
+						}) // Append unlock
+					}
+
+					addPair(vestingNode, "unlocks", vestingPath+".unlocks", unlocks) // Add unlocks
+				}
+
+				addPair(entryNode, "vesting", vestingPath, vestingNode) // Add schedule
+			}
+
+			addPair(alloc, entry.Key(), entryPath, entryNode) // Add entry
+
+			alloc.Content[len(alloc.Content)-2].Style = yaml.DoubleQuotedStyle // Quote address, so as not to be read as a hex integer
+		}
+
+		addPair(root, "alloc", "alloc", alloc) // Add alloc
+	}
+
+	document := &yaml.Node{
+		Kind:        yaml.DocumentNode,                    // Set kind
+		Content:     []*yaml.Node{root},                   // Set content
+		HeadComment: formatComment(comments.get("").Head), // Set head comment
+		FootComment: formatComment(comments.get("").Foot), // Set foot comment
+	} // Init document
+
+	buffer := new(bytes.Buffer) // Init buffer
+
+	encoder := yaml.NewEncoder(buffer) // Init encoder
+
+	encoder.SetIndent(2) // Set indent
+
+	err := encoder.Encode(document) // Encode document
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return buffer.Bytes(), encoder.Close() // Return encoded
+}
+
+// encodeTOML encodes a genesis as a TOML document, along with its comments.
+func (genesis *Genesis) encodeTOML() []byte {
+	comments := genesis.Comments // Get comments
+
+	if comments == nil { // Check no comments
+		comments = make(Comments) // Init comments
+	}
+
+	buffer := new(bytes.Buffer) // Init buffer
+
+	writeLine := func(path string, line string) {
+		comment := comments.get(path) // Get comment
+
+		if comment.Head != "" { // Check has head comment
+			fmt.Fprintln(buffer, formatComment(comment.Head)) // Write head comment
+		}
+
+		if comment.Line != "" { // Check has line comment
+			line += " " + formatComment(comment.Line) // Append line comment
+		}
+
+		fmt.Fprintln(buffer, line) // Write line
+
+		if comment.Foot != "" { // Check has foot comment
+			fmt.Fprintln(buffer, formatComment(comment.Foot)) // Write foot comment
+		}
+	}
+
+	if head := comments.get("").Head; head != "" { // Check has file head comment
+		fmt.Fprintf(buffer, "%s\n\n", formatComment(head)) // Write file head comment
+	}
+
+	writeLine("version", fmt.Sprintf("version = %d", genesis.Version)) // Write version
+
+	if genesis.NetworkID != nil { // Check has network ID
+		writeLine("networkID", fmt.Sprintf("networkID = %d", *genesis.NetworkID)) // Write network ID
+	}
+
+	if genesis.Inflation != nil { // Check has inflation
+		writeLine("inflation", fmt.Sprintf("inflation = %s", formatFloat(*genesis.Inflation))) // Write inflation
+	}
+
+	if genesis.ChainVersion != nil { // Check has chain version
+		writeLine("chainVersion", fmt.Sprintf("chainVersion = %q", *genesis.ChainVersion)) // Write chain version
+	}
+
+	if genesis.Alloc != nil { // Check has alloc
+		fmt.Fprintln(buffer) // Write separator
+
+		writeLine("alloc", "[alloc]") // Write alloc table
+
+		for _, entry := range genesis.Alloc { // Iterate through entries
+			entryPath := childPath("alloc", entry.Key()) // Get entry path
+
+			fmt.Fprintln(buffer) // Write separator
+
+			writeLine(entryPath, fmt.Sprintf("[alloc.%q]", entry.Key())) // Write entry table
+
+			if entry.Role != "" { // Check has role
+				writeLine(entryPath+".role", fmt.Sprintf("role = %q", entry.Role)) // Write role
+			}
+
+			if entry.Name != "" && entry.Address != "" { // Check has name not implied by key
+				writeLine(entryPath+".name", fmt.Sprintf("name = %q", entry.Name)) // Write name
+			}
+
+			writeLine(entryPath+".balance", fmt.Sprintf("balance = %q", entry.Balance)) // Write balance
+
+			if schedule := entry.Vesting; schedule != nil { // Check has vesting schedule
+				vestingPath := entryPath + ".vesting" // Get schedule path
+
+				fmt.Fprintln(buffer) // Write separator
+
+				writeLine(vestingPath, fmt.Sprintf("[alloc.%q.vesting]", entry.Key())) // Write schedule table
+
+				if schedule.Start != nil { // Check has start
+					writeLine(vestingPath+".start", fmt.Sprintf("start = %s", schedule.Start.Format(time.RFC3339Nano))) // Write start
+				}
+
+				if schedule.Cliff != 0 { // Check has cliff
+					writeLine(vestingPath+".cliff", fmt.Sprintf("cliff = %q", schedule.Cliff.String())) // Write cliff
+				}
+
+				if schedule.Duration != 0 { // Check has duration
+					writeLine(vestingPath+".duration", fmt.Sprintf("duration = %q", schedule.Duration.String())) // Write duration
+				}
+
+				for i, unlock := range schedule.Unlocks { // Iterate through unlocks
+					fmt.Fprintln(buffer) // Write separator
+
+					header := fmt.Sprintf("[[alloc.%q.vesting.unlocks]]", entry.Key()) // Get unlock table header
+
+					if i == 0 { // Check first unlock
+						writeLine(vestingPath+".unlocks", header) // Write unlock table, along with the comments of the unlocks
+					} else {
+						fmt.Fprintln(buffer, header) // Write unlock table
+					}
+
+					fmt.Fprintf(buffer, "at = %s\namount = %q\n", unlock.At.Format(time.RFC3339Nano), unlock.Amount) // Write unlock
+				}
+			}
+		}
+	}
+
+	if foot := comments.get("").Foot; foot != "" { // Check has file foot comment
+		fmt.Fprintf(buffer, "\n%s\n", formatComment(foot)) // Write file foot comment
+	}
+
+	return buffer.Bytes() // Return encoded
+}
+
+// formatFloat formats a float such that it is always read back as a float.
+func formatFloat(f float64) string {
+	formatted := strconv.FormatFloat(f, 'g', -1, 64) // Format float
+
+	if !strings.ContainsAny(formatted, ".eEn") { // Check would be read as integer
+		formatted += ".0" // Append fraction
+	}
+
+	return formatted // Return formatted
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,208 @@
+// Package genesis defines the puppet genesis file format, along with helper methods for decoding and validating genesis files.
+package genesis
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	// testYAMLGenesis is a commented YAML genesis file used in tests.
+	testYAMLGenesis = `# Staging network
+
+version: 1
+networkID: 3 # staging
+inflation: 0.1
+alloc:
+  # genesis account
+  "` + testGenesisAddress + `":
+    balance: 100
+  "` + testAllocAddress + `":
+    balance: "5"
+`
+
+	// testTOMLGenesis is a commented TOML genesis file used in tests.
+	testTOMLGenesis = `# Staging network
+
+version = 1
+networkID = 3 # staging
+inflation = 0.1
+
+# genesis account
+[alloc."` + testGenesisAddress + `"]
+balance = 100
+
+[alloc."` + testAllocAddress + `"]
+balance = "5"
+`
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestParseFormat tests the functionality of the ParseFormat() method.
+func TestParseFormat(t *testing.T) {
+	for name, expected := range map[string]Format{"json": FormatJSON, ".yml": FormatYAML, "YAML": FormatYAML, "toml": FormatTOML} { // Iterate through format names
+		format, err := ParseFormat(name) // Parse format
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		if format != expected { // Check invalid format
+			t.Fatalf("expected format %s for %s, found %s", expected, name, format) // Panic
+		}
+	}
+
+	if _, err := FormatFromPath("genesis.xml"); err == nil { // Check unknown format accepted
+		t.Fatal("unknown formats must be rejected") // Panic
+	}
+}
+
+// TestDecodeFormat tests the functionality of the DecodeFormat() method.
+func TestDecodeFormat(t *testing.T) {
+	for format, source := range map[Format]string{FormatYAML: testYAMLGenesis, FormatTOML: testTOMLGenesis} { // Iterate through formats
+		genesis, err := DecodeFormat([]byte(source), format) // Decode genesis
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		if *genesis.NetworkID != 3 || *genesis.Inflation != 0.1 || len(genesis.Alloc) != 2 || genesis.Alloc[0].Address != testGenesisAddress || genesis.Alloc[0].Balance != "100" { // Check invalid values
+			t.Fatalf("%s genesis decoded incorrectly", format) // Panic
+		}
+
+		if genesis.Comments.get("").Head != "Staging network" || genesis.Comments.get("networkID").Line != "staging" || genesis.Comments.get(`alloc["`+testGenesisAddress+`"]`).Head != "genesis account" { // Check comments not preserved
+			t.Fatalf("%s genesis comments not preserved", format) // Panic
+		}
+	}
+}
+
+// TestDecodeFormatUnknownKey tests that the DecodeFormat() method rejects unknown keys in YAML and TOML genesis files.
+func TestDecodeFormatUnknownKey(t *testing.T) {
+	_, err := DecodeFormat([]byte("version: 1\nsupply: 100\n"), FormatYAML) // Decode genesis
+
+	if err == nil || !strings.Contains(err.Error(), "supply: unknown key") { // Check not rejected
+		t.Fatalf("expected unknown key error, found %v", err) // Panic
+	}
+
+	_, err = DecodeFormat([]byte("version = 1\nsupply = 100\n"), FormatTOML) // Decode genesis
+
+	if err == nil || !strings.Contains(err.Error(), "supply: unknown key") { // Check not rejected
+		t.Fatalf("expected unknown key error, found %v", err) // Panic
+	}
+}
+
+// TestEncode tests that genesis files survive a round trip through every format, along with their comments.
+func TestEncode(t *testing.T) {
+	genesis, err := DecodeFormat([]byte(testYAMLGenesis), FormatYAML) // Decode genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	for _, format := range []Format{FormatYAML, FormatTOML, FormatJSON} { // Iterate through formats
+		encoded, err := genesis.Encode(format) // Encode genesis
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		decoded, err := DecodeFormat(encoded, format) // Decode encoded genesis
+
+		if err != nil { // Check for errors
+			t.Fatalf("%s: %s\n%s", format, err, encoded) // Panic
+		}
+
+		if *decoded.NetworkID != *genesis.NetworkID || len(decoded.Alloc) != len(genesis.Alloc) || decoded.Alloc[1].Balance != genesis.Alloc[1].Balance { // Check values not preserved
+			t.Fatalf("%s genesis values not preserved", format) // Panic
+		}
+
+		if format != FormatJSON && (!strings.Contains(string(encoded), "# staging") || !strings.Contains(string(encoded), "# genesis account")) { // Check comments not preserved
+			t.Fatalf("%s genesis comments not preserved:\n%s", format, encoded) // Panic
+		}
+	}
+}
+
+// TestEncodeRoles tests that alloc entry roles and names survive a round trip through every format.
+func TestEncodeRoles(t *testing.T) {
+	genesis, err := Decode([]byte(`{"version": 3, "alloc": {"genesis": {"balance": "100"}, "dev": {"role": "faucet", "balance": "5"}, "` + testAllocAddress + `": {"role": "treasury", "name": "ops", "balance": "10"}}}`)) // Decode genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	for _, format := range []Format{FormatYAML, FormatTOML, FormatJSON} { // Iterate through formats
+		encoded, err := genesis.Encode(format) // Encode genesis
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		decoded, err := DecodeFormat(encoded, format) // Decode encoded genesis
+
+		if err != nil { // Check for errors
+			t.Fatalf("%s: %s\n%s", format, err, encoded) // Panic
+		}
+
+		if decoded.Alloc[1].Name != "dev" || decoded.Alloc[1].Role != RoleFaucet || decoded.Alloc[2].Name != "ops" || decoded.Alloc[2].Role != RoleTreasury { // Check roles not preserved
+			t.Fatalf("%s genesis roles not preserved:\n%s", format, encoded) // Panic
+		}
+	}
+}
+
+// TestEncodeVesting tests that alloc entry vesting schedules survive a round trip through every format.
+func TestEncodeVesting(t *testing.T) {
+	genesis, err := Decode([]byte(`{"version": 4, "alloc": {"genesis": {"balance": "100"}, "team": {"balance": "10", "vesting": {"start": "2026-01-01T00:00:00Z", "cliff": "90d", "duration": "8760h30m"}}, "advisors": {"balance": "5", "vesting": {"unlocks": [{"at": "2026-06-01T00:00:00Z", "amount": "2"}, {"at": "2027-06-01T00:00:00Z", "amount": "3"}]}}}}`)) // Decode genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	for _, format := range []Format{FormatYAML, FormatTOML, FormatJSON} { // Iterate through formats
+		encoded, err := genesis.Encode(format) // Encode genesis
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		decoded, err := DecodeFormat(encoded, format) // Decode encoded genesis
+
+		if err != nil { // Check for errors
+			t.Fatalf("%s: %s\n%s", format, err, encoded) // Panic
+		}
+
+		team, advisors := decoded.Alloc[1].Vesting, decoded.Alloc[2].Vesting // Get schedules
+
+		if team == nil || !team.Start.Equal(*genesis.Alloc[1].Vesting.Start) || team.Cliff != genesis.Alloc[1].Vesting.Cliff || team.Duration != genesis.Alloc[1].Vesting.Duration { // Check linear release not preserved
+			t.Fatalf("%s genesis linear release not preserved:\n%s", format, encoded) // Panic
+		}
+
+		if advisors == nil || len(advisors.Unlocks) != 2 || !advisors.Unlocks[1].At.Equal(genesis.Alloc[2].Vesting.Unlocks[1].At) || advisors.Unlocks[1].Amount != "3" { // Check unlocks not preserved
+			t.Fatalf("%s genesis unlocks not preserved:\n%s", format, encoded) // Panic
+		}
+	}
+}
+
+// TestEncodeUnlockComments tests that the comments of an alloc entry's unlocks are written once, rather than before
+// every unlock, when encoding TOML.
+func TestEncodeUnlockComments(t *testing.T) {
+	genesis, err := Decode([]byte(`{"version": 4, "alloc": {"genesis": {"balance": "100"}, "advisors": {"balance": "5", "vesting": {"unlocks": [{"at": "2026-06-01T00:00:00Z", "amount": "2"}, {"at": "2027-06-01T00:00:00Z", "amount": "3"}]}}}}`)) // Decode genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	genesis.Comments = Comments{`alloc["advisors"].vesting.unlocks`: {Head: "released yearly"}} // Set unlock comment
+
+	encoded, err := genesis.Encode(FormatTOML) // Encode genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if count := strings.Count(string(encoded), "released yearly"); count != 1 { // Check comment repeated
+		t.Fatalf("expected the unlock comment to be written once, found %d times:\n%s", count, encoded) // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
@@ -0,0 +1,273 @@
+// Package genesis defines the puppet genesis file format, along with helper methods for decoding and validating genesis files.
+package genesis
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/puppet/vesting"
+)
+
+// Balance represents the final balance of an account on an existing network, along with its recorded role and name.
+type Balance struct {
+	Address string     // Address
+	Role    string     // Role, if recorded
+	Name    string     // Name, if recorded
+	Balance *big.Float // Final balance
+
+	Lock *vesting.Lock // Vesting lock of the account's genesis allocation, if any
+}
+
+// Spinoff represents the changes made to the final balances of an existing network when a new network is spun off
+// from it.
+type Spinoff struct {
+	NetworkID uint // Network ID of the new network
+
+	Exclude []string          // Addresses whose balances are left out
+	Remap   map[string]string // Addresses whose balances are moved to another address, mapped to that address
+
+	ReplaceFaucets bool // Whether faucet balances are moved to newly generated faucet accounts
+
+	At time.Time // Time the new network is spun off at, from which vesting locks are carried over
+}
+
+var (
+	// ErrInvalidRemapFlag is an error definition describing a remap flag not in the form old=new.
+	ErrInvalidRemapFlag = errors.New("expected old=new")
+
+	// ErrNoBalance is an error definition describing an excluded or remapped address without a balance to exclude or remap.
+	ErrNoBalance = errors.New("address has no balance on the existing network")
+
+	// ErrNegativeBalance is an error definition describing an account whose final balance is negative.
+	ErrNegativeBalance = errors.New("account has a negative balance")
+
+	// ErrExcludedRemap is an error definition describing an excluded address that is also remapped, or remapped to.
+	ErrExcludedRemap = errors.New("address is both excluded and remapped")
+
+	// ErrLockedBalanceSpent is an error definition describing an account holding less than its locked balance.
+	ErrLockedBalanceSpent = errors.New("account holds less than its locked balance")
+
+	// ErrUncarriedLock is an error definition describing a vesting lock that can't be reproduced on the new network.
+	ErrUncarriedLock = errors.New("vesting lock can't be carried over to the new network")
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// FromBalances makes a genesis for a network spun off from an existing one, with the inflation rate and chain version
+// of the existing network's chain config. The genesis account of the new network is generated, and allocates the
+// final balance of every account on the existing network, such that those balances are reproduced once the network is
+// created. Accounts without a balance are left out, as are excluded ones, and remapped balances are allocated to the
+// address they're remapped to. Recorded roles and names are kept, except for the genesis role, which belongs to the
+// new genesis account, and the faucet role, as the wallet server can't send funds from an existing faucet's address.
+// If faucets are replaced, their balances are allocated to newly generated faucet accounts with the same names instead.
+// The part of each vesting lock still in effect at the spinoff's time is carried over: fixed unlocks that have passed
+// are dropped, and linear releases are kept as they are. Locks that can't be reproduced exactly are rejected: those of
+// accounts holding less than their locked balance, linear releases of accounts whose balance has changed, and locks on
+// balances merged with another account's.
+func FromBalances(chainConfig *config.ChainConfig, balances []*Balance, spinoff *Spinoff) (*Genesis, error) {
+	known := make(map[string]bool) // Init known addresses buffer
+
+	for _, balance := range balances { // Iterate through balances
+		known[strings.ToLower(balance.Address)] = true // Set known
+	}
+
+	excluded := make(map[string]bool) // Init excluded addresses buffer
+
+	for _, address := range spinoff.Exclude { // Iterate through excluded addresses
+		if !known[strings.ToLower(address)] { // Check no balance
+			return nil, fmt.Errorf("%s: %s", ErrNoBalance, address) // Return error
+		}
+
+		excluded[strings.ToLower(address)] = true // Set excluded
+	}
+
+	remap := make(map[string]string) // Init remap buffer
+
+	for from, to := range spinoff.Remap { // Iterate through remapped addresses
+		if !known[strings.ToLower(from)] { // Check no balance
+			return nil, fmt.Errorf("%s: %s", ErrNoBalance, from) // Return error
+		}
+
+		for _, address := range []string{from, to} { // Iterate through remapped addresses
+			if excluded[strings.ToLower(address)] { // Check excluded
+				return nil, fmt.Errorf("%s: %s", ErrExcludedRemap, address) // Return error
+			}
+		}
+
+		remap[strings.ToLower(from)] = strings.ToLower(to) // Set remapped address
+	}
+
+	genesisEntry := &AllocEntry{Role: RoleGenesis, Name: RoleGenesis} // Init generated genesis entry
+
+	alloc := Alloc{genesisEntry} // Init alloc
+
+	entries := make(map[string]*AllocEntry) // Init entries buffer, keyed by address (or name, if generated)
+
+	amounts := make(map[*AllocEntry]*big.Float) // Init amounts buffer
+
+	names := map[string]bool{RoleGenesis: true} // Init taken names buffer
+
+	supply := new(big.Float).SetPrec(350) // Init supply buffer
+
+	for _, balance := range balances { // Iterate through balances
+		address := strings.ToLower(balance.Address) // Normalize address
+
+		if excluded[address] || balance.Balance == nil || balance.Balance.Sign() == 0 { // Check left out
+			continue // Continue
+		}
+
+		if balance.Balance.Sign() < 0 { // Check negative balance
+			return nil, fmt.Errorf("%s: %s (%s)", ErrNegativeBalance, balance.Address, balance.Balance.Text('f', -1)) // Return error
+		}
+
+		schedule, err := carrySchedule(balance, spinoff.At) // Get remaining vesting schedule
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		role := balance.Role // Get role
+
+		if role == RoleGenesis || (role == RoleFaucet && !spinoff.ReplaceFaucets) { // Check role can't be kept
+			role = "" // Drop role
+		}
+
+		if to, ok := remap[address]; ok { // Check remapped
+			address = to // Set remapped address
+		}
+
+		key := address // Get entry key
+
+		if role == RoleFaucet { // Check faucet must be generated
+			address, key = "", "faucet:"+balance.Name // Generate faucet
+		}
+
+		entry, ok := entries[key] // Get existing entry
+
+		if ok && (entry.Vesting != nil || schedule != nil) { // Check merging locked balance
+			return nil, fmt.Errorf("%s: %s (its balance would be merged with another account's)", ErrUncarriedLock, balance.Address) // Return error
+		}
+
+		if !ok { // Check no existing entry
+			entry = &AllocEntry{Address: address, Role: role, Vesting: schedule} // Init entry
+
+			if balance.Name != "" && !names[balance.Name] { // Check name not taken
+				entry.Name, names[balance.Name] = balance.Name, true // Set name
+			}
+
+			entries[key], amounts[entry] = entry, new(big.Float).SetPrec(350) // Set entry
+
+			alloc = append(alloc, entry) // Append entry
+		}
+
+		amounts[entry].Add(amounts[entry], balance.Balance) // Add balance
+		supply.Add(supply, balance.Balance)                 // Add to supply
+	}
+
+	for entry, amount := range amounts { // Iterate through entries
+		entry.Balance = amount.Text('f', -1) // Set balance
+	}
+
+	genesisEntry.Balance = supply.Text('f', -1) // Set supply
+
+	networkID := spinoff.NetworkID           // Get network ID
+	inflation := chainConfig.InflationRate   // Get inflation rate
+	chainVersion := chainConfig.ChainVersion // Get chain version
+
+	genesis := &Genesis{
+		Version:      CurrentVersion, // Set version
+		NetworkID:    &networkID,     // Set network ID
+		Inflation:    &inflation,     // Set inflation
+		ChainVersion: &chainVersion,  // Set chain version
+		Alloc:        alloc,          // Set alloc
+	} // Init genesis
+
+	return genesis, genesis.Validate() // Return genesis
+}
+
+// ParseRemapFlags parses a set of remap flag values, each in the form old=new.
+func ParseRemapFlags(values []string) (map[string]string, error) {
+	remap := make(map[string]string) // Init remap buffer
+
+	var problems ValidationErrors // Init problems buffer
+
+	for i, value := range values { // Iterate through values
+		path := fmt.Sprintf("--remap[%d]", i) // Get path
+
+		split := strings.SplitN(value, "=", 2) // Split old, new
+
+		if len(split) != 2 { // Check invalid format
+			problems = append(problems, &ValidationError{Path: path, Message: ErrInvalidRemapFlag.Error()}) // Append problem
+
+			continue // Continue
+		}
+
+		for _, address := range split { // Iterate through addresses
+			if err := validateAddress(address); err != nil { // Check invalid address
+				problems = append(problems, &ValidationError{Path: path, Message: err.Error()}) // Append problem
+			}
+		}
+
+		remap[split[0]] = split[1] // Set remapped address
+	}
+
+	if len(problems) > 0 { // Check has problems
+		return nil, problems // Return problems
+	}
+
+	return remap, nil // No error occurred, return remap
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// carrySchedule gets the part of the vesting schedule of a given balance still in effect at a given time, or nil if
+// the balance has no lock, or is fully unlocked by then.
+func carrySchedule(balance *Balance, at time.Time) (*vesting.Schedule, error) {
+	if balance.Lock == nil || balance.Lock.Schedule == nil { // Check no lock
+		return nil, nil // Nothing to carry over
+	}
+
+	allocated, ok := new(big.Float).SetPrec(350).SetString(balance.Lock.Balance) // Parse allocated balance
+
+	if !ok { // Check invalid allocated balance
+		return nil, fmt.Errorf("%s: %s (invalid allocated balance %q)", ErrUncarriedLock, balance.Address, balance.Lock.Balance) // Return error
+	}
+
+	status := balance.Lock.Schedule.Status(allocated, at) // Get lock status
+
+	if status.Locked.Sign() <= 0 { // Check fully unlocked
+		return nil, nil // Nothing to carry over
+	}
+
+	if balance.Balance.Cmp(status.Locked) < 0 { // Check spent locked balance
+		return nil, fmt.Errorf("%s: %s (%s locked, %s held)", ErrLockedBalanceSpent, balance.Address, status.Locked.Text('f', -1), balance.Balance.Text('f', -1)) // Return error
+	}
+
+	if len(balance.Lock.Schedule.Unlocks) > 0 { // Check fixed unlocks
+		schedule := &vesting.Schedule{} // Init schedule
+
+		for _, unlock := range balance.Lock.Schedule.Unlocks { // Iterate through unlocks
+			if unlock.At.After(at) { // Check still locked
+				schedule.Unlocks = append(schedule.Unlocks, &vesting.Unlock{At: unlock.At, Amount: unlock.Amount}) // Append unlock
+			}
+		}
+
+		return schedule, nil // Return remaining unlocks
+	}
+
+	if balance.Balance.Cmp(allocated) != 0 { // Check balance changed
+		return nil, fmt.Errorf("%s: %s (a linear release can only be carried over while the account holds exactly its allocation of %s, not %s)", ErrUncarriedLock, balance.Address, allocated.Text('f', -1), balance.Balance.Text('f', -1)) // Return error
+	}
+
+	schedule := *balance.Lock.Schedule // Copy schedule
+
+	return &schedule, nil // Return schedule
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,138 @@
+// Package genesis defines the puppet genesis file format, along with helper methods for decoding and validating genesis files.
+package genesis
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/puppet/vesting"
+)
+
+const (
+	// testFaucetAddress is a valid address used as a faucet account in tests.
+	testFaucetAddress = "0x040153b02f793b51160cc7c291c8bf67a844"
+
+	// testRemapAddress is a valid address that balances are remapped to in tests.
+	testRemapAddress = "0x0401a6d99270788014429e092dd9f1f3ee9c"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestFromBalances tests the functionality of the FromBalances() method.
+func TestFromBalances(t *testing.T) {
+	balances := []*Balance{
+		{Address: testGenesisAddress, Role: RoleGenesis, Name: "genesis", Balance: big.NewFloat(60)}, // Existing genesis account
+		{Address: testAllocAddress, Role: RoleTreasury, Name: "team", Balance: big.NewFloat(25.5)},   // Treasury
+		{Address: testFaucetAddress, Role: RoleFaucet, Name: "faucet", Balance: big.NewFloat(10)},    // Faucet
+		{Address: testRemapAddress, Balance: new(big.Float)},                                         // Empty account
+	} // Init balances
+
+	chainConfig := &config.ChainConfig{NetworkID: 7, InflationRate: 0.1, ChainVersion: "0.8.0"} // Init chain config
+
+	spinoff, err := FromBalances(chainConfig, balances, &Spinoff{NetworkID: 8}) // Make genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if *spinoff.NetworkID != 8 || *spinoff.Inflation != 0.1 || *spinoff.ChainVersion != "0.8.0" { // Check invalid values
+		t.Fatalf("expected network 8 at 0.8.0 with 10%% inflation, found %+v", spinoff) // Panic
+	}
+
+	if len(spinoff.Alloc) != 4 || spinoff.Alloc[0].Address != "" || spinoff.Alloc[0].Balance != "95.5" { // Check invalid supply
+		t.Fatalf("expected a generated genesis account allocating 95.5 to 3 accounts, found %d entries", len(spinoff.Alloc)) // Panic
+	}
+
+	if entry := spinoff.Alloc[1]; entry.Address != testGenesisAddress || entry.Role != "" || entry.Name != "" || entry.Balance != "60" { // Check genesis role kept
+		t.Fatalf("expected the existing genesis account to be allocated 60 without its role or taken name, found %+v", entry) // Panic
+	}
+
+	if entry := spinoff.Alloc[3]; entry.Address != testFaucetAddress || entry.Role != "" || entry.Name != "faucet" { // Check faucet role kept
+		t.Fatalf("expected the faucet to keep its address and name, but not its role, found %+v", entry) // Panic
+	}
+
+	spinoff, err = FromBalances(chainConfig, balances, &Spinoff{NetworkID: 8, Exclude: []string{testAllocAddress}, Remap: map[string]string{testGenesisAddress: testRemapAddress}, ReplaceFaucets: true}) // Make genesis with changes
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if len(spinoff.Alloc) != 3 || spinoff.Alloc[0].Balance != "70" || spinoff.Alloc[1].Address != testRemapAddress { // Check exclusion or remap not applied
+		t.Fatalf("expected 70 allocated to the remapped account and the faucet, found %d entries", len(spinoff.Alloc)) // Panic
+	}
+
+	if entry := spinoff.Alloc[2]; entry.Address != "" || entry.Role != RoleFaucet || entry.Name != "faucet" || entry.Balance != "10" { // Check faucet not replaced
+		t.Fatalf("expected a generated faucet named faucet, found %+v", entry) // Panic
+	}
+
+	if _, err = FromBalances(chainConfig, balances, &Spinoff{NetworkID: 8, Exclude: []string{"0x040000000000000000000000000000000000"}}); err == nil { // Check unknown address excluded
+		t.Fatal("expected an error for excluding an address without a balance") // Panic
+	}
+}
+
+// TestFromBalancesVesting tests that FromBalances() carries over the part of each vesting lock still in effect, and
+// rejects locks it can't reproduce.
+func TestFromBalancesVesting(t *testing.T) {
+	at := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC) // Get spinoff time
+
+	start := at.Add(-24 * time.Hour) // Get linear release start
+
+	unlocks := &vesting.Lock{Balance: "30", Schedule: &vesting.Schedule{Unlocks: []*vesting.Unlock{{At: at.Add(-time.Hour), Amount: "10"}, {At: at.Add(time.Hour), Amount: "20"}}}} // Init fixed unlocks
+	linear := &vesting.Lock{Balance: "40", Schedule: &vesting.Schedule{Start: &start, Duration: vesting.Duration(48 * time.Hour)}}                                                  // Init linear release
+
+	balances := []*Balance{
+		{Address: testAllocAddress, Balance: big.NewFloat(25), Lock: unlocks}, // Account that spent part of its unlocked balance
+		{Address: testFaucetAddress, Balance: big.NewFloat(40), Lock: linear}, // Account holding its allocation
+	} // Init balances
+
+	chainConfig := &config.ChainConfig{NetworkID: 7, InflationRate: 0.1, ChainVersion: "0.8.0"} // Init chain config
+
+	spinoff, err := FromBalances(chainConfig, balances, &Spinoff{NetworkID: 8, At: at}) // Make genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if schedule := spinoff.Alloc[1].Vesting; schedule == nil || len(schedule.Unlocks) != 1 || schedule.Unlocks[0].Amount != "20" { // Check passed unlock kept
+		t.Fatalf("expected only the unlock of 20 still ahead to be carried over, found %+v", schedule) // Panic
+	}
+
+	if schedule := spinoff.Alloc[2].Vesting; schedule == nil || !schedule.Start.Equal(start) || schedule.Duration != linear.Schedule.Duration { // Check linear release changed
+		t.Fatalf("expected the linear release to be carried over as it is, found %+v", schedule) // Panic
+	}
+
+	if spinoff, err = FromBalances(chainConfig, balances, &Spinoff{NetworkID: 8, At: at.Add(48 * time.Hour)}); err != nil || spinoff.Alloc[1].Vesting != nil || spinoff.Alloc[2].Vesting != nil { // Check expired locks carried over
+		t.Fatalf("expected fully unlocked balances to be carried over without locks, found %v", err) // Panic
+	}
+
+	for _, test := range []struct {
+		balances []*Balance // Balances
+		spinoff  *Spinoff   // Spinoff
+	}{
+		{[]*Balance{{Address: testAllocAddress, Balance: big.NewFloat(15), Lock: unlocks}}, &Spinoff{NetworkID: 8, At: at}},                                                                                                 // Locked balance spent
+		{[]*Balance{{Address: testFaucetAddress, Balance: big.NewFloat(45), Lock: linear}}, &Spinoff{NetworkID: 8, At: at}},                                                                                                 // Linear release balance changed
+		{[]*Balance{balances[0], {Address: testRemapAddress, Balance: big.NewFloat(5)}}, &Spinoff{NetworkID: 8, At: at, Remap: map[string]string{testRemapAddress: testAllocAddress}}},                                      // Locked balance merged
+		{[]*Balance{balances[0], {Address: testRemapAddress, Balance: big.NewFloat(5)}}, &Spinoff{NetworkID: 8, At: at, Exclude: []string{testAllocAddress}, Remap: map[string]string{testRemapAddress: testAllocAddress}}}, // Remapped to excluded address
+	} { // Iterate through invalid spinoffs
+		if _, err = FromBalances(chainConfig, test.balances, test.spinoff); err == nil { // Check spinoff accepted
+			t.Fatalf("expected an error for spinoff %+v", test.spinoff) // Panic
+		}
+	}
+}
+
+// TestParseRemapFlags tests the functionality of the ParseRemapFlags() method.
+func TestParseRemapFlags(t *testing.T) {
+	remap, err := ParseRemapFlags([]string{testGenesisAddress + "=" + testRemapAddress}) // Parse flags
+
+	if err != nil || remap[testGenesisAddress] != testRemapAddress { // Check invalid remap
+		t.Fatalf("expected %s to be remapped to %s, found %v (%v)", testGenesisAddress, testRemapAddress, remap, err) // Panic
+	}
+
+	if _, err = ParseRemapFlags([]string{testGenesisAddress, testGenesisAddress + "=0xzz"}); err == nil { // Check invalid flags parsed
+		t.Fatal("expected an error for invalid remap flags") // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
@@ -0,0 +1,238 @@
+// Package genesis defines the puppet genesis file format, along with helper methods for decoding and validating genesis files.
+package genesis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SummerCash/go-summercash/config"
+)
+
+const (
+	// testGenesisAddress is a valid address used as the genesis account in tests.
+	testGenesisAddress = "0x040000fe1cb145827b9833a8b3668190d6df"
+
+	// testAllocAddress is a valid address used as an additional alloc address in tests.
+	testAllocAddress = "0x0401351b42bea39ac6f38bef70bf2f7ae5e4"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestDecode tests the functionality of the Decode() method.
+func TestDecode(t *testing.T) {
+	genesis, err := Decode([]byte(`{"version": 1, "networkID": 7, "inflation": 0.5, "alloc": {"` + testGenesisAddress + `": {"balance": "100"}, "` + testAllocAddress + `": {"balance": "25.5"}}}`)) // Decode genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if *genesis.NetworkID != 7 || *genesis.Inflation != 0.5 { // Check invalid values
+		t.Fatal("network ID and inflation must be decoded") // Panic
+	}
+
+	if len(genesis.Alloc) != 2 || genesis.Alloc[0].Address != testGenesisAddress || genesis.Alloc[1].Address != testAllocAddress { // Check order not preserved
+		t.Fatal("alloc order must be preserved") // Panic
+	}
+}
+
+// TestDecodeInvalid tests that the Decode() method reports every problem in a genesis file, along with its path.
+func TestDecodeInvalid(t *testing.T) {
+	upperAllocAddress := "0x" + strings.ToUpper(testAllocAddress[2:]) // Get differently-cased duplicate
+
+	_, err := Decode([]byte(`{"alloc": {"` + testGenesisAddress + `": {"balance": "0"}, "0xzz": {"balance": "1"}, "` + testAllocAddress + `": {"balance": "-1"}, "` + upperAllocAddress + `": {"balance": "one"}}}`)) // Decode genesis
+
+	problems, ok := err.(ValidationErrors) // Get problems
+
+	if !ok { // Check not validation errors
+		t.Fatalf("expected validation errors, found %v", err) // Panic
+	}
+
+	for _, expected := range []string{
+		`alloc["` + testGenesisAddress + `"].balance: genesis supply must not be zero`,
+		`alloc["0xzz"]: address "0xzz" is not valid hex`,
+		`alloc["` + testAllocAddress + `"].balance: must not be negative`,
+		`alloc["` + upperAllocAddress + `"]: duplicate alloc entry`,
+		`alloc["` + upperAllocAddress + `"].balance: "one" is not a number`,
+	} { // Iterate through expected problems
+		if !strings.Contains(problems.Error(), expected) { // Check missing problem
+			t.Fatalf("expected problem %s, found %s", expected, problems.Error()) // Panic
+		}
+	}
+}
+
+// TestDecodeRoles tests that the Decode() method decodes alloc entry roles and names, along with entries keyed by name.
+func TestDecodeRoles(t *testing.T) {
+	genesis, err := Decode([]byte(`{"version": 3, "alloc": {"genesis": {"balance": "100"}, "dev": {"role": "faucet", "balance": "5"}, "` + testAllocAddress + `": {"role": "treasury", "name": "ops", "balance": "10"}}}`)) // Decode genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if genesis.Alloc[0].Address != "" || genesis.Alloc[0].Name != "genesis" || genesis.Alloc.EntryRole(0) != RoleGenesis { // Check invalid genesis entry
+		t.Fatal("entries keyed by name must be generated") // Panic
+	}
+
+	if genesis.Alloc[1].Role != RoleFaucet || genesis.Alloc[1].Name != "dev" || genesis.Alloc[2].Name != "ops" || genesis.Alloc[2].Address != testAllocAddress { // Check invalid entries
+		t.Fatal("roles and names must be decoded") // Panic
+	}
+
+	if _, _, err := genesis.Alloc.Balances(); err == nil { // Check ungenerated entries accepted
+		t.Fatal("balances of ungenerated entries must be rejected") // Panic
+	}
+}
+
+// TestDecodeInvalidRoles tests that the Decode() method reports invalid alloc entry roles and names.
+func TestDecodeInvalidRoles(t *testing.T) {
+	_, err := Decode([]byte(`{"version": 3, "alloc": {"dev": {"role": "faucet", "balance": "100"}, "` + testAllocAddress + `": {"role": "faucet", "balance": "1"}, "qa": {"role": "miner", "balance": "1"}, "bad name": {"balance": "1"}, "` + testGenesisAddress[2:] + `": {"balance": "1"}}}`)) // Decode genesis
+
+	for _, expected := range []string{
+		`alloc["dev"].role: the first alloc entry is the genesis account`,
+		`alloc["` + testAllocAddress + `"].name: faucets must have a name`,
+		`alloc["qa"].role: unknown role "miner"`,
+		`alloc["bad name"]: "bad name" is neither an address nor a valid name`,
+		`alloc["` + testGenesisAddress[2:] + `"]: address "` + testGenesisAddress[2:] + `" must begin with 0x`,
+	} { // Iterate through expected problems
+		if err == nil || !strings.Contains(err.Error(), expected) { // Check missing problem
+			t.Fatalf("expected problem %s, found %v", expected, err) // Panic
+		}
+	}
+
+	_, err = Decode([]byte(`{"version": 2, "alloc": {"genesis": {"balance": "100"}}}`)) // Decode genesis
+
+	if err == nil || !strings.Contains(err.Error(), "require version 3 or later") { // Check not rejected
+		t.Fatalf("expected version error, found %v", err) // Panic
+	}
+}
+
+// TestDecodeVesting tests that the Decode() method decodes and validates alloc entry vesting schedules.
+func TestDecodeVesting(t *testing.T) {
+	genesis, err := Decode([]byte(`{"version": 4, "alloc": {"genesis": {"balance": "100"}, "team": {"balance": "10", "vesting": {"start": "2026-01-01T00:00:00Z", "cliff": "90d", "duration": "365d"}}, "advisors": {"balance": "5", "vesting": {"unlocks": [{"at": "2026-06-01T00:00:00Z", "amount": 2}]}}}}`)) // Decode genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if genesis.Alloc[1].Vesting == nil || genesis.Alloc[1].Vesting.Duration.String() != "365d" || genesis.Alloc[2].Vesting.Unlocks[0].Amount != "2" { // Check invalid schedules
+		t.Fatal("vesting schedules must be decoded") // Panic
+	}
+
+	_, err = Decode([]byte(`{"version": 4, "alloc": {"genesis": {"balance": "100", "vesting": {"unlocks": [{"at": "2026-06-01T00:00:00Z", "amount": "1"}]}}, "team": {"balance": "10", "vesting": {"cliff": "400d", "duration": "365d"}}, "advisors": {"balance": "5", "vesting": {"unlocks": [{"at": "2026-06-01T00:00:00Z", "amount": "6"}]}}, "ops": {"balance": "1", "vesting": {"duration": "soon"}}}}`)) // Decode genesis
+
+	for _, expected := range []string{
+		`alloc["genesis"].vesting: the genesis account cannot vest`,
+		`alloc["team"].vesting.start: missing start`,
+		`alloc["team"].vesting.cliff: must not exceed duration`,
+		`alloc["advisors"].vesting.unlocks: unlocks (6) exceed balance (5)`,
+		`alloc["ops"].vesting: invalid duration`,
+	} { // Iterate through expected problems
+		if err == nil || !strings.Contains(err.Error(), expected) { // Check missing problem
+			t.Fatalf("expected problem %s, found %v", expected, err) // Panic
+		}
+	}
+
+	_, err = Decode([]byte(`{"version": 3, "alloc": {"genesis": {"balance": "100"}, "team": {"balance": "10", "vesting": {"start": "2026-01-01T00:00:00Z", "duration": "365d"}}}}`)) // Decode genesis
+
+	if err == nil || !strings.Contains(err.Error(), "requires version 4 or later") { // Check not rejected
+		t.Fatalf("expected version error, found %v", err) // Panic
+	}
+}
+
+// TestDecodeUnknownKey tests that the Decode() method rejects unknown keys.
+func TestDecodeUnknownKey(t *testing.T) {
+	_, err := Decode([]byte(`{"networkID": 1, "supply": "100"}`)) // Decode genesis
+
+	if err == nil || !strings.Contains(err.Error(), "supply: unknown key") { // Check not rejected
+		t.Fatalf("expected unknown key error, found %v", err) // Panic
+	}
+}
+
+// TestDecodeDuplicate tests that the Decode() method rejects duplicate alloc entries.
+func TestDecodeDuplicate(t *testing.T) {
+	_, err := Decode([]byte(`{"alloc": {"` + testGenesisAddress + `": {"balance": "100"}, "` + testGenesisAddress + `": {"balance": "1"}}}`)) // Decode genesis
+
+	if err == nil || !strings.Contains(err.Error(), "duplicate alloc entry") { // Check not rejected
+		t.Fatalf("expected duplicate alloc entry error, found %v", err) // Panic
+	}
+}
+
+// TestParseAllocFlags tests the functionality of the ParseAllocFlags() method.
+func TestParseAllocFlags(t *testing.T) {
+	alloc, err := ParseAllocFlags([]string{testAllocAddress + "=10"}) // Parse flags
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if len(alloc) != 1 || alloc[0].Balance != "10" { // Check invalid alloc
+		t.Fatal("alloc flag must be parsed") // Panic
+	}
+
+	_, err = ParseAllocFlags([]string{testAllocAddress}) // Parse invalid flags
+
+	if err == nil { // Check not rejected
+		t.Fatal("alloc flag without amount must be rejected") // Panic
+	}
+}
+
+// TestBalances tests the functionality of the Balances() method.
+func TestBalances(t *testing.T) {
+	alloc := Alloc{{Address: testGenesisAddress, Balance: "100"}}.Merge(Alloc{{Address: testAllocAddress, Balance: "5"}}) // Init alloc
+
+	balances, addresses, err := alloc.Balances() // Get balances
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if len(addresses) != 2 || addresses[0].String() != testGenesisAddress || balances[testAllocAddress].String() != "5" { // Check invalid balances
+		t.Fatal("balances must match alloc") // Panic
+	}
+}
+
+// TestMerge tests that the Merge() method only replaces the balance of existing entries.
+func TestMerge(t *testing.T) {
+	alloc, err := Decode([]byte(`{"version": 4, "alloc": {"` + testGenesisAddress + `": {"role": "genesis", "name": "founders", "balance": "100"}, "team": {"balance": "10", "vesting": {"start": "2026-01-01T00:00:00Z", "duration": "365d"}}}}`)) // Decode genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	merged := alloc.Alloc.Merge(Alloc{{Address: testGenesisAddress, Balance: "50"}, {Name: "team", Balance: "20"}, {Address: testAllocAddress, Balance: "5"}}) // Merge entries
+
+	if len(merged) != 3 || merged[0].Role != RoleGenesis || merged[0].Name != "founders" || merged[0].Balance != "50" || merged[1].Vesting == nil || merged[1].Balance != "20" || merged[2].Balance != "5" { // Check invalid merge
+		t.Fatal("merge must only replace the balance of existing entries") // Panic
+	}
+
+	if alloc.Alloc[0].Balance != "100" || alloc.Alloc[1].Balance != "10" { // Check original alloc modified
+		t.Fatal("merge must not modify the original alloc") // Panic
+	}
+}
+
+// TestFromChainConfig tests the functionality of the FromChainConfig() method.
+func TestFromChainConfig(t *testing.T) {
+	alloc, addresses, err := Alloc{{Address: testGenesisAddress, Balance: "100"}, {Address: testAllocAddress, Balance: "0.5"}}.Balances() // Get balances
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	genesis, err := FromChainConfig(&config.ChainConfig{Alloc: alloc, AllocAddresses: addresses, NetworkID: 4, InflationRate: 0.2, ChainVersion: "0.7.3"}) // Make genesis
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if *genesis.NetworkID != 4 || *genesis.Inflation != 0.2 || *genesis.ChainVersion != "0.7.3" { // Check invalid values
+		t.Fatal("genesis must match chain config") // Panic
+	}
+
+	if len(genesis.Alloc) != 2 || genesis.Alloc[1].Address != testAllocAddress || genesis.Alloc[1].Balance != "0.5" { // Check invalid alloc
+		t.Fatal("genesis alloc must match chain config alloc") // Panic
+	}
+
+	if entry := genesis.Alloc[0]; entry.Address != "" || entry.Role != RoleGenesis || entry.Key() != RoleGenesis || entry.Balance != "100" { // Check genesis entry keyed by address
+		t.Fatalf("expected a generated genesis entry allocating 100, found %+v", entry) // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
@@ -3,6 +3,7 @@ module github.com/SummerCash/puppet
 go 1.12
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/SummerCash/go-summercash v0.7.3
 	github.com/SummerCash/summercash-wallet-server v0.6.1
 	github.com/boltdb/bolt v1.3.1
@@ -10,10 +11,12 @@ require (
 	github.com/gernest/wow v0.1.0
 	github.com/klauspost/compress v1.7.0 // indirect
 	github.com/kyokomi/emoji v2.1.0+incompatible
+	github.com/mattn/go-sqlite3 v1.11.0
 	github.com/tcnksm/go-input v0.0.0-20180404061846-548a7d7a8ee8
 	github.com/tockins/interact v0.0.0-20171114182912-f8fb5795b5d7
 	github.com/urfave/cli v1.20.0
-	golang.org/x/crypto v0.0.0-20190605123033-f99c8df09eb5 // indirect
+	golang.org/x/crypto v0.0.0-20190605123033-f99c8df09eb5
 	golang.org/x/net v0.0.0-20190607181551-461777fb6f67 // indirect
 	golang.org/x/sys v0.0.0-20190610200419-93c9922d18ae // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,407 @@
+// Package graph defines an address-level graph of the transactions in a SummerCash blockmesh, along with helper methods
+// for encoding it as Graphviz DOT, GraphML, or a JSON nodes/edges document.
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+// Format represents a graph encoding.
+type Format string
+
+const (
+	// FormatDOT is the Graphviz DOT graph format.
+	FormatDOT Format = "dot"
+
+	// FormatGraphML is the GraphML graph format.
+	FormatGraphML Format = "graphml"
+
+	// FormatJSON is the JSON nodes/edges graph format.
+	FormatJSON Format = "json"
+)
+
+// Graph represents the flow of funds between the addresses of a blockmesh.
+type Graph struct {
+	Nodes []*Node // Addresses, ordered by address
+	Edges []*Edge // Transfers between addresses, ordered by sender and recipient
+}
+
+// Node represents an address in a graph.
+type Node struct {
+	Address string // Address
+
+	Sent     *big.Float // Total sent along the graph's edges
+	Received *big.Float // Total received along the graph's edges
+
+	Transactions int // Number of transactions sent or received along the graph's edges
+}
+
+// Edge represents every transfer from one address to another.
+type Edge struct {
+	From string // Sender address
+	To   string // Recipient address
+
+	Amount *big.Float // Total amount transferred
+	Count  int        // Number of transactions
+
+	First time.Time // Time of the earliest transaction
+	Last  time.Time // Time of the latest transaction
+}
+
+// Options represents the transactions and addresses to build a graph from.
+type Options struct {
+	Since time.Time // Earliest transaction time to include (unbounded if zero)
+	Until time.Time // Time to include transactions up to, exclusive (unbounded if zero)
+
+	From []string // Addresses to start from (every address if empty)
+	Hops int      // Number of transfers, in either direction, an address may be from a starting address
+}
+
+var (
+	// ErrUnknownFormat is an error definition describing an unsupported graph format.
+	ErrUnknownFormat = errors.New("unknown graph format; expected dot, graphml, or json")
+
+	// ErrInvalidHops is an error definition describing a negative hop limit.
+	ErrInvalidHops = errors.New("hops must be at least 0")
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// Build builds the graph of the transactions in a given set of chains. Each transaction is counted once, no matter how
+// many chains hold it. Transactions without a sender or recipient (e.g. genesis issuance) have no edge to lie on,
+// and are left out.
+func Build(chains []*types.Chain, options Options) (*Graph, error) {
+	if options.Hops < 0 { // Check invalid hops
+		return nil, ErrInvalidHops // Return error
+	}
+
+	seen := make(map[summercashCommon.Hash]bool) // Init seen transactions buffer
+	edges := make(map[[2]string]*Edge)           // Init edges buffer
+
+	for _, chain := range chains { // Iterate through chains
+		for _, transaction := range chain.Transactions { // Iterate through transactions
+			if transaction == nil || transaction.Hash == nil || transaction.Sender == nil || transaction.Recipient == nil || seen[*transaction.Hash] { // Check not an edge, or already counted
+				continue // Continue
+			}
+
+			seen[*transaction.Hash] = true // Set seen
+
+			if (!options.Since.IsZero() && transaction.Timestamp.Before(options.Since)) || (!options.Until.IsZero() && !transaction.Timestamp.Before(options.Until)) { // Check outside window
+				continue // Continue
+			}
+
+			key := [2]string{transaction.Sender.String(), transaction.Recipient.String()} // Get edge key
+
+			edge := edges[key] // Get edge
+
+			if edge == nil { // Check new edge
+				edge = &Edge{From: key[0], To: key[1], Amount: new(big.Float), First: transaction.Timestamp, Last: transaction.Timestamp} // Init edge
+
+				edges[key] = edge // Set edge
+			}
+
+			if transaction.Amount != nil { // Check has amount
+				edge.Amount.Add(edge.Amount, transaction.Amount) // Add amount
+			}
+
+			edge.Count++ // Increment count
+
+			if transaction.Timestamp.Before(edge.First) { // Check earlier
+				edge.First = transaction.Timestamp // Set first
+			}
+
+			if transaction.Timestamp.After(edge.Last) { // Check later
+				edge.Last = transaction.Timestamp // Set last
+			}
+		}
+	}
+
+	var reachable map[string]bool // Init reachable addresses buffer
+
+	if len(options.From) > 0 { // Check has starting addresses
+		reachable = neighbourhood(edges, options.From, options.Hops) // Get reachable addresses
+	}
+
+	graph := &Graph{} // Init graph
+
+	nodes := make(map[string]*Node) // Init nodes buffer
+
+	node := func(address string) *Node {
+		if nodes[address] == nil { // Check new node
+			nodes[address] = &Node{Address: address, Sent: new(big.Float), Received: new(big.Float)} // Init node
+		}
+
+		return nodes[address] // Return node
+	}
+
+	for _, edge := range edges { // Iterate through edges
+		if reachable != nil && (!reachable[edge.From] || !reachable[edge.To]) { // Check too far from start
+			continue // Continue
+		}
+
+		sender, recipient := node(edge.From), node(edge.To) // Get nodes
+
+		sender.Sent.Add(sender.Sent, edge.Amount)               // Add sent
+		recipient.Received.Add(recipient.Received, edge.Amount) // Add received
+
+		sender.Transactions += edge.Count // Add sender transactions
+
+		if edge.To != edge.From { // Check not a transfer to self
+			recipient.Transactions += edge.Count // Add recipient transactions
+		}
+
+		graph.Edges = append(graph.Edges, edge) // Append edge
+	}
+
+	for _, node := range nodes { // Iterate through nodes
+		graph.Nodes = append(graph.Nodes, node) // Append node
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].Address < graph.Nodes[j].Address }) // Sort nodes
+
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From { // Check different senders
+			return graph.Edges[i].From < graph.Edges[j].From // Order by sender
+		}
+
+		return graph.Edges[i].To < graph.Edges[j].To // Order by recipient
+	}) // Sort edges
+
+	return graph, nil // Return graph
+}
+
+// Encode encodes a graph in a given format.
+func (graph *Graph) Encode(format Format) ([]byte, error) {
+	switch format {
+	case FormatDOT:
+		return graph.dot(), nil // Return DOT
+	case FormatGraphML:
+		return graph.graphML() // Return GraphML
+	case FormatJSON:
+		return graph.json() // Return JSON
+	}
+
+	return nil, ErrUnknownFormat // Return unknown format
+}
+
+// ParseFormat parses a graph format name.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "dot", "gv":
+		return FormatDOT, nil // Return DOT
+	case "graphml":
+		return FormatGraphML, nil // Return GraphML
+	case "json":
+		return FormatJSON, nil // Return JSON
+	}
+
+	return "", fmt.Errorf("%s: %s", ErrUnknownFormat, name) // Return unknown format
+}
+
+// FormatFromPath detects the format of a graph file from its extension.
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(filepath.Ext(path)) // Parse extension
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// neighbourhood gets the addresses within a given number of hops of a set of starting addresses, following edges in
+// either direction.
+func neighbourhood(edges map[[2]string]*Edge, from []string, hops int) map[string]bool {
+	neighbours := make(map[string][]string) // Init adjacency buffer
+
+	for key := range edges { // Iterate through edges
+		neighbours[key[0]] = append(neighbours[key[0]], key[1]) // Link sender to recipient
+		neighbours[key[1]] = append(neighbours[key[1]], key[0]) // Link recipient to sender
+	}
+
+	reachable := make(map[string]bool) // Init reachable buffer
+
+	frontier := []string{} // Init frontier buffer
+
+	for _, address := range from { // Iterate through starting addresses
+		if !reachable[address] { // Check not already reached
+			reachable[address] = true // Set reachable
+
+			frontier = append(frontier, address) // Add to frontier
+		}
+	}
+
+	for hop := 0; hop < hops && len(frontier) > 0; hop++ { // Iterate through hops
+		var next []string // Init next frontier buffer
+
+		for _, address := range frontier { // Iterate through frontier
+			for _, neighbour := range neighbours[address] { // Iterate through neighbours
+				if !reachable[neighbour] { // Check not already reached
+					reachable[neighbour] = true // Set reachable
+
+					next = append(next, neighbour) // Add to next frontier
+				}
+			}
+		}
+
+		frontier = next // Advance frontier
+	}
+
+	return reachable // Return reachable addresses
+}
+
+// dot encodes a graph as Graphviz DOT.
+func (graph *Graph) dot() []byte {
+	buffer := new(bytes.Buffer) // Init buffer
+
+	buffer.WriteString("digraph blockmesh {\n") // Write header
+
+	for _, node := range graph.Nodes { // Iterate through nodes
+		fmt.Fprintf(buffer, "  %q [sent=%q, received=%q, transactions=%d];\n", node.Address, amount(node.Sent), amount(node.Received), node.Transactions) // Write node
+	}
+
+	for _, edge := range graph.Edges { // Iterate through edges
+		fmt.Fprintf(buffer, "  %q -> %q [label=%q, amount=%q, count=%d, weight=%d];\n", edge.From, edge.To, fmt.Sprintf("%s (%d tx)", amount(edge.Amount), edge.Count), amount(edge.Amount), edge.Count, edge.Count) // Write edge
+	}
+
+	buffer.WriteString("}\n") // Write footer
+
+	return buffer.Bytes() // Return DOT
+}
+
+// graphML encodes a graph as GraphML.
+func (graph *Graph) graphML() ([]byte, error) {
+	type data struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+
+	type key struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+
+	type node struct {
+		ID   string `xml:"id,attr"`
+		Data []data `xml:"data"`
+	}
+
+	type edge struct {
+		ID     string `xml:"id,attr"`
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+		Data   []data `xml:"data"`
+	}
+
+	type document struct {
+		XMLName xml.Name `xml:"graphml"`
+		XMLNS   string   `xml:"xmlns,attr"`
+		Keys    []key    `xml:"key"`
+		Graph   struct {
+			ID          string `xml:"id,attr"`
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []node `xml:"node"`
+			Edges       []edge `xml:"edge"`
+		} `xml:"graph"`
+	}
+
+	encoded := document{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns", // Set namespace
+		Keys: []key{
+			{ID: "sent", For: "node", Name: "sent", Type: "double"},              // Node sent
+			{ID: "received", For: "node", Name: "received", Type: "double"},      // Node received
+			{ID: "transactions", For: "node", Name: "transactions", Type: "int"}, // Node transactions
+			{ID: "amount", For: "edge", Name: "amount", Type: "double"},          // Edge amount
+			{ID: "count", For: "edge", Name: "count", Type: "int"},               // Edge count
+			{ID: "first", For: "edge", Name: "first", Type: "string"},            // Edge first transaction
+			{ID: "last", For: "edge", Name: "last", Type: "string"},              // Edge last transaction
+		}, // Set keys
+	} // Init document
+
+	encoded.Graph.ID = "blockmesh"         // Set graph ID
+	encoded.Graph.EdgeDefault = "directed" // Set edge default
+
+	for _, n := range graph.Nodes { // Iterate through nodes
+		encoded.Graph.Nodes = append(encoded.Graph.Nodes, node{ID: n.Address, Data: []data{
+			{Key: "sent", Value: amount(n.Sent)},                     // Set sent
+			{Key: "received", Value: amount(n.Received)},             // Set received
+			{Key: "transactions", Value: fmt.Sprint(n.Transactions)}, // Set transactions
+		}}) // Append node
+	}
+
+	for i, e := range graph.Edges { // Iterate through edges
+		encoded.Graph.Edges = append(encoded.Graph.Edges, edge{ID: fmt.Sprintf("e%d", i), Source: e.From, Target: e.To, Data: []data{
+			{Key: "amount", Value: amount(e.Amount)},                      // Set amount
+			{Key: "count", Value: fmt.Sprint(e.Count)},                    // Set count
+			{Key: "first", Value: e.First.UTC().Format(time.RFC3339Nano)}, // Set first
+			{Key: "last", Value: e.Last.UTC().Format(time.RFC3339Nano)},   // Set last
+		}}) // Append edge
+	}
+
+	body, err := xml.MarshalIndent(encoded, "", "  ") // Marshal document
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return append(append([]byte(xml.Header), body...), '\n'), nil // Return GraphML
+}
+
+// json encodes a graph as a JSON nodes/edges document.
+func (graph *Graph) json() ([]byte, error) {
+	type node struct {
+		ID           string      `json:"id"`
+		Sent         json.Number `json:"sent"`
+		Received     json.Number `json:"received"`
+		Transactions int         `json:"transactions"`
+	}
+
+	type edge struct {
+		Source string      `json:"source"`
+		Target string      `json:"target"`
+		Amount json.Number `json:"amount"`
+		Count  int         `json:"count"`
+		First  time.Time   `json:"first"`
+		Last   time.Time   `json:"last"`
+	}
+
+	document := struct {
+		Nodes []node `json:"nodes"`
+		Edges []edge `json:"edges"`
+	}{Nodes: []node{}, Edges: []edge{}} // Init document
+
+	for _, n := range graph.Nodes { // Iterate through nodes
+		document.Nodes = append(document.Nodes, node{ID: n.Address, Sent: json.Number(amount(n.Sent)), Received: json.Number(amount(n.Received)), Transactions: n.Transactions}) // Append node
+	}
+
+	for _, e := range graph.Edges { // Iterate through edges
+		document.Edges = append(document.Edges, edge{Source: e.From, Target: e.To, Amount: json.Number(amount(e.Amount)), Count: e.Count, First: e.First.UTC(), Last: e.Last.UTC()}) // Append edge
+	}
+
+	encoded, err := json.MarshalIndent(document, "", "  ") // Marshal document
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return append(encoded, '\n'), nil // Return JSON
+}
+
+// amount formats an amount as a plain decimal number.
+func amount(value *big.Float) string {
+	return value.Text('f', -1) // Return formatted
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,151 @@
+// Package graph defines an address-level graph of the transactions in a SummerCash blockmesh, along with helper methods
+// for encoding it as Graphviz DOT, GraphML, or a JSON nodes/edges document.
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/internal/fixtures"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestBuild tests the functionality of the Build() method.
+func TestBuild(t *testing.T) {
+	chains, start := testChains() // Init chains
+
+	graph, err := Build(chains, Options{}) // Build graph
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if len(graph.Nodes) != 3 || len(graph.Edges) != 2 { // Check invalid graph
+		t.Fatalf("expected 3 nodes and 2 edges, found %d and %d", len(graph.Nodes), len(graph.Edges)) // Panic
+	}
+
+	if edge := graph.Edges[0]; edge.From != fixtures.Sender || edge.To != fixtures.Recipient || edge.Count != 2 || edge.Amount.Cmp(big.NewFloat(30)) != 0 || !edge.Last.Equal(start.Add(time.Hour)) { // Check duplicates counted
+		t.Fatalf("expected 2 transfers of 30 in total from %s to %s, found %+v", fixtures.Sender, fixtures.Recipient, edge) // Panic
+	}
+
+	if node := graph.Nodes[1]; node.Address != fixtures.Recipient || node.Sent.Cmp(big.NewFloat(5)) != 0 || node.Received.Cmp(big.NewFloat(30)) != 0 || node.Transactions != 3 { // Check invalid node
+		t.Fatalf("expected %s to send 5 and receive 30 over 3 transactions, found %+v", fixtures.Recipient, node) // Panic
+	}
+
+	if graph, _ = Build(chains, Options{Since: start.Add(time.Minute), Until: start.Add(2 * time.Hour)}); len(graph.Edges) != 1 || graph.Edges[0].Count != 1 { // Check window not applied
+		t.Fatalf("expected a single transfer within the window, found %d edges", len(graph.Edges)) // Panic
+	}
+
+	if graph, _ = Build(chains, Options{From: []string{fixtures.Sender}, Hops: 1}); len(graph.Nodes) != 2 || len(graph.Edges) != 1 { // Check hops not applied
+		t.Fatalf("expected 2 nodes within a hop of %s, found %d", fixtures.Sender, len(graph.Nodes)) // Panic
+	}
+
+	if graph, _ = Build(chains, Options{From: []string{fixtures.ThirdParty}, Hops: 2}); len(graph.Nodes) != 3 { // Check incoming edges not followed
+		t.Fatalf("expected 3 nodes within two hops of %s, found %d", fixtures.ThirdParty, len(graph.Nodes)) // Panic
+	}
+
+	if _, err = Build(chains, Options{Hops: -1}); err != ErrInvalidHops { // Check negative hops allowed
+		t.Fatalf("expected %v, found %v", ErrInvalidHops, err) // Panic
+	}
+}
+
+// TestEncode tests the functionality of the Encode() method.
+func TestEncode(t *testing.T) {
+	chains, _ := testChains() // Init chains
+
+	graph, err := Build(chains, Options{}) // Build graph
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	dot, err := graph.Encode(FormatDOT) // Encode DOT
+
+	if err != nil || !strings.HasPrefix(string(dot), "digraph") || strings.Count(string(dot), "->") != 2 { // Check invalid DOT
+		t.Fatalf("expected a digraph with 2 edges, found %s (%v)", dot, err) // Panic
+	}
+
+	graphML, err := graph.Encode(FormatGraphML) // Encode GraphML
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	var decodedGraphML struct {
+		Nodes []struct{} `xml:"graph>node"`
+		Edges []struct {
+			Source string `xml:"source,attr"`
+		} `xml:"graph>edge"`
+	} // Init GraphML buffer
+
+	if err = xml.Unmarshal(graphML, &decodedGraphML); err != nil || len(decodedGraphML.Nodes) != 3 || len(decodedGraphML.Edges) != 2 || decodedGraphML.Edges[0].Source != fixtures.Sender { // Check invalid GraphML
+		t.Fatalf("expected GraphML with 3 nodes and 2 edges, found %s (%v)", graphML, err) // Panic
+	}
+
+	encodedJSON, err := graph.Encode(FormatJSON) // Encode JSON
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	var decodedJSON struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+		Edges []struct {
+			Amount float64 `json:"amount"`
+		} `json:"edges"`
+	} // Init JSON buffer
+
+	if err = json.Unmarshal(encodedJSON, &decodedJSON); err != nil || len(decodedJSON.Nodes) != 3 || len(decodedJSON.Edges) != 2 || decodedJSON.Edges[0].Amount != 30 { // Check invalid JSON
+		t.Fatalf("expected JSON with 3 nodes and 2 edges, found %s (%v)", encodedJSON, err) // Panic
+	}
+
+	if _, err = graph.Encode(Format("svg")); err != ErrUnknownFormat { // Check unknown format encoded
+		t.Fatalf("expected %v, found %v", ErrUnknownFormat, err) // Panic
+	}
+}
+
+// TestParseFormat tests the functionality of the ParseFormat() and FormatFromPath() methods.
+func TestParseFormat(t *testing.T) {
+	for path, expected := range map[string]Format{"flow.gv": FormatDOT, "flow.DOT": FormatDOT, "flow.graphml": FormatGraphML, "flow.json": FormatJSON} { // Iterate through paths
+		if format, err := FormatFromPath(path); err != nil || format != expected { // Check not detected
+			t.Fatalf("expected %s to be detected as %s, found %s (%v)", path, expected, format, err) // Panic
+		}
+	}
+
+	if _, err := ParseFormat("svg"); err == nil { // Check unknown format parsed
+		t.Fatal("expected an error for an unknown format") // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
+
+/* BEGIN INTERNAL METHODS */
+
+// testChains initializes the chains of a network in which fixtures.Sender sends fixtures.Recipient 10 and then 20, and
+// fixtures.Recipient sends fixtures.ThirdParty 5, along with the time of the first transfer.
+func testChains() ([]*types.Chain, time.Time) {
+	sender := fixtures.Address(fixtures.Sender)         // Parse sender
+	recipient := fixtures.Address(fixtures.Recipient)   // Parse recipient
+	thirdParty := fixtures.Address(fixtures.ThirdParty) // Parse third party
+
+	start := fixtures.Start // Get first transfer time
+
+	genesis := fixtures.Transaction("genesis", nil, &sender, 1000, start.Add(-time.Hour))        // Init genesis transaction
+	first := fixtures.Transaction("first", &sender, &recipient, 10, start)                       // Init first transfer
+	second := fixtures.Transaction("second", &sender, &recipient, 20, start.Add(time.Hour))      // Init second transfer
+	onward := fixtures.Transaction("onward", &recipient, &thirdParty, 5, start.Add(2*time.Hour)) // Init onward transfer
+
+	return []*types.Chain{
+		{Account: sender, Genesis: *genesis.Hash, Transactions: []*types.Transaction{genesis, first, second}}, // Sender chain
+		{Account: recipient, Transactions: []*types.Transaction{first, second, onward}},                       // Recipient chain
+		{Account: thirdParty, Transactions: []*types.Transaction{onward}},                                     // Third party chain
+	}, start // Return chains
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,761 @@
+// Package index defines a persistent, bolt-backed inverted index of the transactions in a blockmesh, along with helper methods for
+// keeping it up to date and using it to answer search queries.
+package index
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/query"
+)
+
+// CurrentVersion is the version of the index layout written by puppet. Indexes with other versions are rebuilt.
+const CurrentVersion = 1
+
+// Index represents an open search index.
+type Index struct {
+	db *bolt.DB // Index database
+}
+
+// Hit represents a transaction matching a search query.
+type Hit struct {
+	Chain       summercashCommon.Address // Address of the account whose chain holds the transaction
+	Transaction *types.Transaction       // Matching transaction
+}
+
+// Stats represents the work done by an index update.
+type Stats struct {
+	Chains       int // Number of chains in the index
+	Updated      int // Number of chains indexed during the update
+	Transactions int // Number of transactions indexed during the update
+
+	Rebuilt bool // Whether the index was rebuilt from scratch
+}
+
+// chainState represents the state of a chain file when it was last indexed.
+type chainState struct {
+	Size         int64  `json:"size"`         // File size
+	ModTime      int64  `json:"modTime"`      // File modification time (Unix nanoseconds)
+	Transactions int    `json:"transactions"` // Number of indexed transactions
+	LastHash     string `json:"lastHash"`     // Hash of the last indexed transaction
+}
+
+// chainFile represents a chain file in a chain directory.
+type chainFile struct {
+	address summercashCommon.Address // Chain account address
+	path    string                   // File path
+	info    os.FileInfo              // File info
+}
+
+// refs represents a set of transaction references.
+type refs map[string]bool
+
+var (
+	// ErrNoIndex is an error definition describing a data directory without a search index.
+	ErrNoIndex = errors.New("no search index has been built")
+
+	// metaBucket stores the index version.
+	metaBucket = []byte("meta")
+
+	// chainsBucket maps each chain account address to its chainState.
+	chainsBucket = []byte("chains")
+
+	// transactionsBucket maps each transaction reference to its JSON encoding.
+	transactionsBucket = []byte("transactions")
+
+	// hashBucket, senderBucket, and recipientBucket map lowercase hex values to transaction references.
+	hashBucket      = []byte("hash")
+	senderBucket    = []byte("sender")
+	recipientBucket = []byte("recipient")
+
+	// amountBucket maps order-preserving amount encodings to transaction references.
+	amountBucket = []byte("amount")
+
+	// payloadBucket maps each three-byte sequence in a payload to transaction references.
+	payloadBucket = []byte("payload")
+
+	// buckets are every bucket in an index.
+	buckets = [][]byte{metaBucket, chainsBucket, transactionsBucket, hashBucket, senderBucket, recipientBucket, amountBucket, payloadBucket}
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// Open opens the search index at a given path, creating it if it doesn't exist and readOnly is not set.
+func Open(path string, readOnly bool) (*Index, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) && readOnly { // Check no index
+		return nil, ErrNoIndex // Return error
+	}
+
+	if !readOnly { // Check may create
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil { // Create index dir
+			return nil, err // Return found error
+		}
+	}
+
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 5 * time.Second, ReadOnly: readOnly}) // Open DB with timeout
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &Index{db: db}, nil // Return index
+}
+
+// Close closes an index.
+func (index *Index) Close() error {
+	return index.db.Close() // Close DB
+}
+
+// Update indexes the transactions added to the chains in a given chain directory since the index was last updated.
+// If a chain has been removed or rewritten, or full is set, the index is rebuilt from scratch.
+func (index *Index) Update(chainDir string, full bool) (*Stats, error) {
+	files, err := readChainDir(chainDir) // Read chain files
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	stats := &Stats{Chains: len(files)} // Init stats
+
+	err = index.db.Update(func(tx *bolt.Tx) error {
+		states, err := readStates(tx) // Read chain states
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if full || version(tx) != CurrentVersion || len(removedChains(states, files)) > 0 { // Check must rebuild
+			if err := reset(tx); err != nil { // Clear index
+				return err // Return found error
+			}
+
+			states, stats.Rebuilt = map[string]*chainState{}, true // Reset states
+		}
+
+		for _, file := range files { // Iterate through chain files
+			state := states[file.address.String()] // Get indexed state
+
+			if state != nil && state.Size == file.info.Size() && state.ModTime == file.info.ModTime().UnixNano() { // Check unchanged
+				continue // Continue
+			}
+
+			transactions, err := readChainTransactions(file.path) // Read chain transactions
+
+			if err != nil { // Check for errors
+				return fmt.Errorf("%s: %s", file.path, err) // Return found error
+			}
+
+			if state == nil { // Check new chain
+				state = &chainState{} // Init state
+			}
+
+			if state.Transactions > len(transactions) || (state.Transactions > 0 && lastHash(transactions[:state.Transactions]) != state.LastHash) { // Check rewritten
+				return errRewritten // Rebuild
+			}
+
+			for i := state.Transactions; i < len(transactions); i++ { // Iterate through new transactions
+				if err := indexTransaction(tx, file.address, i, transactions[i]); err != nil { // Index transaction
+					return err // Return found error
+				}
+			}
+
+			stats.Updated++                                                                // Increment updated
+			stats.Transactions += len(transactions) - state.Transactions                   // Increment indexed
+			state.Size, state.ModTime = file.info.Size(), file.info.ModTime().UnixNano()   // Set file state
+			state.Transactions, state.LastHash = len(transactions), lastHash(transactions) // Set indexed state
+
+			if err := writeState(tx, file.address, state); err != nil { // Write state
+				return err // Return found error
+			}
+		}
+
+		return tx.Bucket(metaBucket).Put([]byte("version"), []byte{CurrentVersion}) // Write version
+	})
+
+	if err == errRewritten && !full { // Check chain rewritten
+		return index.Update(chainDir, true) // Rebuild
+	}
+
+	return stats, err // Return stats
+}
+
+// Fresh checks whether every chain in a given chain directory has been indexed since it was last modified,
+// and no indexed chain has been removed.
+func (index *Index) Fresh(chainDir string) (bool, error) {
+	files, err := readChainDir(chainDir) // Read chain files
+
+	if err != nil { // Check for errors
+		return false, err // Return found error
+	}
+
+	fresh := false // Init fresh buffer
+
+	err = index.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(chainsBucket) == nil || version(tx) != CurrentVersion { // Check not built
+			return nil // Not fresh
+		}
+
+		states, err := readStates(tx) // Read chain states
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if len(states) != len(files) { // Check chains added or removed
+			return nil // Not fresh
+		}
+
+		for _, file := range files { // Iterate through chain files
+			state := states[file.address.String()] // Get indexed state
+
+			if state == nil || state.Size != file.info.Size() || state.ModTime != file.info.ModTime().UnixNano() { // Check changed
+				return nil // Not fresh
+			}
+		}
+
+		fresh = true // Set fresh
+
+		return nil // No error occurred, return nil
+	})
+
+	return fresh, err // Return fresh
+}
+
+// Search finds the indexed transactions matching a given query, in the chains of a given set of accounts (every chain if empty).
+// Hits are ordered by chain, and then by their position in the chain.
+func (index *Index) Search(searchQuery query.Query, chains []summercashCommon.Address) ([]*Hit, error) {
+	var hits []*Hit // Init hits buffer
+
+	allowed := make(map[string]bool) // Init allowed chains buffer
+
+	for _, chain := range chains { // Iterate through chains
+		allowed[string(chain[:])] = true // Set allowed
+	}
+
+	err := index.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(transactionsBucket) == nil { // Check not built
+			return ErrNoIndex // Return error
+		}
+
+		candidates, narrowed := plan(tx, searchQuery) // Find candidates
+
+		var keys []string // Init candidate keys buffer
+
+		if narrowed { // Check narrowed
+			for key := range candidates { // Iterate through candidates
+				keys = append(keys, key) // Append key
+			}
+
+			sort.Strings(keys) // Sort by chain and position
+		}
+
+		visit := func(key []byte, value []byte) error {
+			var chain summercashCommon.Address // Init chain buffer
+
+			copy(chain[:], key[:summercashCommon.AddressLength]) // Get chain
+
+			if len(allowed) > 0 && !allowed[string(chain[:])] { // Check chain not searched
+				return nil // Skip
+			}
+
+			transaction, err := decodeTransaction(value) // Decode transaction
+
+			if err != nil { // Check for errors
+				return err // Return found error
+			}
+
+			if transaction.Hash != nil && searchQuery.Match(chain, transaction) { // Check match
+				hits = append(hits, &Hit{Chain: chain, Transaction: transaction}) // Append hit
+			}
+
+			return nil // No error occurred, return nil
+		}
+
+		if !narrowed { // Check must scan every transaction
+			return tx.Bucket(transactionsBucket).ForEach(visit) // Visit every transaction
+		}
+
+		for _, key := range keys { // Iterate through candidates
+			if err := visit([]byte(key), tx.Bucket(transactionsBucket).Get([]byte(key))); err != nil { // Visit candidate
+				return err // Return found error
+			}
+		}
+
+		return nil // No error occurred, return nil
+	})
+
+	return hits, err // Return hits
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// errRewritten signals that an indexed chain has been rewritten, and the index must be rebuilt.
+var errRewritten = errors.New("chain rewritten")
+
+// readChainDir lists the chain files in a given chain directory.
+func readChainDir(chainDir string) ([]*chainFile, error) {
+	infos, err := ioutil.ReadDir(chainDir) // Read chain dir
+
+	if os.IsNotExist(err) { // Check no chains
+		return nil, nil // No chain files
+	} else if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	var files []*chainFile // Init files buffer
+
+	for _, info := range infos { // Iterate through files
+		name := strings.TrimSuffix(strings.TrimPrefix(info.Name(), "chain_"), ".json") // Get address
+
+		if info.IsDir() || !strings.HasPrefix(info.Name(), "chain_") || len(name) < 2 { // Check not chain file
+			continue // Continue
+		}
+
+		address, err := summercashCommon.StringToAddress(name) // Parse address
+
+		if err != nil { // Check for errors
+			continue // Skip invalid chain file names
+		}
+
+		files = append(files, &chainFile{address: address, path: filepath.Join(chainDir, info.Name()), info: info}) // Append file
+	}
+
+	return files, nil // Return files
+}
+
+// readChainTransactions reads the JSON encoding of each transaction in a given chain file.
+func readChainTransactions(path string) ([]json.RawMessage, error) {
+	data, err := ioutil.ReadFile(path) // Read chain file
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	var chain struct {
+		Transactions []json.RawMessage `json:"transactions"` // Transactions
+	} // Init chain buffer
+
+	err = json.Unmarshal(data, &chain) // Decode chain
+
+	return chain.Transactions, err // Return transactions
+}
+
+// decodeTransaction decodes a transaction from the JSON encoding it has in a chain file.
+func decodeTransaction(b []byte) (*types.Transaction, error) {
+	transaction := &types.Transaction{} // Init transaction buffer
+
+	err := json.Unmarshal(b, transaction) // Decode transaction
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if transaction.Signature != nil && len(transaction.Signature.SerializedPublicKey) > 0 { // Check has public key
+		err = transaction.RecoverSafeEncoding() // Recover public key
+	}
+
+	return transaction, err // Return transaction
+}
+
+// lastHash gets the hash field of the last of a set of encoded transactions.
+func lastHash(transactions []json.RawMessage) string {
+	if len(transactions) == 0 { // Check no transactions
+		return "" // No hash
+	}
+
+	var last struct {
+		Hash json.RawMessage `json:"hash"` // Hash
+	} // Init last transaction buffer
+
+	json.Unmarshal(transactions[len(transactions)-1], &last) // Decode hash
+
+	return string(last.Hash) // Return hash
+}
+
+// indexTransaction indexes the transaction at a given position in the chain of a given account.
+func indexTransaction(tx *bolt.Tx, chain summercashCommon.Address, position int, encoded json.RawMessage) error {
+	ref := make([]byte, summercashCommon.AddressLength+8) // Init reference buffer
+
+	copy(ref, chain[:])                                                                // Set chain
+	binary.BigEndian.PutUint64(ref[summercashCommon.AddressLength:], uint64(position)) // Set position
+
+	if err := tx.Bucket(transactionsBucket).Put(ref, encoded); err != nil { // Store transaction
+		return err // Return found error
+	}
+
+	transaction, err := decodeTransaction(encoded) // Decode transaction
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	var postings [][2][]byte // Init postings buffer (bucket, value)
+
+	if transaction.Hash != nil { // Check has hash
+		postings = append(postings, [2][]byte{hashBucket, stringKey(transaction.Hash.String())}) // Post hash
+	}
+
+	if transaction.Sender != nil { // Check has sender
+		postings = append(postings, [2][]byte{senderBucket, stringKey(transaction.Sender.String())}) // Post sender
+	}
+
+	if transaction.Recipient != nil { // Check has recipient
+		postings = append(postings, [2][]byte{recipientBucket, stringKey(transaction.Recipient.String())}) // Post recipient
+	}
+
+	if transaction.Amount != nil { // Check has amount
+		postings = append(postings, [2][]byte{amountBucket, amountKey(transaction.Amount)}) // Post amount
+	}
+
+	seen := make(map[string]bool) // Init seen trigrams buffer
+
+	for i := 0; i+3 <= len(transaction.Payload); i++ { // Iterate through payload trigrams
+		if trigram := transaction.Payload[i : i+3]; !seen[string(trigram)] { // Check not posted
+			seen[string(trigram)] = true // Set seen
+
+			postings = append(postings, [2][]byte{payloadBucket, append([]byte{}, trigram...)}) // Post trigram
+		}
+	}
+
+	for _, posting := range postings { // Iterate through postings
+		if err := tx.Bucket(posting[0]).Put(append(posting[1], ref...), nil); err != nil { // Write posting
+			return err // Return found error
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// plan finds the references of the transactions that may match a query. If the index can't narrow the query down,
+// narrowed is false, and every transaction must be checked.
+func plan(tx *bolt.Tx, searchQuery query.Query) (candidates refs, narrowed bool) {
+	switch searchQuery := searchQuery.(type) {
+	case *query.And:
+		for _, operand := range searchQuery.Operands { // Iterate through operands
+			operandCandidates, operandNarrowed := plan(tx, operand) // Plan operand
+
+			switch {
+			case !operandNarrowed:
+				continue // Continue
+			case !narrowed:
+				candidates, narrowed = operandCandidates, true // Set candidates
+			default:
+				candidates = candidates.intersect(operandCandidates) // Intersect candidates
+			}
+		}
+
+		return candidates, narrowed // Return candidates
+	case *query.Or:
+		candidates = refs{} // Init candidates
+
+		for _, operand := range searchQuery.Operands { // Iterate through operands
+			operandCandidates, operandNarrowed := plan(tx, operand) // Plan operand
+
+			if !operandNarrowed { // Check operand can't be narrowed
+				return nil, false // Can't narrow
+			}
+
+			candidates.add(operandCandidates) // Add candidates
+		}
+
+		return candidates, true // Return candidates
+	case *query.Term:
+		return planTerm(tx, searchQuery) // Plan term
+	}
+
+	return nil, false // Can't narrow
+}
+
+// planTerm finds the references of the transactions that may match a term.
+func planTerm(tx *bolt.Tx, term *query.Term) (refs, bool) {
+	value := term.Value // Get value
+
+	switch {
+	case term.Field == query.FieldAny:
+		candidates := refs{} // Init candidates
+
+		for _, bucket := range [][]byte{hashBucket, senderBucket, recipientBucket} { // Iterate through exact match buckets
+			candidates.add(scanPrefix(tx, bucket, stringKey(value))) // Add candidates
+		}
+
+		if amount, ok := new(big.Float).SetString(value); ok { // Check numeric
+			candidates.add(scanAmounts(tx, query.OpEqual, amount)) // Add candidates
+		}
+
+		payloadCandidates, narrowed := planPayload(tx, value) // Plan payload
+
+		if !narrowed { // Check payload can't be narrowed
+			return nil, false // Can't narrow
+		}
+
+		candidates.add(payloadCandidates) // Add candidates
+
+		return candidates, true // Return candidates
+	case term.Operator != query.OpEqual && term.Field != query.FieldAmount && term.Field != query.FieldPayload:
+		return nil, false // Can't narrow
+	case term.Field == query.FieldHash:
+		return scanPrefix(tx, hashBucket, stringKey(value)), true // Return candidates
+	case term.Field == query.FieldSender:
+		return scanPrefix(tx, senderBucket, stringKey(value)), true // Return candidates
+	case term.Field == query.FieldRecipient:
+		return scanPrefix(tx, recipientBucket, stringKey(value)), true // Return candidates
+	case term.Field == query.FieldAddress:
+		candidates := scanPrefix(tx, senderBucket, stringKey(value)) // Get sender candidates
+
+		candidates.add(scanPrefix(tx, recipientBucket, stringKey(value))) // Add recipient candidates
+
+		return candidates, true // Return candidates
+	case term.Field == query.FieldChain:
+		address, err := summercashCommon.StringToAddress(value) // Parse address
+
+		if err != nil { // Check for errors
+			return nil, false // Can't narrow
+		}
+
+		candidates := refs{} // Init candidates
+
+		cursor := tx.Bucket(transactionsBucket).Cursor() // Init cursor
+
+		for key, _ := cursor.Seek(address[:]); key != nil && bytes.HasPrefix(key, address[:]); key, _ = cursor.Next() { // Iterate through chain transactions
+			candidates[string(key)] = true // Add candidate
+		}
+
+		return candidates, true // Return candidates
+	case term.Field == query.FieldAmount && term.Operator != query.OpNotEqual:
+		amount, ok := new(big.Float).SetString(value) // Parse amount
+
+		if !ok { // Check invalid amount
+			return nil, false // Can't narrow
+		}
+
+		return scanAmounts(tx, term.Operator, amount), true // Return candidates
+	case term.Field == query.FieldPayload && term.Operator != query.OpNotEqual:
+		return planPayload(tx, value) // Return candidates
+	}
+
+	return nil, false // Can't narrow
+}
+
+// planPayload finds the references of the transactions whose payloads may contain a given value.
+func planPayload(tx *bolt.Tx, value string) (refs, bool) {
+	if len(value) < 3 { // Check too short to have trigrams
+		return nil, false // Can't narrow
+	}
+
+	var candidates refs // Init candidates buffer
+
+	for i := 0; i+3 <= len(value); i++ { // Iterate through trigrams
+		trigramCandidates := scanPrefix(tx, payloadBucket, []byte(value[i:i+3])) // Find trigram candidates
+
+		if candidates == nil { // Check first trigram
+			candidates = trigramCandidates // Set candidates
+		} else {
+			candidates = candidates.intersect(trigramCandidates) // Intersect candidates
+		}
+	}
+
+	return candidates, true // Return candidates
+}
+
+// scanPrefix finds the references posted under a given value in a bucket.
+func scanPrefix(tx *bolt.Tx, bucket []byte, value []byte) refs {
+	candidates := refs{} // Init candidates
+
+	refLength := summercashCommon.AddressLength + 8 // Get reference length
+
+	cursor := tx.Bucket(bucket).Cursor() // Init cursor
+
+	for key, _ := cursor.Seek(value); key != nil && bytes.HasPrefix(key, value); key, _ = cursor.Next() { // Iterate through postings
+		if len(key) == len(value)+refLength { // Check posted under exactly this value
+			candidates[string(key[len(value):])] = true // Add candidate
+		}
+	}
+
+	return candidates // Return candidates
+}
+
+// scanAmounts finds the references of the transactions whose amounts may compare to a given amount with an operator.
+// Amounts are indexed as float64 values, so the scanned range is widened by one float64 step in each direction.
+func scanAmounts(tx *bolt.Tx, operator query.Operator, amount *big.Float) refs {
+	value, _ := amount.Float64() // Get float value
+
+	lower, upper := amountKeyFloat(math.Nextafter(value, math.Inf(-1))), amountKeyFloat(math.Nextafter(value, math.Inf(1))) // Get widened bounds
+
+	switch operator {
+	case query.OpGreater, query.OpGreaterEqual:
+		upper = nil // No upper bound
+	case query.OpLess, query.OpLessEqual:
+		lower = nil // No lower bound
+	}
+
+	candidates := refs{} // Init candidates
+
+	cursor := tx.Bucket(amountBucket).Cursor() // Init cursor
+
+	key, _ := cursor.First() // Get first posting
+
+	if lower != nil { // Check has lower bound
+		key, _ = cursor.Seek(lower) // Seek to lower bound
+	}
+
+	for ; key != nil && (upper == nil || bytes.Compare(key[:8], upper) <= 0); key, _ = cursor.Next() { // Iterate through postings in range
+		candidates[string(key[8:])] = true // Add candidate
+	}
+
+	return candidates // Return candidates
+}
+
+// stringKey gets the posting key of a hex value.
+func stringKey(value string) []byte {
+	return append([]byte(strings.ToLower(value)), 0) // Return lowercase, terminated value
+}
+
+// amountKey gets the order-preserving posting key of an amount.
+func amountKey(amount *big.Float) []byte {
+	value, _ := amount.Float64() // Get float value
+
+	return amountKeyFloat(value) // Return key
+}
+
+// amountKeyFloat gets the order-preserving posting key of a float64 value.
+func amountKeyFloat(value float64) []byte {
+	bits := math.Float64bits(value) // Get bits
+
+	if value >= 0 { // Check positive
+		bits ^= 1 << 63 // Flip sign bit
+	} else {
+		bits = ^bits // Flip every bit
+	}
+
+	key := make([]byte, 8) // Init key buffer
+
+	binary.BigEndian.PutUint64(key, bits) // Encode bits
+
+	return key // Return key
+}
+
+// add adds every reference in another set to a set.
+func (candidates refs) add(other refs) {
+	for ref := range other { // Iterate through references
+		candidates[ref] = true // Add reference
+	}
+}
+
+// intersect gets the references in both a set and another set.
+func (candidates refs) intersect(other refs) refs {
+	intersection := refs{} // Init intersection
+
+	for ref := range candidates { // Iterate through references
+		if other[ref] { // Check in both
+			intersection[ref] = true // Add reference
+		}
+	}
+
+	return intersection // Return intersection
+}
+
+// version gets the version of an index, or 0 if it hasn't been built.
+func version(tx *bolt.Tx) int {
+	bucket := tx.Bucket(metaBucket) // Get meta bucket
+
+	if bucket == nil { // Check not built
+		return 0 // No version
+	}
+
+	if value := bucket.Get([]byte("version")); len(value) == 1 { // Check has version
+		return int(value[0]) // Return version
+	}
+
+	return 0 // No version
+}
+
+// reset removes every entry from an index.
+func reset(tx *bolt.Tx) error {
+	for _, bucket := range buckets { // Iterate through buckets
+		if tx.Bucket(bucket) != nil { // Check exists
+			if err := tx.DeleteBucket(bucket); err != nil { // Delete bucket
+				return err // Return found error
+			}
+		}
+
+		if _, err := tx.CreateBucket(bucket); err != nil { // Create bucket
+			return err // Return found error
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// readStates reads the indexed state of every chain.
+func readStates(tx *bolt.Tx) (map[string]*chainState, error) {
+	states := make(map[string]*chainState) // Init states buffer
+
+	bucket := tx.Bucket(chainsBucket) // Get chains bucket
+
+	if bucket == nil { // Check not built
+		return states, nil // No states
+	}
+
+	err := bucket.ForEach(func(key []byte, value []byte) error {
+		state := &chainState{} // Init state buffer
+
+		if err := json.Unmarshal(value, state); err != nil { // Decode state
+			return err // Return found error
+		}
+
+		states[string(key)] = state // Set state
+
+		return nil // No error occurred, return nil
+	})
+
+	return states, err // Return states
+}
+
+// writeState writes the indexed state of the chain of a given account.
+func writeState(tx *bolt.Tx, chain summercashCommon.Address, state *chainState) error {
+	encoded, err := json.Marshal(state) // Encode state
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return tx.Bucket(chainsBucket).Put([]byte(chain.String()), encoded) // Write state
+}
+
+// removedChains gets the addresses of the indexed chains missing from a set of chain files.
+func removedChains(states map[string]*chainState, files []*chainFile) []string {
+	present := make(map[string]bool) // Init present chains buffer
+
+	for _, file := range files { // Iterate through files
+		present[file.address.String()] = true // Set present
+	}
+
+	var removed []string // Init removed buffer
+
+	for address := range states { // Iterate through indexed chains
+		if !present[address] { // Check removed
+			removed = append(removed, address) // Append address
+		}
+	}
+
+	return removed // Return removed
+}
+
+/* END INTERNAL METHODS */
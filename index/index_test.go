@@ -0,0 +1,191 @@
+// Package index defines a persistent, bolt-backed inverted index of the transactions in a blockmesh, along with helper methods for
+// keeping it up to date and using it to answer search queries.
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/internal/fixtures"
+	"github.com/SummerCash/puppet/query"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestUpdate tests the functionality of the Update() and Fresh() methods.
+func TestUpdate(t *testing.T) {
+	chainDir, searchIndex := openTestIndex(t) // Open index
+
+	defer os.RemoveAll(filepath.Dir(chainDir)) // Remove test dir
+	defer searchIndex.Close()                  // Close index
+
+	writeTestChain(t, chainDir, fixtures.Sender, testTransactions(3)) // Write chain
+
+	stats, err := searchIndex.Update(chainDir, false) // Update index
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if !stats.Rebuilt || stats.Transactions != 3 { // Check invalid stats
+		t.Fatalf("expected a rebuild of 3 transactions, found %+v", stats) // Panic
+	}
+
+	writeTestChain(t, chainDir, fixtures.Sender, testTransactions(5)) // Append transactions
+
+	if fresh, _ := searchIndex.Fresh(chainDir); fresh { // Check fresh
+		t.Fatal("expected index to be out of date after appending transactions") // Panic
+	}
+
+	if stats, err = searchIndex.Update(chainDir, false); err != nil || stats.Rebuilt || stats.Transactions != 2 { // Check not incremental
+		t.Fatalf("expected 2 new transactions, found %+v (%v)", stats, err) // Panic
+	}
+
+	writeTestChain(t, chainDir, fixtures.Sender, testTransactions(1)) // Rewrite chain
+
+	if stats, err = searchIndex.Update(chainDir, false); err != nil || !stats.Rebuilt || stats.Transactions != 1 { // Check not rebuilt
+		t.Fatalf("expected a rebuild of 1 transaction, found %+v (%v)", stats, err) // Panic
+	}
+
+	if fresh, err := searchIndex.Fresh(chainDir); err != nil || !fresh { // Check not fresh
+		t.Fatalf("expected index to be up to date (%v)", err) // Panic
+	}
+}
+
+// TestSearch tests the functionality of the Search() method.
+func TestSearch(t *testing.T) {
+	chainDir, searchIndex := openTestIndex(t) // Open index
+
+	defer os.RemoveAll(filepath.Dir(chainDir)) // Remove test dir
+	defer searchIndex.Close()                  // Close index
+
+	transactions := testTransactions(6) // Init transactions
+
+	writeTestChain(t, chainDir, fixtures.Sender, transactions)        // Write sender chain
+	writeTestChain(t, chainDir, fixtures.Recipient, transactions[:2]) // Write recipient chain
+
+	if _, err := searchIndex.Update(chainDir, false); err != nil { // Update index
+		t.Fatal(err) // Panic
+	}
+
+	sender := fixtures.Address(fixtures.Sender) // Parse sender
+
+	for s, expected := range map[string]int{
+		`amount>=3`:                                        3,
+		`amount:2 OR amount<1`:                             3,
+		`payload~"invoice 4"`:                              1,
+		`payload~voice amount<=2`:                          5,
+		`recipient:` + fixtures.Recipient:                  8,
+		`chain:` + fixtures.Sender + ` NOT payload~"ce 1"`: 5,
+		transactions[3].Hash.String():                      1,
+		`3`:                                                1,
+		`in`:                                               8,
+	} { // Iterate through queries
+		searchQuery, err := query.Parse(s) // Parse query
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		hits, err := searchIndex.Search(searchQuery, nil) // Search index
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		if len(hits) != expected { // Check invalid hits
+			t.Fatalf("expected %d hits for %s, found %d", expected, s, len(hits)) // Panic
+		}
+
+		for _, hit := range hits { // Iterate through hits
+			if !searchQuery.Match(hit.Chain, hit.Transaction) { // Check not match
+				t.Fatalf("hit %s does not match %s", hit.Transaction.Hash.String(), s) // Panic
+			}
+		}
+	}
+
+	searchQuery, _ := query.Parse(`payload~invoice`) // Parse query
+
+	hits, err := searchIndex.Search(searchQuery, []summercashCommon.Address{sender}) // Search sender chain
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if len(hits) != 6 || hits[0].Transaction.AccountNonce != 0 || hits[5].Transaction.AccountNonce != 5 { // Check not ordered by position
+		t.Fatalf("expected the 6 sender chain transactions in order, found %d", len(hits)) // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
+
+// openTestIndex creates a temporary chain dir and opens an index next to it.
+func openTestIndex(t *testing.T) (string, *Index) {
+	dir, err := ioutil.TempDir("", "puppet_index") // Create test dir
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	chainDir := filepath.Join(dir, "chain") // Get chain dir
+
+	if err = os.MkdirAll(chainDir, 0755); err != nil { // Create chain dir
+		t.Fatal(err) // Panic
+	}
+
+	searchIndex, err := Open(filepath.Join(dir, "index", "search.db"), false) // Open index
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	return chainDir, searchIndex // Return chain dir and index
+}
+
+// testTransactions creates a given number of unsigned transactions from fixtures.Sender to fixtures.Recipient, each with
+// an amount equal to its nonce and the payload "invoice <nonce>".
+func testTransactions(count int) []*types.Transaction {
+	sender := fixtures.Address(fixtures.Sender)       // Parse sender
+	recipient := fixtures.Address(fixtures.Recipient) // Parse recipient
+
+	transactions := make([]*types.Transaction, count) // Init transactions buffer
+
+	for i := range transactions { // Iterate through transactions
+		payload := fmt.Sprintf("invoice %d", i) // Get payload
+
+		transactions[i] = fixtures.Transaction(payload, &sender, &recipient, float64(i), time.Unix(int64(i), 0).UTC()) // Init transaction
+
+		transactions[i].AccountNonce, transactions[i].Payload = uint64(i), []byte(payload) // Set nonce, payload
+	}
+
+	return transactions // Return transactions
+}
+
+// writeTestChain writes a chain file holding a given set of transactions, and moves its modification time forward
+// so that each write is detected.
+func writeTestChain(t *testing.T, chainDir string, address string, transactions []*types.Transaction) {
+	encoded, err := json.Marshal(map[string]interface{}{"transactions": transactions}) // Encode chain
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	path := filepath.Join(chainDir, fmt.Sprintf("chain_%s.json", address)) // Get chain file
+
+	if err = ioutil.WriteFile(path, encoded, 0644); err != nil { // Write chain
+		t.Fatal(err) // Panic
+	}
+
+	modTime := time.Now().Add(time.Duration(len(transactions)) * time.Second) // Get unique modification time
+
+	if err = os.Chtimes(path, modTime, modTime); err != nil { // Set modification time
+		t.Fatal(err) // Panic
+	}
+}
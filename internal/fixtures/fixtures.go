@@ -0,0 +1,56 @@
+// Package fixtures defines the addresses, times, and transactions shared by the tests of puppet's packages.
+package fixtures
+
+import (
+	"math/big"
+	"time"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/crypto"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+const (
+	// Sender is a valid address used as a transaction sender, chain, or genesis account in tests.
+	Sender = "0x040000fe1cb145827b9833a8b3668190d6df"
+
+	// Recipient is a valid address used as a transaction recipient or chain in tests.
+	Recipient = "0x0401351b42bea39ac6f38bef70bf2f7ae5e4"
+
+	// ThirdParty is a valid address only reachable from Sender through Recipient in tests.
+	ThirdParty = "0x0401a6d99270788014429e092dd9f1f3ee9c"
+)
+
+var (
+	// Start is the time of the first transfer in tests.
+	Start = time.Date(2019, time.March, 14, 0, 0, 0, 0, time.UTC)
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// Address parses a given address, panicking if it's invalid.
+func Address(address string) summercashCommon.Address {
+	parsed, err := summercashCommon.StringToAddress(address) // Parse address
+
+	if err != nil { // Check for errors
+		panic(err) // Panic
+	}
+
+	return parsed // Return address
+}
+
+// Transaction initializes a transaction between two accounts, identified by the hash of a given seed. A nil sender
+// makes a minting transaction.
+func Transaction(seed string, sender *summercashCommon.Address, recipient *summercashCommon.Address, amount float64, at time.Time) *types.Transaction {
+	hash := summercashCommon.NewHash(crypto.Sha3([]byte(seed))) // Get hash
+
+	return &types.Transaction{
+		Sender:    sender,               // Set sender
+		Recipient: recipient,            // Set recipient
+		Amount:    big.NewFloat(amount), // Set amount
+		Timestamp: at,                   // Set timestamp
+		Hash:      &hash,                // Set hash
+	} // Return transaction
+}
+
+/* END EXPORTED METHODS */
@@ -0,0 +1,263 @@
+// Package keystore defines a passphrase-encrypted keystore format, used to store credentials (e.g. the faucet's wallet password) at rest.
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// CurrentVersion is the version of the keystore envelope written by puppet.
+	CurrentVersion = 1
+
+	// KDFScrypt is the name of the scrypt key derivation function.
+	KDFScrypt = "scrypt"
+
+	// CipherAESGCM is the name of the AES-256-GCM cipher.
+	CipherAESGCM = "aes-256-gcm"
+)
+
+var (
+	// ErrEmptyPassphrase is an error definition describing an attempt to encrypt a keystore with an empty passphrase.
+	ErrEmptyPassphrase = errors.New("keystore passphrase must not be empty")
+
+	// ErrInvalidPassphrase is an error definition describing a keystore that could not be decrypted with a given passphrase.
+	ErrInvalidPassphrase = errors.New("invalid keystore passphrase")
+
+	// ErrUnsupportedKeystore is an error definition describing a keystore envelope with an unknown version, KDF, or cipher.
+	ErrUnsupportedKeystore = errors.New("unsupported keystore")
+
+	// DefaultScryptParams are the scrypt parameters used to encrypt new keystores.
+	DefaultScryptParams = ScryptParams{N: 1 << 15, R: 8, P: 1, KeyLength: 32}
+
+	// MaxScryptParams are the largest scrypt parameters a keystore may be decrypted with, such that a crafted keystore
+	// can't make key derivation exhaust the memory or CPU of the machine decrypting it.
+	MaxScryptParams = ScryptParams{N: 1 << 20, R: 32, P: 16, KeyLength: 32}
+)
+
+// ScryptParams represents the parameters of the scrypt key derivation function.
+type ScryptParams struct {
+	N         int    `json:"n"`     // CPU/memory cost
+	R         int    `json:"r"`     // Block size
+	P         int    `json:"p"`     // Parallelization
+	KeyLength int    `json:"dklen"` // Derived key length
+	Salt      string `json:"salt"`  // Hex-encoded salt
+}
+
+// Envelope represents a versioned, passphrase-encrypted keystore.
+type Envelope struct {
+	Version uint `json:"version"` // Envelope version
+
+	KDF       string       `json:"kdf"`       // Key derivation function
+	KDFParams ScryptParams `json:"kdfparams"` // Key derivation params
+
+	Cipher     string `json:"cipher"`     // Cipher
+	Nonce      string `json:"nonce"`      // Hex-encoded nonce
+	Ciphertext string `json:"ciphertext"` // Hex-encoded ciphertext
+}
+
+/* BEGIN EXPORTED METHODS */
+
+// Encrypt encrypts a given plaintext with a passphrase, authenticating the envelope's header (its version, KDF, KDF
+// params, cipher, and nonce) as additional data. The salt and nonce are always read from crypto/rand, even
+// when the rest of a network is derived from a seed, so that no two keystores share a nonce.
+func Encrypt(plaintext []byte, passphrase string) (*Envelope, error) {
+	if passphrase == "" { // Check empty passphrase
+		return nil, ErrEmptyPassphrase // Return error
+	}
+
+	params := DefaultScryptParams // Get params
+
+	salt := make([]byte, 32) // Init salt buffer
+
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil { // Read salt
+		return nil, err // Return found error
+	}
+
+	params.Salt = hex.EncodeToString(salt) // Set salt
+
+	aead, err := params.cipher(passphrase) // Derive cipher
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	nonce := make([]byte, aead.NonceSize()) // Init nonce buffer
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil { // Read nonce
+		return nil, err // Return found error
+	}
+
+	envelope := &Envelope{
+		Version:   CurrentVersion,            // Set version
+		KDF:       KDFScrypt,                 // Set KDF
+		KDFParams: params,                    // Set KDF params
+		Cipher:    CipherAESGCM,              // Set cipher
+		Nonce:     hex.EncodeToString(nonce), // Set nonce
+	} // Init envelope
+
+	header, err := envelope.header() // Get header
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	envelope.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, plaintext, header)) // Set ciphertext
+
+	return envelope, nil // Return envelope
+}
+
+// Decrypt decrypts a given envelope with a passphrase. Decryption fails if any field of the envelope's header has been
+// tampered with, as the header is authenticated along with the ciphertext.
+func (envelope *Envelope) Decrypt(passphrase string) ([]byte, error) {
+	if envelope.Version != CurrentVersion || envelope.KDF != KDFScrypt || envelope.Cipher != CipherAESGCM { // Check unsupported envelope
+		return nil, fmt.Errorf("%s: version %d, kdf %q, cipher %q", ErrUnsupportedKeystore, envelope.Version, envelope.KDF, envelope.Cipher) // Return error
+	}
+
+	aead, err := envelope.KDFParams.cipher(passphrase) // Derive cipher
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	nonce, err := hex.DecodeString(envelope.Nonce) // Decode nonce
+
+	if err != nil || len(nonce) != aead.NonceSize() { // Check invalid nonce
+		return nil, fmt.Errorf("%s: invalid nonce", ErrUnsupportedKeystore) // Return error
+	}
+
+	ciphertext, err := hex.DecodeString(envelope.Ciphertext) // Decode ciphertext
+
+	if err != nil { // Check for errors
+		return nil, fmt.Errorf("%s: invalid ciphertext", ErrUnsupportedKeystore) // Return error
+	}
+
+	header, err := envelope.header() // Get header
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, header) // Decrypt
+
+	if err != nil { // Check for errors
+		return nil, ErrInvalidPassphrase // Return invalid passphrase
+	}
+
+	return plaintext, nil // Return plaintext
+}
+
+// WriteToFile writes a given envelope to a file readable only by its owner. The envelope is written to a new temporary
+// file next to the given path, which then replaces any existing file, such that an existing file's permissions are
+// never inherited.
+func (envelope *Envelope) WriteToFile(path string) error {
+	marshaled, err := json.MarshalIndent(envelope, "", "  ") // Marshal envelope
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp-") // Create temporary file, readable only by its owner
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	_, err = file.Write(marshaled) // Write envelope
+
+	if closeErr := file.Close(); err == nil { // Close file
+		err = closeErr // Set error
+	}
+
+	if err == nil { // Check written
+		err = os.Rename(file.Name(), path) // Move into place
+	}
+
+	if err != nil { // Check for errors
+		os.Remove(file.Name()) // Remove temporary file
+
+		return err // Return found error
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// ReadFromFile reads an envelope from a given file.
+func ReadFromFile(path string) (*Envelope, error) {
+	data, err := ioutil.ReadFile(path) // Read file
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	envelope := &Envelope{} // Init envelope buffer
+
+	err = json.Unmarshal(data, envelope) // Unmarshal envelope
+
+	if err != nil { // Check for errors
+		return nil, fmt.Errorf("%s: %s", ErrUnsupportedKeystore, err) // Return error
+	}
+
+	return envelope, nil // Return envelope
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// header marshals the fields of an envelope that are authenticated as additional data: every field except the ciphertext.
+func (envelope *Envelope) header() ([]byte, error) {
+	header := *envelope // Copy envelope
+
+	header.Ciphertext = "" // Leave out ciphertext
+
+	return json.Marshal(header) // Return header
+}
+
+// cipher derives an AES-256-GCM cipher from a given passphrase, rejecting scrypt params outside of MaxScryptParams.
+func (params ScryptParams) cipher(passphrase string) (cipher.AEAD, error) {
+	if params.N < 2 || params.N > MaxScryptParams.N || params.N&(params.N-1) != 0 { // Check invalid CPU/memory cost
+		return nil, fmt.Errorf("%s: scrypt n must be a power of 2 between 2 and %d, found %d", ErrUnsupportedKeystore, MaxScryptParams.N, params.N) // Return error
+	}
+
+	if params.R < 1 || params.R > MaxScryptParams.R || params.P < 1 || params.P > MaxScryptParams.P { // Check invalid block size, parallelization
+		return nil, fmt.Errorf("%s: scrypt r and p must be between 1 and %d and %d, found %d and %d", ErrUnsupportedKeystore, MaxScryptParams.R, MaxScryptParams.P, params.R, params.P) // Return error
+	}
+
+	if params.KeyLength != MaxScryptParams.KeyLength { // Check not an AES-256 key
+		return nil, fmt.Errorf("%s: scrypt dklen must be %d, found %d", ErrUnsupportedKeystore, MaxScryptParams.KeyLength, params.KeyLength) // Return error
+	}
+
+	salt, err := hex.DecodeString(params.Salt) // Decode salt
+
+	if err != nil { // Check for errors
+		return nil, fmt.Errorf("%s: invalid salt", ErrUnsupportedKeystore) // Return error
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.KeyLength) // Derive key
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	block, err := aes.NewCipher(key) // Init block cipher
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return cipher.NewGCM(block) // Return AEAD
+}
+
+/* END INTERNAL METHODS */
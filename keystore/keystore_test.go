@@ -0,0 +1,172 @@
+// Package keystore defines a passphrase-encrypted keystore format, used to store credentials (e.g. the faucet's wallet password) at rest.
+package keystore
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestEncrypt tests the functionality of the Encrypt() and Decrypt() methods.
+func TestEncrypt(t *testing.T) {
+	envelope, err := Encrypt([]byte("credential"), "passphrase") // Encrypt credential
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	plaintext, err := envelope.Decrypt("passphrase") // Decrypt credential
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if string(plaintext) != "credential" { // Check invalid plaintext
+		t.Fatalf("expected credential, found %s", plaintext) // Panic
+	}
+
+	if _, err = envelope.Decrypt("wrong"); err != ErrInvalidPassphrase { // Check wrong passphrase accepted
+		t.Fatalf("expected invalid passphrase error, found %v", err) // Panic
+	}
+
+	if _, err = Encrypt([]byte("credential"), ""); err != ErrEmptyPassphrase { // Check empty passphrase accepted
+		t.Fatal("empty passphrases must be rejected") // Panic
+	}
+}
+
+// TestEncryptRandomness tests that the Encrypt() method never reuses a salt or nonce.
+func TestEncryptRandomness(t *testing.T) {
+	first, err := Encrypt([]byte("credential"), "passphrase") // Encrypt credential
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	second, err := Encrypt([]byte("credential"), "passphrase") // Encrypt credential again
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if first.KDFParams.Salt == second.KDFParams.Salt || first.Nonce == second.Nonce { // Check salt or nonce reused
+		t.Fatal("expected a fresh salt and nonce for every keystore") // Panic
+	}
+}
+
+// TestDecryptHeader tests that the Decrypt() method authenticates the header of an envelope.
+func TestDecryptHeader(t *testing.T) {
+	envelope, err := Encrypt([]byte("credential"), "passphrase") // Encrypt credential
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	aead, err := envelope.KDFParams.cipher("passphrase") // Derive cipher
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	nonce, _ := hex.DecodeString(envelope.Nonce)           // Decode nonce
+	ciphertext, _ := hex.DecodeString(envelope.Ciphertext) // Decode ciphertext
+
+	if _, err = aead.Open(nil, nonce, ciphertext, nil); err == nil { // Check header not authenticated
+		t.Fatal("expected the header to be authenticated as additional data") // Panic
+	}
+
+	envelope.Version = CurrentVersion + 1 // Tamper with version
+
+	if _, err = envelope.Decrypt("passphrase"); err == nil { // Check tampered envelope accepted
+		t.Fatal("expected a tampered envelope to be rejected") // Panic
+	}
+}
+
+// TestDecryptScryptParams tests that the Decrypt() method rejects scrypt params outside of MaxScryptParams before
+// deriving a key.
+func TestDecryptScryptParams(t *testing.T) {
+	envelope, err := Encrypt([]byte("credential"), "passphrase") // Encrypt credential
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	for _, params := range []ScryptParams{
+		{N: 1 << 30, R: 8, P: 1, KeyLength: 32},       // Too much memory
+		{N: 3 << 10, R: 8, P: 1, KeyLength: 32},       // Not a power of 2
+		{N: 1 << 15, R: 1 << 20, P: 1, KeyLength: 32}, // Too large a block size
+		{N: 1 << 15, R: 8, P: 0, KeyLength: 32},       // No parallelization
+		{N: 1 << 15, R: 8, P: 1, KeyLength: 1 << 30},  // Too long a key
+	} { // Iterate through invalid params
+		tampered := *envelope // Copy envelope
+
+		params.Salt = envelope.KDFParams.Salt // Keep salt
+
+		tampered.KDFParams = params // Set params
+
+		if _, err := tampered.Decrypt("passphrase"); err == nil || !strings.HasPrefix(err.Error(), ErrUnsupportedKeystore.Error()) { // Check params accepted
+			t.Fatalf("expected unsupported keystore error for %+v, found %v", params, err) // Panic
+		}
+	}
+}
+
+// TestWriteToFile tests the functionality of the WriteToFile() and ReadFromFile() methods.
+func TestWriteToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore") // Make temp dir
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	defer os.RemoveAll(dir) // Remove temp dir
+
+	envelope, err := Encrypt([]byte("credential"), "passphrase") // Encrypt credential
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	path := filepath.Join(dir, "keystore.json") // Get keystore path
+
+	err = ioutil.WriteFile(path, []byte("{}"), 0644) // Write existing file, readable by others
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	err = envelope.WriteToFile(path) // Write keystore
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	info, err := os.Stat(path) // Get file info
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if info.Mode().Perm() != 0600 { // Check readable by others
+		t.Fatalf("expected mode 0600, found %o", info.Mode().Perm()) // Panic
+	}
+
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 { // Check temporary file left behind
+		t.Fatalf("expected only the keystore in %s, found %d files", dir, len(files)) // Panic
+	}
+
+	read, err := ReadFromFile(path) // Read keystore
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if plaintext, err := read.Decrypt("passphrase"); err != nil || string(plaintext) != "credential" { // Check invalid plaintext
+		t.Fatalf("keystore must survive a round trip: %v", err) // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
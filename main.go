@@ -12,9 +12,20 @@ import (
 func main() {
 	app := cli.NewCLI() // Initialize CLI app
 
-	app.SetupCreateCommand()   // Setup create command
-	app.SetupSearchCommand()   // Setup search command
-	app.SetupHardforkCommand() // Setup hardfork command
+	app.SetupCreateCommand()      // Setup create command
+	app.SetupSearchCommand()      // Setup search command
+	app.SetupHardforkCommand()    // Setup hardfork command
+	app.SetupGenesisCommand()     // Setup genesis command
+	app.SetupFaucetCommand()      // Setup faucet command
+	app.SetupAccountsCommand()    // Setup accounts command
+	app.SetupVestingCommand()     // Setup vesting command
+	app.SetupIndexCommand()       // Setup index command
+	app.SetupInspectCommand()     // Setup inspect command
+	app.SetupStatsCommand()       // Setup stats command
+	app.SetupVerifyCommand()      // Setup verify command
+	app.SetupGraphCommand()       // Setup graph command
+	app.SetupExportCommand()      // Setup export command
+	app.SetupForkNetworkCommand() // Setup fork-network command
 
 	err := app.App.Run(os.Args) // Initialize CLI app
 
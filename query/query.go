@@ -0,0 +1,690 @@
+// Package query defines the puppet search query language, along with helper methods for parsing queries and evaluating them against transactions.
+//
+// A query is made up of terms, combined with AND, OR, NOT, and parentheses. Terms placed next to each other are
+// combined with AND. A term is either a field comparison (e.g. sender:0x..., amount>100, payload~"invoice"), or a
+// bare word, which matches a transaction with that hash, sender, or recipient, a payload containing it, or an equal amount.
+package query
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+// Field represents a transaction field a term compares against.
+type Field string
+
+// Operator represents the comparison a term makes.
+type Operator string
+
+// Query represents a parsed search query.
+type Query interface {
+	// Match checks whether a transaction in the chain of a given account matches the query.
+	Match(chain summercashCommon.Address, transaction *types.Transaction) bool
+
+	// String formats the query, such that it parses back to an equivalent query.
+	String() string
+}
+
+// Term represents a single comparison against a transaction field. Terms without a field are bare words.
+type Term struct {
+	Field    Field    // Compared field
+	Operator Operator // Comparison
+	Value    string   // Compared value
+
+	number *big.Float // Parsed numeric value
+}
+
+// And represents a query matching transactions matched by every one of its operands.
+type And struct {
+	Operands []Query // Operands
+}
+
+// Or represents a query matching transactions matched by any of its operands.
+type Or struct {
+	Operands []Query // Operands
+}
+
+// Not represents a query matching transactions not matched by its operand.
+type Not struct {
+	Operand Query // Operand
+}
+
+// token represents a lexical token in a query.
+type token struct {
+	text   string // Unquoted text
+	offset int    // Offset of the token in the query
+
+	quoteStart int // Offset in the text at which the first quoted section starts, or -1
+
+	paren byte // Parenthesis, if the token is one
+}
+
+// parser represents the state of a query being parsed.
+type parser struct {
+	tokens []*token // Tokens
+	next   int      // Index of the next token
+}
+
+const (
+	// FieldAny is the field of a bare word.
+	FieldAny Field = ""
+
+	// FieldHash is the transaction hash field.
+	FieldHash Field = "hash"
+
+	// FieldSender is the transaction sender field.
+	FieldSender Field = "sender"
+
+	// FieldRecipient is the transaction recipient field.
+	FieldRecipient Field = "recipient"
+
+	// FieldAddress matches either the transaction sender or recipient.
+	FieldAddress Field = "address"
+
+	// FieldChain is the address of the account whose chain the transaction is in.
+	FieldChain Field = "chain"
+
+	// FieldAmount is the transaction amount field.
+	FieldAmount Field = "amount"
+
+	// FieldNonce is the transaction account nonce field.
+	FieldNonce Field = "nonce"
+
+	// FieldPayload is the transaction payload field.
+	FieldPayload Field = "payload"
+)
+
+const (
+	// OpEqual is the exact match operator.
+	OpEqual Operator = ":"
+
+	// OpNotEqual is the negated exact match operator.
+	OpNotEqual Operator = "!="
+
+	// OpContains is the substring match operator.
+	OpContains Operator = "~"
+
+	// OpGreater is the greater than operator.
+	OpGreater Operator = ">"
+
+	// OpGreaterEqual is the greater than or equal to operator.
+	OpGreaterEqual Operator = ">="
+
+	// OpLess is the less than operator.
+	OpLess Operator = "<"
+
+	// OpLessEqual is the less than or equal to operator.
+	OpLessEqual Operator = "<="
+)
+
+var (
+	// ErrEmptyQuery is an error definition describing a query without any terms.
+	ErrEmptyQuery = errors.New("empty query")
+
+	// ErrUnknownField is an error definition describing a term comparing against an unknown field.
+	ErrUnknownField = errors.New("unknown field")
+
+	// ErrInvalidOperator is an error definition describing a term using an operator its field doesn't support.
+	ErrInvalidOperator = errors.New("invalid operator")
+
+	// ErrInvalidValue is an error definition describing a term comparing against a value its field can't hold.
+	ErrInvalidValue = errors.New("invalid value")
+
+	// ErrUnexpectedToken is an error definition describing a token that doesn't fit the query grammar.
+	ErrUnexpectedToken = errors.New("unexpected token")
+
+	// ErrUnterminatedString is an error definition describing a quoted string without a closing quote.
+	ErrUnterminatedString = errors.New("unterminated string")
+
+	// Fields are the fields a term may compare against.
+	Fields = []Field{FieldHash, FieldSender, FieldRecipient, FieldAddress, FieldChain, FieldAmount, FieldNonce, FieldPayload}
+
+	// operators are the operators supported by each field.
+	operators = map[Field][]Operator{
+		FieldHash:      {OpEqual, OpNotEqual, OpContains},
+		FieldSender:    {OpEqual, OpNotEqual, OpContains},
+		FieldRecipient: {OpEqual, OpNotEqual, OpContains},
+		FieldAddress:   {OpEqual, OpNotEqual, OpContains},
+		FieldChain:     {OpEqual, OpNotEqual, OpContains},
+		FieldAmount:    {OpEqual, OpNotEqual, OpGreater, OpGreaterEqual, OpLess, OpLessEqual},
+		FieldNonce:     {OpEqual, OpNotEqual, OpGreater, OpGreaterEqual, OpLess, OpLessEqual},
+		FieldPayload:   {OpEqual, OpNotEqual, OpContains},
+	}
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// Parse parses a search query.
+func Parse(s string) (Query, error) {
+	tokens, err := lex(s) // Split query into tokens
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if len(tokens) == 0 { // Check no tokens
+		return nil, ErrEmptyQuery // Return error
+	}
+
+	p := &parser{tokens: tokens} // Init parser
+
+	query, err := p.parseOr() // Parse query
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if p.next < len(p.tokens) { // Check unparsed tokens
+		return nil, p.unexpected() // Return error
+	}
+
+	return query, nil // Return query
+}
+
+// Match checks whether a transaction in the chain of a given account matches a term.
+func (term *Term) Match(chain summercashCommon.Address, transaction *types.Transaction) bool {
+	switch term.Field {
+	case FieldAny:
+		field, _ := term.matchedField(transaction) // Match bare word
+
+		return field != FieldAny // Check matched
+	case FieldHash:
+		return term.matchString(hashString(transaction.Hash)) // Match hash
+	case FieldSender:
+		return term.matchString(addressString(transaction.Sender)) // Match sender
+	case FieldRecipient:
+		return term.matchString(addressString(transaction.Recipient)) // Match recipient
+	case FieldAddress:
+		if term.Operator == OpNotEqual { // Check negated
+			return term.matchString(addressString(transaction.Sender)) && term.matchString(addressString(transaction.Recipient)) // Match neither
+		}
+
+		return term.matchString(addressString(transaction.Sender)) || term.matchString(addressString(transaction.Recipient)) // Match either
+	case FieldChain:
+		return term.matchString(chain.String()) // Match chain
+	case FieldAmount:
+		amount := new(big.Float) // Init amount buffer
+
+		if transaction.Amount != nil { // Check has amount
+			amount = transaction.Amount // Set amount
+		}
+
+		return term.compare(amount.Cmp(term.number)) // Compare amount
+	case FieldNonce:
+		return term.compare(new(big.Float).SetUint64(transaction.AccountNonce).Cmp(term.number)) // Compare nonce
+	case FieldPayload:
+		switch term.Operator {
+		case OpContains:
+			return bytes.Contains(transaction.Payload, []byte(term.Value)) // Match substring
+		case OpNotEqual:
+			return !bytes.Equal(transaction.Payload, []byte(term.Value)) // Match inequality
+		}
+
+		return bytes.Equal(transaction.Payload, []byte(term.Value)) // Match payload
+	}
+
+	return false // Unknown field
+}
+
+// Match checks whether a transaction matches every operand of a query.
+func (and *And) Match(chain summercashCommon.Address, transaction *types.Transaction) bool {
+	for _, operand := range and.Operands { // Iterate through operands
+		if !operand.Match(chain, transaction) { // Check no match
+			return false // No match
+		}
+	}
+
+	return true // Match
+}
+
+// Match checks whether a transaction matches any operand of a query.
+func (or *Or) Match(chain summercashCommon.Address, transaction *types.Transaction) bool {
+	for _, operand := range or.Operands { // Iterate through operands
+		if operand.Match(chain, transaction) { // Check match
+			return true // Match
+		}
+	}
+
+	return false // No match
+}
+
+// Match checks whether a transaction doesn't match the operand of a query.
+func (not *Not) Match(chain summercashCommon.Address, transaction *types.Transaction) bool {
+	return !not.Operand.Match(chain, transaction) // Negate match
+}
+
+// Reasons gets the terms of a query matched by a transaction in the chain of a given account, with bare words
+// replaced by the field terms they matched. Negations a transaction matches are included whole.
+func Reasons(query Query, chain summercashCommon.Address, transaction *types.Transaction) []string {
+	if !query.Match(chain, transaction) { // Check no match
+		return nil // No reasons
+	}
+
+	var reasons []string // Init reasons buffer
+
+	switch query := query.(type) {
+	case *Term:
+		if query.Field == FieldAny { // Check bare word
+			field, operator := query.matchedField(transaction) // Get matched field
+
+			return []string{(&Term{Field: field, Operator: operator, Value: query.Value}).String()} // Return matched field term
+		}
+
+		reasons = append(reasons, query.String()) // Append term
+	case *And:
+		for _, operand := range query.Operands { // Iterate through operands
+			reasons = append(reasons, Reasons(operand, chain, transaction)...) // Append operand reasons
+		}
+	case *Or:
+		for _, operand := range query.Operands { // Iterate through operands
+			reasons = append(reasons, Reasons(operand, chain, transaction)...) // Append operand reasons
+		}
+	default:
+		reasons = append(reasons, query.String()) // Append query
+	}
+
+	return reasons // Return reasons
+}
+
+// String formats a term.
+func (term *Term) String() string {
+	if term.Field == FieldAny { // Check bare word
+		return quote(term.Value) // Return value
+	}
+
+	return string(term.Field) + string(term.Operator) + quote(term.Value) // Return formatted
+}
+
+// String formats a query matching every one of its operands.
+func (and *And) String() string {
+	return joinQueries(and.Operands, " AND ") // Return formatted
+}
+
+// String formats a query matching any of its operands.
+func (or *Or) String() string {
+	return joinQueries(or.Operands, " OR ") // Return formatted
+}
+
+// String formats a query not matching its operand.
+func (not *Not) String() string {
+	return "NOT " + wrapQuery(not.Operand) // Return formatted
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// lex splits a query into tokens.
+func lex(s string) ([]*token, error) {
+	var tokens []*token // Init tokens buffer
+
+	for i := 0; i < len(s); { // Iterate through characters
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++ // Skip whitespace
+		case c == '(' || c == ')':
+			tokens = append(tokens, &token{text: string(c), offset: i, quoteStart: -1, paren: c}) // Append parenthesis
+
+			i++ // Next character
+		default:
+			current := &token{offset: i, quoteStart: -1} // Init token
+
+			var text strings.Builder // Init text buffer
+
+			for i < len(s) && !strings.ContainsRune(" \t\n\r()", rune(s[i])) { // Iterate through word characters
+				if s[i] != '"' { // Check not quoted
+					text.WriteByte(s[i]) // Append character
+					i++                  // Next character
+
+					continue // Continue
+				}
+
+				if current.quoteStart < 0 { // Check first quoted section
+					current.quoteStart = text.Len() // Set quote start
+				}
+
+				start := i // Get quote offset
+
+				for i++; i < len(s) && s[i] != '"'; i++ { // Iterate through quoted characters
+					if s[i] == '\\' && i+1 < len(s) { // Check escaped character
+						i++ // Skip escape
+					}
+
+					text.WriteByte(s[i]) // Append character
+				}
+
+				if i >= len(s) { // Check no closing quote
+					return nil, fmt.Errorf("%s at offset %d", ErrUnterminatedString, start) // Return error
+				}
+
+				i++ // Skip closing quote
+			}
+
+			current.text = text.String() // Set text
+
+			tokens = append(tokens, current) // Append token
+		}
+	}
+
+	return tokens, nil // Return tokens
+}
+
+// parseOr parses a sequence of operands separated by OR.
+func (p *parser) parseOr() (Query, error) {
+	var operands []Query // Init operands buffer
+
+	for {
+		operand, err := p.parseAnd() // Parse operand
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		operands = append(operands, operand) // Append operand
+
+		if !p.keyword("OR") { // Check no more operands
+			break // Break
+		}
+
+		p.next++ // Skip OR
+	}
+
+	if len(operands) == 1 { // Check single operand
+		return operands[0], nil // Return operand
+	}
+
+	return &Or{Operands: operands}, nil // Return disjunction
+}
+
+// parseAnd parses a sequence of operands separated by AND, or placed next to each other.
+func (p *parser) parseAnd() (Query, error) {
+	var operands []Query // Init operands buffer
+
+	for {
+		operand, err := p.parseNot() // Parse operand
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		operands = append(operands, operand) // Append operand
+
+		if p.keyword("AND") { // Check explicit AND
+			p.next++ // Skip AND
+		} else if p.next >= len(p.tokens) || p.keyword("OR") || p.tokens[p.next].paren == ')' { // Check no more operands
+			break // Break
+		}
+	}
+
+	if len(operands) == 1 { // Check single operand
+		return operands[0], nil // Return operand
+	}
+
+	return &And{Operands: operands}, nil // Return conjunction
+}
+
+// parseNot parses an operand, optionally negated by NOT.
+func (p *parser) parseNot() (Query, error) {
+	if !p.keyword("NOT") { // Check not negated
+		return p.parsePrimary() // Parse operand
+	}
+
+	p.next++ // Skip NOT
+
+	operand, err := p.parseNot() // Parse operand
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &Not{Operand: operand}, nil // Return negation
+}
+
+// parsePrimary parses a term or a parenthesized query.
+func (p *parser) parsePrimary() (Query, error) {
+	if p.next >= len(p.tokens) { // Check no more tokens
+		return nil, fmt.Errorf("%s: query ends unexpectedly", ErrUnexpectedToken) // Return error
+	}
+
+	current := p.tokens[p.next] // Get token
+
+	switch {
+	case current.paren == ')' || p.keyword("AND") || p.keyword("OR"):
+		return nil, p.unexpected() // Return error
+	case current.paren == '(':
+		p.next++ // Skip (
+
+		query, err := p.parseOr() // Parse query
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		if p.next >= len(p.tokens) || p.tokens[p.next].paren != ')' { // Check not closed
+			return nil, fmt.Errorf("%s: missing ) for ( at offset %d", ErrUnexpectedToken, current.offset) // Return error
+		}
+
+		p.next++ // Skip )
+
+		return query, nil // Return query
+	}
+
+	p.next++ // Skip term
+
+	return parseTerm(current) // Parse term
+}
+
+// keyword checks whether the next token is a given unquoted keyword.
+func (p *parser) keyword(keyword string) bool {
+	return p.next < len(p.tokens) && p.tokens[p.next].quoteStart < 0 && p.tokens[p.next].text == keyword // Check keyword
+}
+
+// unexpected makes an error describing the next token.
+func (p *parser) unexpected() error {
+	return fmt.Errorf("%s %q at offset %d", ErrUnexpectedToken, p.tokens[p.next].text, p.tokens[p.next].offset) // Return error
+}
+
+// parseTerm parses a term token.
+func parseTerm(current *token) (*Term, error) {
+	unquoted := current.text // Get unquoted prefix
+
+	if current.quoteStart >= 0 { // Check has quoted section
+		unquoted = current.text[:current.quoteStart] // Only look for operators before quotes
+	}
+
+	index := strings.IndexAny(unquoted, ":~<>=!") // Find operator
+
+	if index < 0 { // Check bare word
+		term := &Term{Field: FieldAny, Operator: OpEqual, Value: current.text} // Init bare word
+
+		if number, ok := new(big.Float).SetPrec(350).SetString(current.text); ok { // Check numeric
+			term.number = number // Set number
+		}
+
+		return term, nil // Return bare word
+	}
+
+	term := &Term{Field: Field(strings.ToLower(unquoted[:index]))} // Init term
+
+	operator := unquoted[index:] // Get operator and value
+
+	for _, candidate := range []Operator{OpGreaterEqual, OpLessEqual, OpNotEqual, OpEqual, OpContains, OpGreater, OpLess, "="} { // Iterate through operators, longest first
+		if strings.HasPrefix(operator, string(candidate)) { // Check matches
+			term.Operator = candidate // Set operator
+
+			break // Break
+		}
+	}
+
+	if term.Operator == "" { // Check no operator
+		return nil, fmt.Errorf("%s %q at offset %d", ErrInvalidOperator, operator[:1], current.offset+index) // Return error
+	}
+
+	term.Value = current.text[index+len(term.Operator):] // Set value
+
+	if term.Operator == "=" { // Check alias
+		term.Operator = OpEqual // Normalize operator
+	}
+
+	supported, known := operators[term.Field] // Get supported operators
+
+	if !known { // Check unknown field
+		return nil, fmt.Errorf("%s %q at offset %d; expected one of %s", ErrUnknownField, term.Field, current.offset, joinFields(Fields)) // Return error
+	}
+
+	if !hasOperator(supported, term.Operator) { // Check unsupported operator
+		return nil, fmt.Errorf("%s %q for field %s at offset %d", ErrInvalidOperator, term.Operator, term.Field, current.offset+index) // Return error
+	}
+
+	switch term.Field {
+	case FieldAmount, FieldNonce:
+		number, ok := new(big.Float).SetPrec(350).SetString(term.Value) // Parse number
+
+		if !ok || (term.Field == FieldNonce && (!number.IsInt() || number.Sign() < 0)) { // Check invalid number
+			return nil, fmt.Errorf("%s %q for field %s at offset %d", ErrInvalidValue, term.Value, term.Field, current.offset) // Return error
+		}
+
+		term.number = number // Set number
+	case FieldHash, FieldSender, FieldRecipient, FieldAddress, FieldChain:
+		if term.Operator == OpContains { // Check substring match
+			break // Any value is valid
+		}
+
+		if _, err := hex.DecodeString(strings.TrimPrefix(term.Value, "0x")); err != nil || !strings.HasPrefix(term.Value, "0x") { // Check invalid hex
+			return nil, fmt.Errorf("%s %q for field %s at offset %d; expected 0x-prefixed hex", ErrInvalidValue, term.Value, term.Field, current.offset) // Return error
+		}
+	}
+
+	return term, nil // Return term
+}
+
+// matchedField gets the field and operator of the field term equivalent to a bare word for a given transaction,
+// or FieldAny if the transaction doesn't match the bare word.
+func (term *Term) matchedField(transaction *types.Transaction) (Field, Operator) {
+	fields := []Field{FieldHash, FieldSender, FieldRecipient} // Get exact match fields
+
+	for i, value := range []string{hashString(transaction.Hash), addressString(transaction.Sender), addressString(transaction.Recipient)} { // Iterate through exact match fields
+		if value != "" && strings.EqualFold(value, term.Value) { // Check match
+			return fields[i], OpEqual // Match
+		}
+	}
+
+	if term.number != nil && transaction.Amount != nil && transaction.Amount.Cmp(term.number) == 0 { // Check amount match
+		return FieldAmount, OpEqual // Match
+	}
+
+	if bytes.Contains(transaction.Payload, []byte(term.Value)) { // Check payload match
+		return FieldPayload, OpContains // Match
+	}
+
+	return FieldAny, "" // No match
+}
+
+// matchString compares a string field against a term.
+func (term *Term) matchString(value string) bool {
+	switch term.Operator {
+	case OpContains:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(term.Value)) // Match substring
+	case OpNotEqual:
+		return !strings.EqualFold(value, term.Value) // Match inequality
+	}
+
+	return strings.EqualFold(value, term.Value) // Match value
+}
+
+// compare checks whether the result of comparing a numeric field against a term's value satisfies the term.
+func (term *Term) compare(result int) bool {
+	switch term.Operator {
+	case OpNotEqual:
+		return result != 0 // Not equal
+	case OpGreater:
+		return result > 0 // Greater
+	case OpGreaterEqual:
+		return result >= 0 // Greater or equal
+	case OpLess:
+		return result < 0 // Less
+	case OpLessEqual:
+		return result <= 0 // Less or equal
+	}
+
+	return result == 0 // Equal
+}
+
+// hasOperator checks whether a set of operators contains a given operator.
+func hasOperator(operators []Operator, operator Operator) bool {
+	for _, candidate := range operators { // Iterate through operators
+		if candidate == operator { // Check matches
+			return true // Has operator
+		}
+	}
+
+	return false // No operator
+}
+
+// joinFields joins a set of fields with commas.
+func joinFields(fields []Field) string {
+	names := make([]string, len(fields)) // Init names buffer
+
+	for i, field := range fields { // Iterate through fields
+		names[i] = string(field) // Set name
+	}
+
+	return strings.Join(names, ", ") // Return joined
+}
+
+// joinQueries formats a set of queries, joined by a given separator.
+func joinQueries(queries []Query, separator string) string {
+	formatted := make([]string, len(queries)) // Init formatted buffer
+
+	for i, query := range queries { // Iterate through queries
+		formatted[i] = wrapQuery(query) // Set formatted
+	}
+
+	return strings.Join(formatted, separator) // Return joined
+}
+
+// wrapQuery formats a query, parenthesizing it if it combines other queries.
+func wrapQuery(query Query) string {
+	switch query.(type) {
+	case *And, *Or:
+		return "(" + query.String() + ")" // Return parenthesized
+	}
+
+	return query.String() // Return formatted
+}
+
+// quote quotes a term value if it contains characters that would otherwise be read as syntax.
+func quote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\r()\"\\:~<>=!") && value != "AND" && value != "OR" && value != "NOT" { // Check needs no quotes
+		return value // Return value
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"` // Return quoted
+}
+
+// hashString formats a hash, returning an empty string if it is nil.
+func hashString(hash *summercashCommon.Hash) string {
+	if hash == nil { // Check no hash
+		return "" // Return empty
+	}
+
+	return hash.String() // Return hash
+}
+
+// addressString formats an address, returning an empty string if it is nil.
+func addressString(address *summercashCommon.Address) string {
+	if address == nil { // Check no address
+		return "" // Return empty
+	}
+
+	return address.String() // Return address
+}
+
+/* END INTERNAL METHODS */
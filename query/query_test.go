@@ -0,0 +1,110 @@
+// Package query defines the puppet search query language, along with helper methods for parsing queries and evaluating them against transactions.
+package query
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/internal/fixtures"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestParse tests the functionality of the Parse() method.
+func TestParse(t *testing.T) {
+	for s, expected := range map[string]string{
+		`sender:` + fixtures.Sender + ` amount>100`: `sender:` + fixtures.Sender + ` AND amount>100`,
+		`a OR b c`:                                     `a OR (b AND c)`,
+		`NOT (payload~"in voice" OR nonce>=2)`:         `NOT (payload~"in voice" OR nonce>=2)`,
+		`amount=5 AND NOT chain:` + fixtures.Recipient: `amount:5 AND NOT chain:` + fixtures.Recipient,
+		`"AND"`: `"AND"`,
+	} { // Iterate through queries
+		query, err := Parse(s) // Parse query
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		if query.String() != expected { // Check invalid query
+			t.Fatalf("expected %s for %s, found %s", expected, s, query.String()) // Panic
+		}
+	}
+
+	for s, expected := range map[string]error{
+		``:                   ErrEmptyQuery,
+		`to:0x04`:            ErrUnknownField,
+		`payload>1`:          ErrInvalidOperator,
+		`amount>lots`:        ErrInvalidValue,
+		`sender:alice`:       ErrInvalidValue,
+		`payload~"invoice`:   ErrUnterminatedString,
+		`(amount>1`:          ErrUnexpectedToken,
+		`amount>1 OR`:        ErrUnexpectedToken,
+		`amount>1 AND ) foo`: ErrUnexpectedToken,
+	} { // Iterate through invalid queries
+		if _, err := Parse(s); err == nil || !strings.HasPrefix(err.Error(), expected.Error()) { // Check not rejected
+			t.Fatalf("expected %s for %q, found %v", expected, s, err) // Panic
+		}
+	}
+}
+
+// TestMatch tests the functionality of the Match() method.
+func TestMatch(t *testing.T) {
+	sender := fixtures.Address(fixtures.Sender)       // Parse sender
+	recipient := fixtures.Address(fixtures.Recipient) // Parse recipient
+
+	transaction := fixtures.Transaction("transaction", &sender, &recipient, 150, fixtures.Start) // Init transaction
+
+	transaction.AccountNonce, transaction.Payload = 3, []byte("invoice #1 paid") // Set nonce, payload
+
+	for s, expected := range map[string]bool{
+		`sender:0x` + strings.ToUpper(fixtures.Sender[2:]): true,
+		`sender:` + fixtures.Recipient:                     false,
+		`address:` + fixtures.Recipient:                    true,
+		`chain:` + fixtures.Sender:                         true,
+		`amount>100 amount<=150`:                           true,
+		`amount>150`:                                       false,
+		`payload~"invoice #1"`:                             true,
+		`payload:invoice`:                                  false,
+		`nonce:3 AND NOT payload~refund`:                   true,
+		`amount<1 OR recipient:` + fixtures.Recipient:      true,
+		`hash:` + transaction.Hash.String():                true,
+		`150`:                                              true,
+		`"#1"`:                                             true,
+		`5`:                                                false,
+		fixtures.Recipient:                                 true,
+		`NOT (amount>100 OR payload~invoice)`:              false,
+	} { // Iterate through queries
+		query, err := Parse(s) // Parse query
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		if query.Match(sender, transaction) != expected { // Check invalid match
+			t.Fatalf("expected %s to match: %t", s, expected) // Panic
+		}
+	}
+}
+
+// TestReasons tests the functionality of the Reasons() method.
+func TestReasons(t *testing.T) {
+	sender := fixtures.Address(fixtures.Sender) // Parse sender
+
+	transaction := &types.Transaction{Sender: &sender, Amount: big.NewFloat(150), Payload: []byte("invoice")} // Init transaction
+
+	query, err := Parse(`(amount>1000 payload~invoice) OR (amount>100 ` + fixtures.Sender + `) OR NOT nonce>0`) // Parse query
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	reasons := strings.Join(Reasons(query, sender, transaction), ", ") // Get reasons
+
+	if expected := `amount>100, sender:` + fixtures.Sender + `, NOT nonce>0`; reasons != expected { // Check invalid reasons
+		t.Fatalf("expected reasons %s, found %s", expected, reasons) // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
@@ -0,0 +1,354 @@
+// Package stats defines balance, supply, and activity analytics over the chains of a SummerCash network.
+package stats
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+// Interval represents the length of the periods activity is grouped into.
+type Interval string
+
+const (
+	// Day groups activity by UTC day.
+	Day Interval = "day"
+
+	// Week groups activity by UTC week, starting on Monday.
+	Week Interval = "week"
+
+	// Month groups activity by UTC month.
+	Month Interval = "month"
+)
+
+// Report represents the analytics of a network.
+type Report struct {
+	Chains   int        // Number of chains analyzed
+	Accounts []*Account // Accounts, ordered by balance (largest first)
+
+	GenesisAlloc *big.Float // Supply issued at genesis, per the chain config
+	Supply       *big.Float // Sum of every account balance
+	Minted       *big.Float // Supply created since genesis (Supply - GenesisAlloc)
+
+	Transactions        int        // Number of distinct non-genesis transactions
+	GenesisTransactions int        // Number of genesis and genesis child transactions
+	Volume              *big.Float // Total amount of the non-genesis transactions
+	Periods             []*Period  // Activity per period, in chronological order
+
+	Inflation *Inflation // Configured vs. realised inflation
+}
+
+// Account represents the balance and activity of an account.
+type Account struct {
+	Address string     // Account address
+	Balance *big.Float // Balance
+	Alloc   *big.Float // Genesis allocation in the chain config, if any
+	Share   float64    // Fraction of the supply held
+
+	Sent     int // Number of transactions sent
+	Received int // Number of transactions received
+}
+
+// Period represents the activity in a period of time.
+type Period struct {
+	Start        time.Time  // Period start
+	Transactions int        // Number of transactions
+	Volume       *big.Float // Total amount transacted
+}
+
+// Inflation represents the inflation configured for a network, and that actually realised.
+type Inflation struct {
+	Configured float64       // Configured yearly rate in effect at Until (e.g. 0.1 for 10%)
+	Changes    []*RateChange // Changes to the configured rate made between genesis and Until, in chronological order
+
+	Since time.Time // Genesis time
+	Until time.Time // Time the analytics were computed at
+
+	Realised   float64  // Realised supply growth since genesis (e.g. 0.02 for 2%)
+	Annualised *float64 // Realised yearly rate, if at least a day has passed since genesis
+
+	ExpectedSupply *big.Float // Supply at the configured rate
+}
+
+// RateChange represents a change to the configured inflation rate of a network, such as one made by a scheduled fork.
+type RateChange struct {
+	At   time.Time // Time the rate takes effect
+	Rate float64   // Yearly rate from then on
+}
+
+// ErrUnknownInterval is an error definition describing an unsupported activity interval.
+var ErrUnknownInterval = errors.New("unknown interval; expected day, week, or month")
+
+/* BEGIN EXPORTED METHODS */
+
+// Compute computes the analytics of a network with a given chain config from its chains, as of a given time. The
+// configured inflation rate is that of the chain config until the first of a set of rate changes takes effect.
+func Compute(chains []*types.Chain, chainConfig *config.ChainConfig, changes []*RateChange, interval Interval, at time.Time) *Report {
+	report := &Report{
+		Chains:       len(chains),           // Set chains
+		GenesisAlloc: new(big.Float),        // Init genesis alloc
+		Supply:       new(big.Float),        // Init supply
+		Volume:       new(big.Float),        // Init volume
+		Inflation:    &Inflation{Until: at}, // Init inflation
+	} // Init report
+
+	accounts := make(map[string]*Account) // Init accounts buffer
+
+	account := func(address string) *Account {
+		if accounts[address] == nil { // Check not seen
+			accounts[address] = &Account{Address: address, Balance: new(big.Float)} // Init account
+		}
+
+		return accounts[address] // Return account
+	}
+
+	if chainConfig != nil { // Check has chain config
+		report.Inflation.Configured = chainConfig.InflationRate // Set configured rate
+
+		for address, alloc := range chainConfig.Alloc { // Iterate through alloc
+			if alloc != nil { // Check valid alloc
+				account(address).Alloc = alloc // Set alloc
+			}
+		}
+
+		report.GenesisAlloc = genesisAlloc(chainConfig) // Set genesis alloc
+	}
+
+	genesisHashes := make(map[summercashCommon.Hash]bool) // Init genesis hashes buffer
+
+	for _, chain := range chains { // Iterate through chains
+		genesisHashes[chain.Genesis] = true // Set genesis hash
+
+		balance, _, _ := Balance(chain) // Get balance
+
+		account(chain.Account.String()).Balance = balance // Set balance
+
+		report.Supply.Add(report.Supply, balance) // Add balance
+	}
+
+	seen := make(map[summercashCommon.Hash]bool) // Init seen transactions buffer
+	periods := make(map[time.Time]*Period)       // Init periods buffer
+
+	for _, chain := range chains { // Iterate through chains
+		for _, transaction := range chain.Transactions { // Iterate through transactions
+			if transaction == nil || transaction.Hash == nil || seen[*transaction.Hash] { // Check invalid or already counted
+				continue // Continue
+			}
+
+			seen[*transaction.Hash] = true // Set seen
+
+			if genesisHashes[*transaction.Hash] { // Check genesis transaction
+				report.GenesisTransactions++ // Increment genesis transactions
+
+				if report.Inflation.Since.IsZero() || transaction.Timestamp.Before(report.Inflation.Since) { // Check earliest genesis
+					report.Inflation.Since = transaction.Timestamp // Set genesis time
+				}
+
+				continue // Continue
+			}
+
+			if string(transaction.Payload) == "genesisChild" { // Check genesis child transaction
+				report.GenesisTransactions++ // Increment genesis transactions
+
+				continue // Continue
+			}
+
+			report.Transactions++ // Increment transactions
+
+			if transaction.Sender != nil { // Check has sender
+				account(transaction.Sender.String()).Sent++ // Increment sent
+			}
+
+			if transaction.Recipient != nil { // Check has recipient
+				account(transaction.Recipient.String()).Received++ // Increment received
+			}
+
+			start := interval.Truncate(transaction.Timestamp) // Get period start
+
+			if periods[start] == nil { // Check new period
+				periods[start] = &Period{Start: start, Volume: new(big.Float)} // Init period
+			}
+
+			periods[start].Transactions++ // Increment period transactions
+
+			if transaction.Amount != nil { // Check has amount
+				periods[start].Volume.Add(periods[start].Volume, transaction.Amount) // Add to period volume
+				report.Volume.Add(report.Volume, transaction.Amount)                 // Add to volume
+			}
+		}
+	}
+
+	for _, account := range accounts { // Iterate through accounts
+		if report.Supply.Sign() > 0 { // Check has supply
+			account.Share, _ = new(big.Float).Quo(account.Balance, report.Supply).Float64() // Set share
+		}
+
+		report.Accounts = append(report.Accounts, account) // Append account
+	}
+
+	sort.Slice(report.Accounts, func(i, j int) bool {
+		if comparison := report.Accounts[i].Balance.Cmp(report.Accounts[j].Balance); comparison != 0 { // Check balances differ
+			return comparison > 0 // Order by balance
+		}
+
+		return report.Accounts[i].Address < report.Accounts[j].Address // Order by address
+	}) // Sort accounts
+
+	for _, period := range periods { // Iterate through periods
+		report.Periods = append(report.Periods, period) // Append period
+	}
+
+	sort.Slice(report.Periods, func(i, j int) bool { return report.Periods[i].Start.Before(report.Periods[j].Start) }) // Sort periods
+
+	report.Minted = new(big.Float).Sub(report.Supply, report.GenesisAlloc) // Set minted
+
+	report.Inflation.compute(report.GenesisAlloc, report.Supply, changes) // Compute inflation
+
+	return report // Return report
+}
+
+// Balance computes the balance of a chain's account, along with the totals it has received and sent.
+// Unlike Chain.CalculateBalance, it tolerates transactions without a sender, recipient, or amount.
+func Balance(chain *types.Chain) (balance *big.Float, received *big.Float, sent *big.Float) {
+	received, sent = new(big.Float), new(big.Float) // Init totals
+
+	for _, transaction := range chain.Transactions { // Iterate through transactions
+		if transaction == nil || transaction.Amount == nil { // Check no amount
+			continue // Continue
+		}
+
+		switch {
+		case transaction.Hash != nil && *transaction.Hash == chain.Genesis:
+			received.Add(received, transaction.Amount) // Add genesis allocation
+		case transaction.Sender != nil && *transaction.Sender == chain.Account:
+			sent.Add(sent, transaction.Amount) // Add sent
+		case transaction.Recipient != nil && *transaction.Recipient == chain.Account:
+			received.Add(received, transaction.Amount) // Add received
+		}
+	}
+
+	return new(big.Float).Sub(received, sent), received, sent // Return balances
+}
+
+// ParseInterval parses an activity interval (day, week, or month).
+func ParseInterval(s string) (Interval, error) {
+	switch interval := Interval(s); interval {
+	case Day, Week, Month:
+		return interval, nil // Return interval
+	}
+
+	return "", ErrUnknownInterval // Return error
+}
+
+// Truncate gets the start of the period holding a given time.
+func (interval Interval) Truncate(t time.Time) time.Time {
+	t = t.UTC() // Use UTC
+
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC) // Get day start
+
+	switch interval {
+	case Week:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)) // Return Monday
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC) // Return first of month
+	}
+
+	return day // Return day
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// genesisAlloc gets the supply issued at the genesis of a network with a given chain config. The genesis transaction
+// issues the alloc of the first alloc address, which then funds every other alloc address, so only the first counts.
+func genesisAlloc(chainConfig *config.ChainConfig) *big.Float {
+	if len(chainConfig.AllocAddresses) == 0 { // Check no alloc addresses
+		total := new(big.Float) // Init total buffer
+
+		for _, alloc := range chainConfig.Alloc { // Iterate through alloc
+			if alloc != nil { // Check valid alloc
+				total.Add(total, alloc) // Add alloc
+			}
+		}
+
+		return total // Return total
+	}
+
+	if alloc := chainConfig.Alloc[chainConfig.AllocAddresses[0].String()]; alloc != nil { // Check has genesis alloc
+		return new(big.Float).Set(alloc) // Return genesis alloc
+	}
+
+	return new(big.Float) // No genesis alloc
+}
+
+// compute computes the realised and expected inflation of a network with a given genesis allocation and supply,
+// compounding the configured rate (as changed by a set of rate changes) over each period it was in effect for.
+func (inflation *Inflation) compute(genesisAlloc *big.Float, supply *big.Float, changes []*RateChange) {
+	sorted := append([]*RateChange(nil), changes...) // Copy changes
+
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) }) // Sort changes
+
+	growth := 1.0           // Init expected growth buffer
+	last := inflation.Since // Init period start buffer
+
+	for _, change := range sorted { // Iterate through changes
+		if change.At.After(inflation.Until) { // Check not yet in effect
+			break // Stop
+		}
+
+		if !inflation.Since.IsZero() && change.At.After(last) { // Check ends a period
+			growth *= math.Pow(1+inflation.Configured, years(last, change.At)) // Compound period
+
+			last = change.At // Set period start
+		}
+
+		inflation.Configured = change.Rate                    // Set rate
+		inflation.Changes = append(inflation.Changes, change) // Append change
+	}
+
+	if !inflation.Since.IsZero() { // Check has genesis
+		growth *= math.Pow(1+inflation.Configured, years(last, inflation.Until)) // Compound last period
+	}
+
+	if genesisAlloc.Sign() <= 0 { // Check no allocation to grow from
+		return // Nothing to compute
+	}
+
+	ratio, _ := new(big.Float).Quo(supply, genesisAlloc).Float64() // Get supply growth
+
+	inflation.Realised = ratio - 1 // Set realised
+
+	elapsed := 0.0 // Init elapsed buffer
+
+	if !inflation.Since.IsZero() { // Check has genesis
+		elapsed = years(inflation.Since, inflation.Until) // Get years since genesis
+	}
+
+	if elapsed >= 1/365.25 { // Check at least a day has passed
+		annualised := math.Pow(ratio, 1/elapsed) - 1 // Get yearly rate
+
+		inflation.Annualised = &annualised // Set annualised
+	}
+
+	expected := new(big.Float).SetFloat64(growth) // Get expected growth
+
+	inflation.ExpectedSupply = expected.Mul(expected, genesisAlloc) // Set expected supply
+}
+
+// years gets the number of years between two times, or zero if the second isn't after the first.
+func years(since time.Time, until time.Time) float64 {
+	if !until.After(since) { // Check no elapsed time
+		return 0 // No elapsed time
+	}
+
+	return until.Sub(since).Hours() / 24 / 365.25 // Return years
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,117 @@
+// Package stats defines balance, supply, and activity analytics over the chains of a SummerCash network.
+package stats
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/types"
+	"github.com/SummerCash/puppet/internal/fixtures"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestCompute tests the functionality of the Compute() method.
+func TestCompute(t *testing.T) {
+	sender := fixtures.Address(fixtures.Sender)       // Parse sender
+	recipient := fixtures.Address(fixtures.Recipient) // Parse recipient
+
+	genesis := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC) // Get genesis time
+
+	genesisTx := fixtures.Transaction("genesis", nil, &sender, 1000, genesis)                                 // Init genesis transaction
+	childTx := fixtures.Transaction("child", &sender, &recipient, 400, genesis)                               // Init genesis child transaction
+	transferTx := fixtures.Transaction("transfer", &sender, &recipient, 100, genesis.AddDate(0, 0, 2))        // Init transfer
+	rewardTx := fixtures.Transaction("reward", nil, &recipient, 100, genesis.AddDate(0, 0, 3))                // Init minting transaction
+	laterTx := fixtures.Transaction("later", &recipient, &sender, 0, genesis.AddDate(0, 0, 3).Add(time.Hour)) // Init same-day transfer
+
+	genesisTx.Payload, childTx.Payload, rewardTx.Payload = []byte("genesis"), []byte("genesisChild"), []byte("reward") // Set payloads
+
+	chains := []*types.Chain{
+		{Account: sender, Genesis: *genesisTx.Hash, Transactions: []*types.Transaction{genesisTx, childTx, transferTx, laterTx}}, // Genesis chain
+		{Account: recipient, Transactions: []*types.Transaction{childTx, transferTx, rewardTx, laterTx}},                         // Alloc chain
+	} // Init chains
+
+	chainConfig := &config.ChainConfig{
+		Alloc:          map[string]*big.Float{fixtures.Sender: big.NewFloat(1000), fixtures.Recipient: big.NewFloat(400)}, // Set alloc
+		AllocAddresses: []summercashCommon.Address{sender, recipient},                                                     // Set alloc addresses
+		InflationRate:  0.1,                                                                                               // Set inflation
+	} // Init chain config
+
+	until := genesis.Add(time.Duration(365.25*24) * time.Hour) // Get analytics time
+
+	report := Compute(chains, chainConfig, nil, Day, until) // Compute analytics
+
+	if report.GenesisAlloc.Cmp(big.NewFloat(1000)) != 0 || report.Supply.Cmp(big.NewFloat(1100)) != 0 || report.Minted.Cmp(big.NewFloat(100)) != 0 { // Check invalid supply
+		t.Fatalf("expected an alloc of 1000, a supply of 1100, and 100 minted; found %s, %s, %s", report.GenesisAlloc.String(), report.Supply.String(), report.Minted.String()) // Panic
+	}
+
+	if report.Transactions != 3 || report.GenesisTransactions != 2 || report.Volume.Cmp(big.NewFloat(200)) != 0 { // Check invalid activity
+		t.Fatalf("expected 3 transactions, 2 genesis transactions, and a volume of 200; found %d, %d, %s", report.Transactions, report.GenesisTransactions, report.Volume.String()) // Panic
+	}
+
+	if len(report.Accounts) != 2 || report.Accounts[0].Address != fixtures.Recipient || report.Accounts[0].Balance.Cmp(big.NewFloat(600)) != 0 || report.Accounts[0].Received != 2 || report.Accounts[0].Sent != 1 { // Check invalid top holder
+		t.Fatalf("expected %s to hold 600, found %+v", fixtures.Recipient, report.Accounts[0]) // Panic
+	}
+
+	if len(report.Periods) != 2 || !report.Periods[0].Start.Equal(genesis.AddDate(0, 0, 2)) || report.Periods[1].Transactions != 2 { // Check invalid periods
+		t.Fatalf("expected 2 periods, found %d", len(report.Periods)) // Panic
+	}
+
+	if math.Abs(report.Inflation.Realised-0.1) > 1e-9 || report.Inflation.Annualised == nil || math.Abs(*report.Inflation.Annualised-0.1) > 1e-9 { // Check invalid inflation
+		t.Fatalf("expected 10%% realised inflation over a year, found %+v", report.Inflation) // Panic
+	}
+
+	if expected, _ := report.Inflation.ExpectedSupply.Float64(); math.Abs(expected-1100) > 1e-6 { // Check invalid expected supply
+		t.Fatalf("expected an expected supply of 1100, found %f", expected) // Panic
+	}
+
+	halfway := genesis.Add(until.Sub(genesis) / 2) // Get halfway time
+
+	report = Compute(chains, chainConfig, []*RateChange{{At: until.AddDate(1, 0, 0), Rate: 1}, {At: halfway, Rate: 0}}, Day, until) // Compute analytics with inflation stopped halfway
+
+	if report.Inflation.Configured != 0 || len(report.Inflation.Changes) != 1 { // Check invalid rate
+		t.Fatalf("expected a single change to a rate of 0, found %+v", report.Inflation) // Panic
+	}
+
+	if expected, _ := report.Inflation.ExpectedSupply.Float64(); math.Abs(expected-1000*math.Sqrt(1.1)) > 1e-6 { // Check invalid expected supply
+		t.Fatalf("expected an expected supply of %f, found %f", 1000*math.Sqrt(1.1), expected) // Panic
+	}
+}
+
+// TestParseInterval tests the functionality of the ParseInterval() method.
+func TestParseInterval(t *testing.T) {
+	for _, valid := range []string{"day", "week", "month"} { // Iterate through valid intervals
+		if interval, err := ParseInterval(valid); err != nil || string(interval) != valid { // Check not parsed
+			t.Fatalf("expected %s to parse, found %s (%v)", valid, interval, err) // Panic
+		}
+	}
+
+	if _, err := ParseInterval("year"); err != ErrUnknownInterval { // Check parsed
+		t.Fatalf("expected %v, found %v", ErrUnknownInterval, err) // Panic
+	}
+}
+
+// TestTruncate tests the functionality of the Truncate() method.
+func TestTruncate(t *testing.T) {
+	at := time.Date(2019, time.March, 14, 15, 9, 26, 0, time.UTC) // Get Thursday afternoon
+
+	for interval, expected := range map[Interval]time.Time{
+		Day:   time.Date(2019, time.March, 14, 0, 0, 0, 0, time.UTC), // Day start
+		Week:  time.Date(2019, time.March, 11, 0, 0, 0, 0, time.UTC), // Monday
+		Month: time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC),  // First of month
+	} { // Iterate through intervals
+		if start := interval.Truncate(at); !start.Equal(expected) { // Check invalid start
+			t.Fatalf("expected %s to truncate to %s, found %s", interval, expected, start) // Panic
+		}
+	}
+
+	if start := Week.Truncate(time.Date(2019, time.March, 17, 23, 0, 0, 0, time.UTC)); start.Day() != 11 { // Check Sunday belongs to previous week
+		t.Fatalf("expected Sunday to belong to the week of the 11th, found %s", start) // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
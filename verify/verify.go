@@ -0,0 +1,304 @@
+// Package verify defines integrity checks over the chains of a SummerCash network.
+package verify
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"path/filepath"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/crypto"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+// Check represents a kind of integrity check.
+type Check string
+
+const (
+	// CheckChainFile checks that a chain file can be read.
+	CheckChainFile Check = "chain-file"
+
+	// CheckChainID checks that a chain's ID matches the one derived from its account and network.
+	CheckChainID Check = "chain-id"
+
+	// CheckNetworkID checks that a chain belongs to the network in the chain config.
+	CheckNetworkID Check = "network-id"
+
+	// CheckHash checks that a transaction's hash matches its contents, and is unique within its chain.
+	CheckHash Check = "hash"
+
+	// CheckSignature checks that a transaction is signed by its sender.
+	CheckSignature Check = "signature"
+
+	// CheckGenesis checks that the genesis chain matches the alloc in the chain config.
+	CheckGenesis Check = "genesis"
+
+	// CheckCrossReference checks that a transaction is held by the chains of both its sender and its recipient.
+	CheckCrossReference Check = "cross-reference"
+)
+
+// Violation represents a failed integrity check.
+type Violation struct {
+	File        string // Path of the chain file holding the violation
+	Chain       string // Chain account address
+	Transaction string // Transaction hash, if the violation concerns a transaction
+	Check       Check  // Failed check
+	Message     string // Description of the violation
+}
+
+// Report represents the result of verifying the chains of a network.
+type Report struct {
+	Chains       int // Number of chains verified
+	Transactions int // Number of transactions verified
+
+	Violations []*Violation // Violations, in chain order
+}
+
+/* BEGIN EXPORTED METHODS */
+
+// Verify checks the integrity of the chains of a network with a given chain config, stored in a given chain directory.
+func Verify(chainDir string, chains []*types.Chain, chainConfig *config.ChainConfig) *Report {
+	report := &Report{Chains: len(chains)} // Init report
+
+	held := make(map[summercashCommon.Address]map[summercashCommon.Hash]bool) // Init held transactions buffer
+
+	for _, chain := range chains { // Iterate through chains
+		held[chain.Account] = make(map[summercashCommon.Hash]bool) // Init chain transactions
+
+		for _, transaction := range chain.Transactions { // Iterate through transactions
+			if transaction != nil && transaction.Hash != nil { // Check has hash
+				held[chain.Account][*transaction.Hash] = true // Set held
+			}
+		}
+	}
+
+	for _, chain := range chains { // Iterate through chains
+		violation := func(transaction *types.Transaction, check Check, format string, args ...interface{}) {
+			entry := &Violation{
+				File:    File(chainDir, chain.Account.String()), // Set file
+				Chain:   chain.Account.String(),                 // Set chain
+				Check:   check,                                  // Set check
+				Message: fmt.Sprintf(format, args...),           // Set message
+			} // Init violation
+
+			if transaction != nil && transaction.Hash != nil { // Check has transaction
+				entry.Transaction = transaction.Hash.String() // Set transaction
+			}
+
+			report.Violations = append(report.Violations, entry) // Append violation
+		}
+
+		if expected := ChainID(chain); chain.ID != expected { // Check ID mismatch
+			violation(nil, CheckChainID, "chain ID %s does not match the ID derived from its account and network, %s", chain.ID.String(), expected.String()) // Record violation
+		}
+
+		if chainConfig != nil && chain.NetworkID != chainConfig.NetworkID { // Check network mismatch
+			violation(nil, CheckNetworkID, "chain belongs to network %d, but the chain config is for network %d", chain.NetworkID, chainConfig.NetworkID) // Record violation
+		}
+
+		seen := make(map[summercashCommon.Hash]bool) // Init seen hashes buffer
+
+		for i, transaction := range chain.Transactions { // Iterate through transactions
+			report.Transactions++ // Increment transactions
+
+			if transaction == nil { // Check empty transaction
+				violation(nil, CheckHash, "transaction %d is empty", i) // Record violation
+
+				continue // Continue
+			}
+
+			if transaction.Hash == nil { // Check no hash
+				violation(nil, CheckHash, "transaction %d has no hash", i) // Record violation
+			} else if seen[*transaction.Hash] { // Check duplicate
+				violation(transaction, CheckHash, "transaction appears more than once in the chain") // Record violation
+			} else if expected := TransactionHash(transaction); *transaction.Hash != expected { // Check hash mismatch
+				violation(transaction, CheckHash, "hash does not match the transaction's contents, which hash to %s", expected.String()) // Record violation
+			}
+
+			if transaction.Hash != nil { // Check has hash
+				seen[*transaction.Hash] = true // Set seen
+			}
+
+			if err := Signature(transaction); err != nil { // Check invalid signature
+				if transaction.Sender != nil || transaction.Hash == nil || *transaction.Hash != chain.Genesis { // Check not genesis transaction
+					violation(transaction, CheckSignature, "%s", err) // Record violation
+				}
+			}
+
+			if (transaction.Sender == nil || *transaction.Sender != chain.Account) && (transaction.Recipient == nil || *transaction.Recipient != chain.Account) { // Check irrelevant
+				violation(transaction, CheckCrossReference, "transaction is neither sent nor received by %s", chain.Account.String()) // Record violation
+			}
+
+			if transaction.Hash == nil { // Check can't be cross-referenced
+				continue // Continue
+			}
+
+			for _, counterpart := range []struct {
+				role    string
+				address *summercashCommon.Address
+			}{{"sender", transaction.Sender}, {"recipient", transaction.Recipient}} { // Iterate through counterparts
+				if counterpart.address == nil || *counterpart.address == chain.Account { // Check no other chain to reference
+					continue // Continue
+				}
+
+				if counterpartChain, ok := held[*counterpart.address]; !ok { // Check no chain
+					violation(transaction, CheckCrossReference, "the chain of its %s %s is missing or unreadable", counterpart.role, counterpart.address.String()) // Record violation
+				} else if !counterpartChain[*transaction.Hash] { // Check not held
+					violation(transaction, CheckCrossReference, "the chain of its %s %s does not hold it", counterpart.role, counterpart.address.String()) // Record violation
+				}
+			}
+		}
+	}
+
+	if chainConfig != nil && len(chainConfig.AllocAddresses) > 0 { // Check has genesis
+		genesisAddress := chainConfig.AllocAddresses[0].String() // Get genesis address
+
+		var genesisChain *types.Chain // Init genesis chain buffer
+
+		for _, chain := range chains { // Iterate through chains
+			if chain.Account == chainConfig.AllocAddresses[0] { // Check is genesis chain
+				genesisChain = chain // Set genesis chain
+			}
+		}
+
+		if genesisChain == nil { // Check no genesis chain
+			report.Violations = append(report.Violations, &Violation{File: File(chainDir, genesisAddress), Chain: genesisAddress, Check: CheckGenesis, Message: "the genesis chain is missing or unreadable"}) // Record violation
+		} else {
+			for _, problem := range Genesis(genesisChain, chainConfig) { // Iterate through mismatches
+				report.Violations = append(report.Violations, &Violation{File: File(chainDir, genesisAddress), Chain: genesisAddress, Check: CheckGenesis, Message: problem}) // Record violation
+			}
+		}
+	}
+
+	return report // Return report
+}
+
+// Genesis checks that the genesis and genesis child transactions in a genesis chain match the alloc of a given
+// chain config, returning a description of each mismatch.
+func Genesis(genesisChain *types.Chain, chainConfig *config.ChainConfig) []string {
+	var problems []string // Init problems buffer
+
+	if len(chainConfig.AllocAddresses) == 0 { // Check no alloc
+		return problems // Nothing to match
+	}
+
+	expected := make(map[string]*big.Float) // Init expected balances buffer
+
+	for i, address := range chainConfig.AllocAddresses { // Iterate through alloc addresses
+		if i > 0 { // Check not genesis
+			expected[address.String()] = chainConfig.Alloc[address.String()] // Set expected balance
+		}
+	}
+
+	foundGenesis := false // Init found genesis buffer
+
+	for _, transaction := range genesisChain.Transactions { // Iterate through transactions
+		if transaction == nil || transaction.Hash == nil || transaction.Amount == nil { // Check not transaction
+			continue // Continue
+		}
+
+		if *transaction.Hash == genesisChain.Genesis { // Check is genesis
+			foundGenesis = true // Set found
+
+			if supply := chainConfig.Alloc[chainConfig.AllocAddresses[0].String()]; supply == nil || transaction.Amount.Cmp(supply) != 0 { // Check supply mismatch
+				problems = append(problems, fmt.Sprintf("genesis transaction %s issues %s", transaction.Hash.String(), transaction.Amount.Text('f', -1))) // Record mismatch
+			}
+		} else if string(transaction.Payload) == "genesisChild" && transaction.Recipient != nil { // Check is genesis child
+			balance, ok := expected[transaction.Recipient.String()] // Get expected balance
+
+			if !ok || balance == nil || transaction.Amount.Cmp(balance) != 0 { // Check balance mismatch
+				problems = append(problems, fmt.Sprintf("genesis child transaction %s sends %s to %s", transaction.Hash.String(), transaction.Amount.Text('f', -1), transaction.Recipient.String())) // Record mismatch
+			}
+
+			delete(expected, transaction.Recipient.String()) // Mark found
+		}
+	}
+
+	if !foundGenesis { // Check no genesis
+		problems = append(problems, fmt.Sprintf("genesis transaction %s is missing", genesisChain.Genesis.String())) // Record mismatch
+	}
+
+	for _, address := range chainConfig.AllocAddresses[1:] { // Iterate through alloc addresses, in order
+		if _, unmatched := expected[address.String()]; unmatched { // Check unmatched
+			problems = append(problems, fmt.Sprintf("no genesis child transaction for %s", address.String())) // Record mismatch
+		}
+	}
+
+	return problems // Return problems
+}
+
+// Signature checks that a transaction is signed by its sender, over its contents.
+func Signature(transaction *types.Transaction) error {
+	if transaction.Sender == nil { // Check no sender
+		return fmt.Errorf("transaction has no sender, but isn't its chain's genesis transaction") // Return error
+	}
+
+	if transaction.Signature == nil || transaction.Signature.PublicKey == nil || transaction.Signature.PublicKey.X == nil { // Check unsigned
+		return fmt.Errorf("transaction is unsigned") // Return error
+	}
+
+	if signer := summercashCommon.PublicKeyToAddress(transaction.Signature.PublicKey); signer != *transaction.Sender { // Check signed by someone else
+		return fmt.Errorf("transaction is signed by %s rather than its sender %s", signer.String(), transaction.Sender.String()) // Return error
+	}
+
+	if !bytes.Equal(transaction.Signature.V, signingDigest(transaction)) { // Check signature over other contents
+		return fmt.Errorf("signature does not cover the transaction's contents") // Return error
+	}
+
+	if valid, err := types.VerifyTransactionSignature(transaction); err != nil || !valid { // Check invalid signature
+		return fmt.Errorf("signature is invalid") // Return error
+	}
+
+	return nil // Signature valid
+}
+
+// ChainID derives the ID of a chain from its account and network, in the same way it was set when the chain was created.
+func ChainID(chain *types.Chain) summercashCommon.Hash {
+	initial := &types.Chain{
+		Account:      chain.Account,          // Set account
+		Transactions: []*types.Transaction{}, // Set transactions
+		NetworkID:    chain.NetworkID,        // Set network ID
+	} // Init chain as created
+
+	return summercashCommon.NewHash(crypto.Sha3(initial.Bytes())) // Return ID
+}
+
+// TransactionHash derives the hash of a transaction from its contents, in the same way it was set when the
+// transaction was created.
+func TransactionHash(transaction *types.Transaction) summercashCommon.Hash {
+	initial := unsigned(transaction) // Get transaction as created
+
+	initial.Hash = nil // Remove hash
+
+	return summercashCommon.NewHash(crypto.Sha3(initial.Bytes())) // Return hash
+}
+
+// File gets the path of the file holding the chain of the account with a given address in a given chain directory.
+func File(chainDir string, account string) string {
+	return filepath.Join(chainDir, fmt.Sprintf("chain_%s.json", account)) // Return path
+}
+
+/* END EXPORTED METHODS */
+
+/* BEGIN INTERNAL METHODS */
+
+// signingDigest derives the digest a transaction's sender signs, in the same way types.SignTransaction does.
+func signingDigest(transaction *types.Transaction) []byte {
+	return crypto.Sha3(unsigned(transaction).Bytes()) // Return digest
+}
+
+// unsigned gets a copy of a transaction without the fields set once it has been hashed.
+func unsigned(transaction *types.Transaction) *types.Transaction {
+	initial := *transaction // Copy transaction
+
+	initial.Signature = nil // Remove signature
+	initial.Genesis = false // Remove genesis flag
+	initial.State = nil     // Remove contract state
+	initial.Logs = nil      // Remove logs
+
+	return &initial // Return copy
+}
+
+/* END INTERNAL METHODS */
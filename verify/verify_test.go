@@ -0,0 +1,153 @@
+// Package verify defines integrity checks over the chains of a SummerCash network.
+package verify
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	summercashCommon "github.com/SummerCash/go-summercash/common"
+	"github.com/SummerCash/go-summercash/config"
+	"github.com/SummerCash/go-summercash/types"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestVerify tests the functionality of the Verify() method.
+func TestVerify(t *testing.T) {
+	chains, chainConfig := testNetwork(t) // Init network
+
+	if report := Verify("chain", chains, chainConfig); len(report.Violations) != 0 || report.Chains != 2 || report.Transactions != 3 { // Check not valid
+		t.Fatalf("expected 2 valid chains with 3 transactions, found %+v", report) // Panic
+	}
+
+	chains, chainConfig = testNetwork(t) // Reset network
+
+	chains[1].Transactions = nil                             // Drop genesis child from recipient chain
+	chains[1].NetworkID = 8                                  // Move recipient chain to another network
+	chains[0].Transactions[1].Amount = big.NewFloat(401)     // Change genesis child amount
+	chains[0].Transactions[1].Recipient = &chains[0].Account // Redirect genesis child
+
+	expected := map[Check]int{CheckChainID: 1, CheckNetworkID: 1, CheckHash: 1, CheckSignature: 1, CheckGenesis: 2} // Get expected violations
+
+	for _, violation := range Verify("chain", chains, chainConfig).Violations { // Iterate through violations
+		expected[violation.Check]-- // Decrement expected
+
+		if violation.File != File("chain", violation.Chain) { // Check invalid file
+			t.Fatalf("expected violation in %s, found %s", File("chain", violation.Chain), violation.File) // Panic
+		}
+	}
+
+	for check, remaining := range expected { // Iterate through checks
+		if remaining != 0 { // Check unexpected count
+			t.Fatalf("expected %d more %s violations", remaining, check) // Panic
+		}
+	}
+}
+
+// TestVerifyCrossReferences tests that Verify() reports transactions missing from their counterpart's chain.
+func TestVerifyCrossReferences(t *testing.T) {
+	chains, chainConfig := testNetwork(t) // Init network
+
+	chains[1].Transactions = nil // Drop genesis child from recipient chain
+
+	violations := Verify("chain", chains, chainConfig).Violations // Verify chains
+
+	if len(violations) != 1 || violations[0].Check != CheckCrossReference || violations[0].Chain != chains[0].Account.String() { // Check not reported
+		t.Fatalf("expected a single cross-reference violation in the genesis chain, found %d violations", len(violations)) // Panic
+	}
+
+	violations = Verify("chain", chains[:1], chainConfig).Violations // Verify without recipient chain
+
+	if len(violations) != 1 || violations[0].Check != CheckCrossReference { // Check not reported
+		t.Fatalf("expected a single cross-reference violation for the missing chain, found %d violations", len(violations)) // Panic
+	}
+}
+
+// TestSignature tests the functionality of the Signature() method.
+func TestSignature(t *testing.T) {
+	chains, _ := testNetwork(t) // Init network
+
+	transaction := chains[0].Transactions[1] // Get genesis child
+
+	if err := Signature(transaction); err != nil { // Check invalid
+		t.Fatal(err) // Panic
+	}
+
+	if err := Signature(chains[0].Transactions[0]); err == nil { // Check genesis transaction signed
+		t.Fatal("expected an error for a transaction without a sender") // Panic
+	}
+
+	transaction.Sender = transaction.Recipient // Change sender
+
+	if err := Signature(transaction); err == nil { // Check still valid
+		t.Fatal("expected an error for a transaction signed by someone other than its sender") // Panic
+	}
+
+	transaction.Signature = nil // Remove signature
+
+	if err := Signature(transaction); err == nil { // Check still valid
+		t.Fatal("expected an error for an unsigned transaction") // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */
+
+/* BEGIN INTERNAL METHODS */
+
+// testNetwork initializes a network with a genesis chain and a recipient chain holding a genesis child transaction.
+func testNetwork(t *testing.T) ([]*types.Chain, *config.ChainConfig) {
+	genesisKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader) // Generate genesis key
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	recipientKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader) // Generate recipient key
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	genesisAddress := summercashCommon.PublicKeyToAddress(&genesisKey.PublicKey)     // Get genesis address
+	recipientAddress := summercashCommon.PublicKeyToAddress(&recipientKey.PublicKey) // Get recipient address
+
+	genesisTx, err := types.NewTransaction(0, nil, nil, &genesisAddress, big.NewFloat(1000), []byte("genesis")) // Init genesis transaction
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	childTx, err := types.NewTransaction(1, genesisTx, &genesisAddress, &recipientAddress, big.NewFloat(400), []byte("genesisChild")) // Init genesis child transaction
+
+	if err != nil { // Check for errors
+		t.Fatal(err) // Panic
+	}
+
+	if err = types.SignTransaction(childTx, genesisKey); err != nil { // Sign genesis child transaction
+		t.Fatal(err) // Panic
+	}
+
+	chains := []*types.Chain{
+		{Account: genesisAddress, NetworkID: 7},   // Genesis chain
+		{Account: recipientAddress, NetworkID: 7}, // Recipient chain
+	} // Init chains
+
+	for _, chain := range chains { // Iterate through chains
+		chain.ID = ChainID(chain) // Set ID
+	}
+
+	chains[0].Genesis = *genesisTx.Hash                               // Set genesis
+	chains[0].Transactions = []*types.Transaction{genesisTx, childTx} // Set genesis transactions
+	chains[1].Transactions = []*types.Transaction{childTx}            // Set received transactions
+
+	return chains, &config.ChainConfig{
+		Alloc:          map[string]*big.Float{genesisAddress.String(): big.NewFloat(1000), recipientAddress.String(): big.NewFloat(400)}, // Set alloc
+		AllocAddresses: []summercashCommon.Address{genesisAddress, recipientAddress},                                                     // Set alloc addresses
+		NetworkID:      7,                                                                                                                // Set network ID
+	} // Return network
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,320 @@
+// Package vesting defines genesis allocation vesting schedules, along with helper methods for computing the unlocked portion of an allocation at a given time.
+package vesting
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule represents the vesting schedule of a genesis allocation. A schedule either releases the allocation
+// linearly from Start over Duration, with nothing released before the Cliff, or releases fixed amounts at each
+// of its Unlocks, with any remainder unlocked from genesis.
+type Schedule struct {
+	Start    *time.Time `json:"start,omitempty"`    // Linear release start
+	Cliff    Duration   `json:"cliff,omitempty"`    // Time after start before anything is released
+	Duration Duration   `json:"duration,omitempty"` // Linear release duration
+
+	Unlocks []*Unlock `json:"unlocks,omitempty"` // Fixed unlocks
+}
+
+// Unlock represents a fixed amount released at a given time.
+type Unlock struct {
+	At     time.Time `json:"at"`     // Unlock time
+	Amount string    `json:"amount"` // Unlocked amount
+}
+
+// Duration represents a length of time, written as a Go duration (e.g. 720h) or a number of days (e.g. 90d).
+type Duration time.Duration
+
+// Lock represents the vesting schedule of the allocation held by a particular address.
+type Lock struct {
+	Address  string    `json:"address"`  // Address
+	Balance  string    `json:"balance"`  // Allocated balance
+	Schedule *Schedule `json:"schedule"` // Vesting schedule
+}
+
+// Status represents the unlocked and locked portions of an allocation at a given time.
+type Status struct {
+	Total    *big.Float // Allocated balance
+	Unlocked *big.Float // Unlocked balance
+	Locked   *big.Float // Locked balance
+
+	NextUnlock    *time.Time // Time of the next cliff or fixed unlock, if any
+	FullyUnlocked *time.Time // Time the allocation is fully unlocked, if still locked
+}
+
+// FieldError represents a problem with a particular field of a vesting schedule.
+type FieldError struct {
+	Field   string // Field path, relative to the schedule
+	Message string // Description of the problem
+}
+
+var (
+	// ErrInvalidDuration is an error definition describing a duration that could not be parsed.
+	ErrInvalidDuration = errors.New("invalid duration; expected e.g. 720h or 90d")
+
+	// ErrNoLock is an error definition describing an address without a vesting schedule.
+	ErrNoLock = errors.New("no vesting schedule for address")
+)
+
+/* BEGIN EXPORTED METHODS */
+
+// Validate returns every problem found in a schedule for an allocation of a given balance.
+func (schedule *Schedule) Validate(balance *big.Float) []*FieldError {
+	var problems []*FieldError // Init problems buffer
+
+	linear := schedule.Start != nil || schedule.Cliff != 0 || schedule.Duration != 0 // Check is linear
+
+	switch {
+	case linear && len(schedule.Unlocks) > 0:
+		problems = append(problems, &FieldError{Message: "must define either a linear release (start, cliff, duration) or unlocks, not both"}) // Append problem
+	case !linear && len(schedule.Unlocks) == 0:
+		problems = append(problems, &FieldError{Message: "must define a linear release (start, cliff, duration) or unlocks"}) // Append problem
+	case linear:
+		if schedule.Start == nil { // Check no start
+			problems = append(problems, &FieldError{Field: "start", Message: "missing start"}) // Append problem
+		}
+
+		if schedule.Duration <= 0 { // Check no duration
+			problems = append(problems, &FieldError{Field: "duration", Message: "must be positive"}) // Append problem
+		}
+
+		if schedule.Cliff < 0 { // Check negative cliff
+			problems = append(problems, &FieldError{Field: "cliff", Message: "must not be negative"}) // Append problem
+		} else if schedule.Duration > 0 && schedule.Cliff > schedule.Duration { // Check cliff after end
+			problems = append(problems, &FieldError{Field: "cliff", Message: "must not exceed duration"}) // Append problem
+		}
+	default:
+		unlocked := new(big.Float) // Init unlocked buffer
+
+		for i, unlock := range schedule.Unlocks { // Iterate through unlocks
+			field := fmt.Sprintf("unlocks[%d]", i) // Get field
+
+			if unlock.At.IsZero() { // Check no time
+				problems = append(problems, &FieldError{Field: field + ".at", Message: "missing unlock time"}) // Append problem
+			}
+
+			amount, ok := new(big.Float).SetPrec(350).SetString(unlock.Amount) // Parse amount
+
+			switch {
+			case unlock.Amount == "":
+				problems = append(problems, &FieldError{Field: field + ".amount", Message: "missing amount"}) // Append problem
+			case !ok:
+				problems = append(problems, &FieldError{Field: field + ".amount", Message: fmt.Sprintf("%q is not a number", unlock.Amount)}) // Append problem
+			case amount.Sign() <= 0:
+				problems = append(problems, &FieldError{Field: field + ".amount", Message: "must be positive"}) // Append problem
+			default:
+				unlocked.Add(unlocked, amount) // Add to unlocked
+			}
+		}
+
+		if balance != nil && unlocked.Cmp(balance) > 0 { // Check unlocks exceed balance
+			problems = append(problems, &FieldError{Field: "unlocks", Message: fmt.Sprintf("unlocks (%s) exceed balance (%s)", unlocked.Text('f', -1), balance.Text('f', -1))}) // Append problem
+		}
+	}
+
+	return problems // Return problems
+}
+
+// Status computes the unlocked and locked portions of an allocation of a given balance at a given time.
+func (schedule *Schedule) Status(balance *big.Float, at time.Time) *Status {
+	status := &Status{
+		Total:    new(big.Float).SetPrec(350).Set(balance), // Set total
+		Unlocked: new(big.Float).SetPrec(350),              // Init unlocked
+	} // Init status
+
+	if len(schedule.Unlocks) > 0 { // Check fixed unlocks
+		locked := new(big.Float).SetPrec(350) // Init locked buffer
+
+		unlocks := append([]*Unlock{}, schedule.Unlocks...) // Copy unlocks
+
+		sort.SliceStable(unlocks, func(i, j int) bool { return unlocks[i].At.Before(unlocks[j].At) }) // Sort by time
+
+		for _, unlock := range unlocks { // Iterate through unlocks
+			if !unlock.At.After(at) { // Check unlocked
+				continue // Continue
+			}
+
+			amount, _ := new(big.Float).SetPrec(350).SetString(unlock.Amount) // Parse amount
+
+			locked.Add(locked, amount) // Add to locked
+
+			if status.NextUnlock == nil { // Check is next unlock
+				next := unlock.At // Get time
+
+				status.NextUnlock = &next // Set next unlock
+			}
+
+			last := unlock.At // Get time
+
+			status.FullyUnlocked = &last // Set fully unlocked
+		}
+
+		status.Unlocked.Sub(status.Total, locked) // Set unlocked
+	} else if schedule.Start != nil { // Check linear release
+		cliff := schedule.Start.Add(time.Duration(schedule.Cliff))  // Get cliff end
+		end := schedule.Start.Add(time.Duration(schedule.Duration)) // Get release end
+
+		switch {
+		case !at.Before(end):
+			status.Unlocked.Set(status.Total) // Fully unlocked
+		case at.Before(cliff):
+			status.NextUnlock = &cliff  // Set next unlock
+			status.FullyUnlocked = &end // Set fully unlocked
+		default:
+			elapsed := new(big.Float).SetInt64(int64(at.Sub(*schedule.Start))) // Get elapsed
+			duration := new(big.Float).SetInt64(int64(schedule.Duration))      // Get duration
+
+			status.Unlocked.Mul(status.Total, elapsed.Quo(elapsed, duration)) // Set unlocked
+
+			status.FullyUnlocked = &end // Set fully unlocked
+		}
+	} else {
+		status.Unlocked.Set(status.Total) // Fully unlocked
+	}
+
+	status.Locked = new(big.Float).SetPrec(350).Sub(status.Total, status.Unlocked) // Set locked
+
+	return status // Return status
+}
+
+// ParseDuration parses a Go duration (e.g. 720h) or a number of days (e.g. 90d).
+func ParseDuration(s string) (Duration, error) {
+	if strings.HasSuffix(s, "d") { // Check days
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64) // Parse days
+
+		if err != nil { // Check for errors
+			return 0, fmt.Errorf("%s: %q", ErrInvalidDuration, s) // Return error
+		}
+
+		return Duration(days * float64(24*time.Hour)), nil // Return duration
+	}
+
+	duration, err := time.ParseDuration(s) // Parse duration
+
+	if err != nil { // Check for errors
+		return 0, fmt.Errorf("%s: %q", ErrInvalidDuration, s) // Return error
+	}
+
+	return Duration(duration), nil // Return duration
+}
+
+// String formats a duration in days if it is a whole number of days, and as a Go duration otherwise.
+func (duration Duration) String() string {
+	if duration != 0 && time.Duration(duration)%(24*time.Hour) == 0 { // Check whole days
+		return fmt.Sprintf("%dd", time.Duration(duration)/(24*time.Hour)) // Return days
+	}
+
+	return time.Duration(duration).String() // Return duration
+}
+
+// MarshalJSON marshals a duration to a JSON string.
+func (duration Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(duration.String()) // Marshal string
+}
+
+// UnmarshalJSON unmarshals a duration from a JSON string.
+func (duration *Duration) UnmarshalJSON(b []byte) error {
+	var s string // Init string buffer
+
+	err := json.Unmarshal(b, &s) // Unmarshal string
+
+	if err != nil { // Check for errors
+		return ErrInvalidDuration // Return error
+	}
+
+	*duration, err = ParseDuration(s) // Parse duration
+
+	return err // Return error
+}
+
+// UnmarshalJSON strictly unmarshals an unlock from a JSON object, accepting its amount as either a string or a number.
+func (unlock *Unlock) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		At     time.Time       `json:"at"`     // Unlock time
+		Amount json.RawMessage `json:"amount"` // Unlocked amount
+	} // Init raw unlock buffer
+
+	decoder := json.NewDecoder(bytes.NewReader(b)) // Init decoder
+
+	decoder.DisallowUnknownFields() // Reject unknown keys
+
+	err := decoder.Decode(&raw) // Decode unlock
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	unlock.At, unlock.Amount = raw.At, "" // Set time
+
+	if len(raw.Amount) > 0 && raw.Amount[0] == '"' { // Check string amount
+		return json.Unmarshal(raw.Amount, &unlock.Amount) // Unquote amount
+	} else if len(raw.Amount) > 0 && string(raw.Amount) != "null" { // Check numeric amount
+		var number json.Number // Init number buffer
+
+		err = json.Unmarshal(raw.Amount, &number) // Decode number
+
+		unlock.Amount = number.String() // Set amount
+	}
+
+	return err // Return error
+}
+
+// Error formats a field error as field: message.
+func (problem *FieldError) Error() string {
+	if problem.Field == "" { // Check no field
+		return problem.Message // Return message
+	}
+
+	return fmt.Sprintf("%s: %s", problem.Field, problem.Message) // Return formatted
+}
+
+// WriteLocks writes a set of locks to a given file.
+func WriteLocks(path string, locks []*Lock) error {
+	marshaled, err := json.MarshalIndent(locks, "", "  ") // Marshal locks
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return ioutil.WriteFile(path, marshaled, 0644) // Write locks
+}
+
+// ReadLocks reads a set of locks from a given file, returning nil if the file doesn't exist.
+func ReadLocks(path string) ([]*Lock, error) {
+	data, err := ioutil.ReadFile(path) // Read locks
+
+	if os.IsNotExist(err) { // Check no locks
+		return nil, nil // No locks
+	} else if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	var locks []*Lock // Init locks buffer
+
+	err = json.Unmarshal(data, &locks) // Unmarshal locks
+
+	return locks, err // Return locks
+}
+
+// FindLock finds the lock of a given address in a set of locks.
+func FindLock(locks []*Lock, address string) (*Lock, error) {
+	for _, lock := range locks { // Iterate through locks
+		if strings.EqualFold(lock.Address, address) { // Check matches
+			return lock, nil // Return lock
+		}
+	}
+
+	return nil, fmt.Errorf("%s %s", ErrNoLock, address) // Return error
+}
+
+/* END EXPORTED METHODS */
@@ -0,0 +1,76 @@
+// Package vesting defines genesis allocation vesting schedules, along with helper methods for computing the unlocked portion of an allocation at a given time.
+package vesting
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+/* BEGIN EXPORTED METHODS TESTS */
+
+// TestStatus tests the functionality of the Status() method.
+func TestStatus(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) // Get start
+
+	linear := &Schedule{Start: &start, Cliff: Duration(100 * time.Hour), Duration: Duration(400 * time.Hour)} // Init linear release
+
+	for hours, expected := range map[int]string{-1: "0", 99: "0", 100: "25", 200: "50", 400: "100", 500: "100"} { // Iterate through expected unlocked amounts
+		status := linear.Status(big.NewFloat(100), start.Add(time.Duration(hours)*time.Hour)) // Get status
+
+		if status.Unlocked.Text('f', -1) != expected { // Check invalid unlocked amount
+			t.Fatalf("expected %s unlocked after %d hours, found %s", expected, hours, status.Unlocked.Text('f', -1)) // Panic
+		}
+	}
+
+	fixed := &Schedule{Unlocks: []*Unlock{{At: start.Add(48 * time.Hour), Amount: "30"}, {At: start.Add(24 * time.Hour), Amount: "20"}}} // Init fixed unlocks
+
+	status := fixed.Status(big.NewFloat(100), start.Add(36*time.Hour)) // Get status
+
+	if status.Unlocked.Text('f', -1) != "70" || status.Locked.Text('f', -1) != "30" || !status.NextUnlock.Equal(start.Add(48*time.Hour)) { // Check invalid status
+		t.Fatalf("expected 70 unlocked and 30 locked until the second unlock, found %s and %s", status.Unlocked.Text('f', -1), status.Locked.Text('f', -1)) // Panic
+	}
+}
+
+// TestValidate tests the functionality of the Validate() method.
+func TestValidate(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) // Get start
+
+	if problems := (&Schedule{Start: &start, Duration: Duration(time.Hour)}).Validate(big.NewFloat(1)); len(problems) != 0 { // Check valid schedule rejected
+		t.Fatalf("expected no problems, found %v", problems) // Panic
+	}
+
+	for _, schedule := range []*Schedule{
+		{},
+		{Duration: Duration(time.Hour)},
+		{Start: &start, Cliff: Duration(2 * time.Hour), Duration: Duration(time.Hour)},
+		{Start: &start, Duration: Duration(time.Hour), Unlocks: []*Unlock{{At: start, Amount: "1"}}},
+		{Unlocks: []*Unlock{{At: start, Amount: "2"}}},
+		{Unlocks: []*Unlock{{Amount: "1"}}},
+	} { // Iterate through invalid schedules
+		if problems := schedule.Validate(big.NewFloat(1)); len(problems) == 0 { // Check invalid schedule accepted
+			t.Fatalf("expected problems with schedule %+v", schedule) // Panic
+		}
+	}
+}
+
+// TestParseDuration tests the functionality of the ParseDuration() method.
+func TestParseDuration(t *testing.T) {
+	for s, expected := range map[string]time.Duration{"90d": 90 * 24 * time.Hour, "1.5d": 36 * time.Hour, "720h": 720 * time.Hour} { // Iterate through durations
+		duration, err := ParseDuration(s) // Parse duration
+
+		if err != nil { // Check for errors
+			t.Fatal(err) // Panic
+		}
+
+		if time.Duration(duration) != expected { // Check invalid duration
+			t.Fatalf("expected %s for %s, found %s", expected, s, time.Duration(duration)) // Panic
+		}
+	}
+
+	if _, err := ParseDuration("soon"); err == nil { // Check invalid duration accepted
+		t.Fatal("invalid durations must be rejected") // Panic
+	}
+}
+
+/* END EXPORTED METHODS TESTS */